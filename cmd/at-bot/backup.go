@@ -0,0 +1,116 @@
+package main
+
+import (
+	"at-bot/internal/db/sqlite"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// backupDir はバックアップの保存先
+const backupDir = "./data/backups"
+
+// runCommand はサブコマンドを実行する
+func runCommand(name string, args []string) error {
+	var err error
+	switch name {
+	case "backup":
+		err = runBackup(args)
+	case "restore":
+		err = runRestore(args)
+	default:
+		return fmt.Errorf("unknown command: %s (available: backup, restore)", name)
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
+}
+
+// runBackup はBotの稼働中でもデータベースのバックアップを作成する
+func runBackup(args []string) error {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	out := flags.String("out", "", "バックアップの出力先 (既定: "+backupDir+"/bot-<日時>.db)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := openSQLite()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	path := *out
+	if path == "" {
+		path = filepath.Join(backupDir, sqlite.BackupFileName(time.Now()))
+	}
+	if err := sqlite.Backup(context.Background(), db, path); err != nil {
+		return err
+	}
+	log.Printf("[BACKUP] database backed up to %s", path)
+	return nil
+}
+
+// runRestore はバックアップからデータベースを復元する
+// 復元前のデータベースはbackupDirに退避する
+func runRestore(args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	in := flags.String("in", "", "復元するバックアップファイル")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" {
+		return errors.New("restore requires -in <backup file>")
+	}
+
+	// 稼働中のBotの書き込みが失われないよう、他の接続を排除して開く
+	// 退避するファイルが復元前の状態のままになるよう、マイグレーションは復元後に行う
+	if err := requireSQLite(); err != nil {
+		return err
+	}
+	db, err := sqlite.OpenExclusive(sqliteConfig())
+	if errors.Is(err, sqlite.ErrDatabaseInUse) {
+		return fmt.Errorf("stop the bot before restoring: %w", err)
+	}
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	// 退避したファイルは古いバックアップの削除対象にならないよう別の名前にする
+	saved := filepath.Join(backupDir, "before-restore-"+sqlite.BackupFileName(time.Now()))
+	if err := sqlite.Backup(ctx, db, saved); err != nil {
+		return fmt.Errorf("failed to save current database: %w", err)
+	}
+	log.Printf("[BACKUP] current database saved to %s", saved)
+
+	if err := sqlite.Restore(ctx, db, *in); err != nil {
+		return err
+	}
+	log.Printf("[BACKUP] database restored from %s", *in)
+	return nil
+}
+
+// openSQLite はサブコマンドで操作するSQLiteのデータベースを開く
+func openSQLite() (*sql.DB, error) {
+	if err := requireSQLite(); err != nil {
+		return nil, err
+	}
+	return sqlite.InitDB(sqliteConfig())
+}
+
+// requireSQLite はSQLiteを使用する設定であることを確認する
+func requireSQLite() error {
+	if os.Getenv("DATABASE_URL") != "" {
+		return errors.New("backup and restore are only supported for SQLite, use the database tools for DATABASE_URL")
+	}
+	return nil
+}
@@ -1,14 +1,24 @@
 package main
 
 import (
+	"at-bot/internal/db/inmemory"
+	"at-bot/internal/db/postgres"
 	"at-bot/internal/db/sqlite"
 	"at-bot/internal/dice"
 	"at-bot/internal/discord"
 	"at-bot/internal/handler"
 	"at-bot/internal/recruit"
 	"at-bot/internal/shutdown"
+	"at-bot/internal/stats"
+	"at-bot/internal/uow"
+	"context"
+	"database/sql"
 	"log"
 	"os"
+	"strconv"
+	"time"
+	// 実行環境にタイムゾーンのデータがなくても、TZの時刻で開始時刻を解釈できるように埋め込む
+	_ "time/tzdata"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/joho/godotenv"
@@ -17,38 +27,89 @@ import (
 func main() {
 	_ = godotenv.Load(".env")
 
-	db, err := sqlite.InitDB("./data/bot.db")
-	if err != nil {
-		log.Fatalf("[INIT] failed to initialize database: %v", err)
+	// サブコマンド
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			log.Fatalf("[CLI] %v", err)
+		}
+		return
 	}
-	defer db.Close()
+
+	lifecycle := shutdown.NewManager()
 
 	// infra
-	recruitRepo := sqlite.NewRecruitRepository(db)
-	participantRepos := sqlite.NewParticipantRepository(db)
-	txManager := sqlite.NewTxManager(db)
+	repos, err := openRepositories(os.Getenv("DATABASE_URL"), sqliteConfig())
+	if err != nil {
+		log.Fatalf("[INIT] failed to initialize database: %v", err)
+	}
+	lifecycle.OnClose("database", repos.close)
 	// usecase
-	recruitUsecase := recruit.NewRecruitUsecase(recruitRepo, participantRepos, txManager)
-	diceUsecase := dice.NewDiceUsecase()
+	recruitUsecase := recruit.NewRecruitUsecase(repos.recruit, repos.participant, repos.txManager)
+	statsUsecase := stats.NewStatsUsecase(repos.stats, repos.txManager)
+	diceUsecase := dice.NewDiceUsecase(dice.NewCryptoSource(), repos.roll, repos.txManager)
 	// handler
 	openSlashCmd := handler.NewOpenRecruitSlashCommand(recruitUsecase)
+	openModalCmd := handler.NewOpenRecruitModalCommand(recruitUsecase)
+	listSlashCmd := handler.NewListRecruitsSlashCommand(recruitUsecase)
 	joinCmd := handler.NewJoinRecruitCommand(recruitUsecase)
 	declineCmd := handler.NewDeclineRecruitCommand(recruitUsecase)
+	tentativeCmd := handler.NewTentativeRecruitCommand(recruitUsecase)
 	cancelCmd := handler.NewCancelRecruitCommand(recruitUsecase)
 	closeCmd := handler.NewCloseRecruitCommand(recruitUsecase)
+	reopenCmd := handler.NewReopenRecruitCommand(recruitUsecase)
+	manageCmd := handler.NewManageRecruitCommand(recruitUsecase)
+	capacityModalCmd := handler.NewCapacityModalCommand()
+	changeCapacityCmd := handler.NewChangeCapacityCommand(recruitUsecase)
+	kickCmd := handler.NewKickParticipantCommand(recruitUsecase)
+	deleteCmd := handler.NewDeleteRecruitCommand(recruitUsecase)
+	pickCmd := handler.NewPickMemberCommand(recruitUsecase, diceUsecase)
+	teamsModalCmd := handler.NewTeamsModalCommand()
+	splitTeamsCmd := handler.NewSplitTeamsCommand(recruitUsecase, diceUsecase)
+	statsCmd := handler.NewStatsSlashCommand(statsUsecase)
 	diceCmd := handler.NewDiceSlashCommand(diceUsecase)
 	versionCmd := handler.NewVersionSlashCommand()
+	// scheduler
+	recruitScheduler := handler.NewRecruitScheduler(
+		recruitUsecase,
+		envDuration("RECRUIT_REMIND_MINUTES", 10, time.Minute),
+		envDuration("RECRUIT_TTL_HOURS", 24, time.Hour),
+	)
+
+	interactionPool := discord.NewWorkerPool(
+		envInt("INTERACTION_WORKERS", 8),
+		envInt("INTERACTION_QUEUE_SIZE", 100),
+	)
+	lifecycle.OnClose("interaction pool", interactionPool.Close)
 
 	interactionDispatcher := &discord.InteractionDispatcher{
 		Listeners: []discord.InteractionListener{
 			joinCmd,
 			declineCmd,
+			tentativeCmd,
 			cancelCmd,
 			closeCmd,
+			reopenCmd,
+			manageCmd,
+			capacityModalCmd,
+			changeCapacityCmd,
+			kickCmd,
+			deleteCmd,
+			pickCmd,
+			teamsModalCmd,
+			splitTeamsCmd,
 			openSlashCmd,
+			openModalCmd,
+			listSlashCmd,
+			statsCmd,
 			diceCmd,
 			versionCmd,
 		},
+		Gate:         lifecycle,
+		BaseContext:  lifecycle.TaskContext(),
+		Pool:         interactionPool,
+		SerialKey:    handler.RecruitMessageKey,
+		PanicMessage: handler.ErrorMessageContent,
+		BusyMessage:  handler.BusyMessageContent,
 	}
 
 	config, err := discord.
@@ -56,8 +117,12 @@ func main() {
 			discord.WithToken(os.Getenv("DISCORD_BOT_TOKEN")),
 			discord.WithIntent(discordgo.IntentGuildMessages),
 			discord.WithIntent(discordgo.IntentMessageContent),
+			// 同じ募集への操作を受け付けた順にプールへ渡す
+			discord.WithSyncEvents(),
 			discord.WithInteractionCreateHandler(interactionDispatcher.OnInteractionCreate),
 			discord.WithSlashCommand(openSlashCmd),
+			discord.WithSlashCommand(listSlashCmd),
+			discord.WithSlashCommand(statsCmd),
 			discord.WithSlashCommand(diceCmd),
 			discord.WithSlashCommand(versionCmd),
 		)
@@ -70,8 +135,126 @@ func main() {
 	if err := sm.Open(config); err != nil {
 		log.Fatalf("[INIT] failed to connect to Discord: %v", err)
 	}
-	defer sm.Close()
+	lifecycle.OnClose("discord session", sm.Close)
+
+	ctx := lifecycle.Context()
+	lifecycle.Go(func() { recruitScheduler.Run(ctx, sm.Session(), time.Minute) })
+	if interval := envDuration("SQLITE_BACKUP_INTERVAL_HOURS", 0, time.Hour); interval > 0 && repos.sqlite != nil {
+		backupScheduler := sqlite.NewBackupScheduler(repos.sqlite, backupDir, max(envInt("SQLITE_BACKUP_RETENTION", 7), 1))
+		lifecycle.Go(func() { backupScheduler.Run(ctx, interval) })
+	}
 
 	log.Println("[INIT] discord bot started successfully")
 	shutdown.WaitForExitSignal()
+
+	// 処理中のインタラクションの完了を待ってから、Discordのセッション、データベースの順に閉じる
+	log.Println("[SHUTDOWN] shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), envDuration("SHUTDOWN_TIMEOUT_SECONDS", 8, time.Second))
+	defer cancel()
+	if err := lifecycle.Shutdown(shutdownCtx); err != nil {
+		log.Printf("[SHUTDOWN] %v", err)
+	}
+}
+
+// memoryDatabaseURL はメモリ上で動作させる場合のDATABASE_URL
+const memoryDatabaseURL = "memory://"
+
+// repositories はデータベースの種類に応じたリポジトリ実装の組
+type repositories struct {
+	close       func() error
+	sqlite      *sql.DB // SQLiteを使用している場合のみ設定される
+	recruit     recruit.RecruitRepository
+	participant recruit.ParticipantRepository
+	stats       stats.StatsRepository
+	roll        dice.RollRepository
+	txManager   uow.UnitOfWork
+}
+
+// openRepositories はdatabaseURLが指定されている場合はPostgreSQLに、未指定の場合はSQLiteに接続する
+// 複数のレプリカで同じデータベースを共有する場合はPostgreSQLを使用する
+// memoryDatabaseURLの場合はデータを保存せずメモリ上で動作する
+func openRepositories(databaseURL string, sqliteConfig sqlite.Config) (*repositories, error) {
+	if databaseURL == memoryDatabaseURL {
+		store := inmemory.NewStore()
+		log.Println("[INIT] using in-memory database, data will be lost on exit")
+		return &repositories{
+			close:       func() error { return nil },
+			recruit:     inmemory.NewRecruitRepository(store),
+			participant: inmemory.NewParticipantRepository(store),
+			stats:       inmemory.NewStatsRepository(store),
+			roll:        inmemory.NewRollRepository(store),
+			txManager:   inmemory.NewTxManager(store),
+		}, nil
+	}
+
+	if databaseURL != "" {
+		db, err := postgres.InitDB(databaseURL)
+		if err != nil {
+			return nil, err
+		}
+		log.Println("[INIT] using PostgreSQL database")
+		return &repositories{
+			close:       db.Close,
+			recruit:     postgres.NewRecruitRepository(db),
+			participant: postgres.NewParticipantRepository(db),
+			stats:       postgres.NewStatsRepository(db),
+			roll:        postgres.NewRollRepository(db),
+			txManager:   postgres.NewTxManager(db),
+		}, nil
+	}
+
+	db, err := sqlite.InitDB(sqliteConfig)
+	if err != nil {
+		return nil, err
+	}
+	log.Println("[INIT] using SQLite database")
+	return &repositories{
+		close:       db.Close,
+		sqlite:      db,
+		recruit:     sqlite.NewRecruitRepository(db),
+		participant: sqlite.NewParticipantRepository(db),
+		stats:       sqlite.NewStatsRepository(db),
+		roll:        sqlite.NewRollRepository(db),
+		txManager:   sqlite.NewTxManager(db),
+	}, nil
+}
+
+// sqliteConfig は環境変数からSQLiteの接続設定を読み込む
+func sqliteConfig() sqlite.Config {
+	config := sqlite.DefaultConfig("./data/bot.db")
+	config.JournalMode = envString("SQLITE_JOURNAL_MODE", config.JournalMode)
+	config.Synchronous = envString("SQLITE_SYNCHRONOUS", config.Synchronous)
+	config.BusyTimeout = envDuration("SQLITE_BUSY_TIMEOUT_MS", int(config.BusyTimeout.Milliseconds()), time.Millisecond)
+	config.MaxOpenConns = envInt("SQLITE_MAX_OPEN_CONNS", config.MaxOpenConns)
+	return config
+}
+
+// envString は環境変数から設定値を読み込む
+// 未設定の場合はfallbackを使用する
+func envString(key string, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// envInt は環境変数から0以上の設定値を読み込む
+// 未設定または不正な値の場合はfallbackを使用する
+func envInt(key string, fallback int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		log.Printf("[INIT] invalid %s: %q, using default %d", key, v, fallback)
+		return fallback
+	}
+	return n
+}
+
+// envDuration は環境変数からunit単位の設定値を読み込む
+// 未設定または不正な値の場合はfallbackを使用する
+func envDuration(key string, fallback int, unit time.Duration) time.Duration {
+	return time.Duration(envInt(key, fallback)) * unit
 }
@@ -0,0 +1,26 @@
+package inmemory
+
+import (
+	"at-bot/internal/dice"
+	"at-bot/internal/dice/dicetest"
+	"at-bot/internal/recruit/recruittest"
+	"testing"
+)
+
+func TestRecruitRepositoryConformance(t *testing.T) {
+	recruittest.Run(t, func(t *testing.T) recruittest.Backend {
+		store := NewStore()
+
+		return recruittest.Backend{
+			Recruits:     NewRecruitRepository(store),
+			Participants: NewParticipantRepository(store),
+			UnitOfWork:   NewTxManager(store),
+		}
+	})
+}
+
+func TestRollRepositoryConformance(t *testing.T) {
+	dicetest.Run(t, func(t *testing.T) dice.RollRepository {
+		return NewRollRepository(NewStore())
+	})
+}
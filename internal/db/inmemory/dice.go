@@ -0,0 +1,111 @@
+package inmemory
+
+import (
+	"at-bot/internal/dice"
+	"context"
+	"sort"
+)
+
+func copyRoll(record *dice.RollRecord) *dice.RollRecord {
+	copied := *record
+	copied.Dice = append([]dice.RecordedDie(nil), record.Dice...)
+	return &copied
+}
+
+type inmemoryRollRepository struct {
+	store *Store
+}
+
+func NewRollRepository(store *Store) dice.RollRepository {
+	return &inmemoryRollRepository{
+		store: store,
+	}
+}
+
+func (r *inmemoryRollRepository) Create(ctx context.Context, record *dice.RollRecord) (dice.RollID, error) {
+	var id dice.RollID
+	err := r.store.access(ctx, func(d *data) error {
+		d.lastRollID++
+		id = d.lastRollID
+
+		created := copyRoll(record)
+		created.ID = id
+		d.rolls = append(d.rolls, created)
+		return nil
+	})
+	return id, err
+}
+
+func (r *inmemoryRollRepository) ListRecent(
+	ctx context.Context,
+	guildID dice.GuildID,
+	userID dice.UserID,
+	limit int,
+) ([]*dice.RollRecord, error) {
+	var records []*dice.RollRecord
+	err := r.store.access(ctx, func(d *data) error {
+		for _, record := range d.rolls {
+			if record.Roller.GuildID == guildID && record.Roller.UserID == userID {
+				records = append(records, copyRoll(record))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].CreatedAt.Equal(records[j].CreatedAt) {
+			return records[i].CreatedAt.After(records[j].CreatedAt)
+		}
+		return records[i].ID > records[j].ID
+	})
+	return truncate(records, limit), nil
+}
+
+func (r *inmemoryRollRepository) CountFaces(
+	ctx context.Context,
+	guildID dice.GuildID,
+	userID dice.UserID,
+) ([]dice.FaceCount, error) {
+	type face struct {
+		sides int
+		value int
+	}
+	counts := make(map[face]int)
+	sidesTotal := make(map[int]int)
+
+	err := r.store.access(ctx, func(d *data) error {
+		for _, record := range d.rolls {
+			if record.Roller.GuildID != guildID || record.Roller.UserID != userID || record.Secret {
+				continue
+			}
+			for _, die := range record.Dice {
+				counts[face{sides: die.Sides, value: die.Value}]++
+				sidesTotal[die.Sides]++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dice.FaceCount
+	for f, count := range counts {
+		result = append(result, dice.FaceCount{Sides: f.sides, Value: f.value, Count: count})
+	}
+	// 振ったダイスの個数が多い面数の順、同じ面数の中では出目の順に並べる
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if sidesTotal[a.Sides] != sidesTotal[b.Sides] {
+			return sidesTotal[a.Sides] > sidesTotal[b.Sides]
+		}
+		if a.Sides != b.Sides {
+			return a.Sides < b.Sides
+		}
+		return a.Value < b.Value
+	})
+	return result, nil
+}
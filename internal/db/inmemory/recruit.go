@@ -0,0 +1,294 @@
+package inmemory
+
+import (
+	"at-bot/internal/recruit"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
+
+// copyRecruit は呼び出し側の変更がStoreに影響しないよう募集を複製する
+func copyRecruit(state *recruit.RecruitState) *recruit.RecruitState {
+	copied := *state
+	copied.StartAt = copyTime(state.StartAt)
+	copied.RemindedAt = copyTime(state.RemindedAt)
+	copied.ReopenedAt = copyTime(state.ReopenedAt)
+	copied.UpdatedAt = copyTime(state.UpdatedAt)
+	return &copied
+}
+
+func copyParticipant(p *recruit.Participant) *recruit.Participant {
+	copied := *p
+	copied.UpdatedAt = copyTime(p.UpdatedAt)
+	return &copied
+}
+
+type inmemoryRecruitRepository struct {
+	store *Store
+}
+
+func NewRecruitRepository(store *Store) recruit.RecruitRepository {
+	return &inmemoryRecruitRepository{
+		store: store,
+	}
+}
+
+func (r *inmemoryRecruitRepository) Get(ctx context.Context, id recruit.RecruitID) (*recruit.RecruitState, error) {
+	var state *recruit.RecruitState
+	err := r.store.access(ctx, func(d *data) error {
+		found, ok := d.recruits[id]
+		if !ok {
+			return fmt.Errorf("recruit not found: %d", id)
+		}
+		state = copyRecruit(found)
+		return nil
+	})
+	return state, err
+}
+
+func (r *inmemoryRecruitRepository) GetByMessage(
+	ctx context.Context,
+	channelID recruit.ChannelID,
+	messageID recruit.MessageID,
+) (*recruit.RecruitState, error) {
+	var state *recruit.RecruitState
+	err := r.store.access(ctx, func(d *data) error {
+		for _, found := range sortedRecruits(d) {
+			// 作成者が削除した募集はアーカイブとして残っているが、メッセージからは取得できない
+			if found.ChannelID == channelID && found.MessageID == messageID &&
+				found.CloseReason != recruit.CloseReasonDeleted {
+				state = copyRecruit(found)
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %s, %s", recruit.ErrRecruitNotFound, channelID, messageID)
+	})
+	return state, err
+}
+
+func (r *inmemoryRecruitRepository) Create(ctx context.Context, state *recruit.RecruitState) (recruit.RecruitID, error) {
+	var id recruit.RecruitID
+	err := r.store.access(ctx, func(d *data) error {
+		d.lastRecruitID++
+		id = d.lastRecruitID
+
+		// 作成時に保存しない項目はSQLの実装と同様に既定値にする
+		created := copyRecruit(state)
+		created.ID = id
+		created.RemindedAt = nil
+		created.CloseReason = recruit.CloseReasonNone
+		created.ReopenedAt = nil
+		created.UpdatedAt = nil
+		d.recruits[id] = created
+		return nil
+	})
+	return id, err
+}
+
+func (r *inmemoryRecruitRepository) Update(ctx context.Context, state *recruit.RecruitState) error {
+	return r.store.access(ctx, func(d *data) error {
+		existing, ok := d.recruits[state.ID]
+		if !ok {
+			return fmt.Errorf("recruit not found: %d", state.ID)
+		}
+
+		now := time.Now()
+		updated := copyRecruit(state)
+		// 作成日時は更新しない
+		updated.CreatedAt = existing.CreatedAt
+		updated.UpdatedAt = &now
+		d.recruits[state.ID] = updated
+		return nil
+	})
+}
+
+func (r *inmemoryRecruitRepository) Delete(ctx context.Context, id recruit.RecruitID) error {
+	return r.store.access(ctx, func(d *data) error {
+		if _, ok := d.recruits[id]; !ok {
+			return fmt.Errorf("recruit not found: %d", id)
+		}
+		delete(d.recruits, id)
+		// 外部キー制約のON DELETE CASCADEと同様に参加者も削除する
+		delete(d.participants, id)
+		delete(d.participantEvents, id)
+		return nil
+	})
+}
+
+func (r *inmemoryRecruitRepository) ListScheduled(ctx context.Context, until time.Time) ([]*recruit.RecruitState, error) {
+	states, err := r.list(ctx, func(state *recruit.RecruitState) bool {
+		return state.Status == recruit.RecruitStatusOpened &&
+			state.StartAt != nil &&
+			!state.StartAt.After(until)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(states, func(i, j int) bool {
+		return states[i].StartAt.Before(*states[j].StartAt)
+	})
+	return states, nil
+}
+
+func (r *inmemoryRecruitRepository) ListExpired(ctx context.Context, before time.Time) ([]*recruit.RecruitState, error) {
+	states, err := r.list(ctx, func(state *recruit.RecruitState) bool {
+		if state.Status == recruit.RecruitStatusClosed {
+			return false
+		}
+		latest := state.CreatedAt
+		for _, t := range []*time.Time{state.StartAt, state.ReopenedAt} {
+			if t != nil && t.After(latest) {
+				latest = *t
+			}
+		}
+		return !latest.After(before)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortByCreatedAt(states)
+	return states, nil
+}
+
+func (r *inmemoryRecruitRepository) ListOpened(
+	ctx context.Context,
+	guildID recruit.GuildID,
+	channelID recruit.ChannelID,
+) ([]*recruit.RecruitState, error) {
+	states, err := r.list(ctx, func(state *recruit.RecruitState) bool {
+		return state.GuildID == guildID &&
+			(state.Status == recruit.RecruitStatusOpened || state.Status == recruit.RecruitStatusStarted) &&
+			(channelID == "" || state.ChannelID == channelID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortByCreatedAt(states)
+	return states, nil
+}
+
+// list は条件に一致する募集の複製をID順に返す
+func (r *inmemoryRecruitRepository) list(
+	ctx context.Context,
+	match func(state *recruit.RecruitState) bool,
+) ([]*recruit.RecruitState, error) {
+	var states []*recruit.RecruitState
+	err := r.store.access(ctx, func(d *data) error {
+		for _, state := range sortedRecruits(d) {
+			if match(state) {
+				states = append(states, copyRecruit(state))
+			}
+		}
+		return nil
+	})
+	return states, err
+}
+
+// sortedRecruits は募集を作成順(ID順)に返す
+func sortedRecruits(d *data) []*recruit.RecruitState {
+	states := make([]*recruit.RecruitState, 0, len(d.recruits))
+	for _, state := range d.recruits {
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].ID < states[j].ID
+	})
+	return states
+}
+
+func sortByCreatedAt(states []*recruit.RecruitState) {
+	sort.SliceStable(states, func(i, j int) bool {
+		return states[i].CreatedAt.Before(states[j].CreatedAt)
+	})
+}
+
+type inmemoryParticipantRepository struct {
+	store *Store
+}
+
+func NewParticipantRepository(store *Store) recruit.ParticipantRepository {
+	return &inmemoryParticipantRepository{
+		store: store,
+	}
+}
+
+func (r *inmemoryParticipantRepository) Upsert(ctx context.Context, recruitID recruit.RecruitID, userID recruit.UserID, status recruit.ParticipantStatus) error {
+	return r.store.access(ctx, func(d *data) error {
+		// 外部キー制約と同様に存在しない募集への登録はエラー
+		if _, ok := d.recruits[recruitID]; !ok {
+			return fmt.Errorf("failed to upsert participant: recruit not found: %d", recruitID)
+		}
+
+		now := time.Now()
+		for _, p := range d.participants[recruitID] {
+			if p.UserID == userID {
+				// SQLの実装と同様に参加状態が変わる場合は変更前の状態とともに履歴に記録する
+				if p.Status != status {
+					d.participantEvents[recruitID] = append(d.participantEvents[recruitID],
+						participantEvent{userID: userID, from: p.Status, to: status})
+				}
+				p.Status = status
+				p.UpdatedAt = &now
+				return nil
+			}
+		}
+
+		d.participantEvents[recruitID] = append(d.participantEvents[recruitID],
+			participantEvent{userID: userID, to: status})
+		d.participants[recruitID] = append(d.participants[recruitID], &recruit.Participant{
+			RecruitID: recruitID,
+			UserID:    userID,
+			Status:    status,
+			CreatedAt: now,
+			UpdatedAt: copyTime(&now),
+		})
+		return nil
+	})
+}
+
+func (r *inmemoryParticipantRepository) FindByRecruitAndUser(ctx context.Context, recruitID recruit.RecruitID, userID recruit.UserID) (*recruit.Participant, error) {
+	var participant *recruit.Participant
+	err := r.store.access(ctx, func(d *data) error {
+		for _, p := range d.participants[recruitID] {
+			if p.UserID == userID {
+				participant = copyParticipant(p)
+				return nil
+			}
+		}
+		return nil
+	})
+	return participant, err
+}
+
+func (r *inmemoryParticipantRepository) List(ctx context.Context, recruitID recruit.RecruitID) ([]recruit.Participant, error) {
+	var participants []recruit.Participant
+	err := r.store.access(ctx, func(d *data) error {
+		for _, p := range d.participants[recruitID] {
+			participants = append(participants, *copyParticipant(p))
+		}
+		return nil
+	})
+	return participants, err
+}
+
+func (r *inmemoryParticipantRepository) DeleteAll(ctx context.Context, recruitID recruit.RecruitID) error {
+	return r.store.access(ctx, func(d *data) error {
+		if len(d.participants[recruitID]) == 0 {
+			return fmt.Errorf("participants not found: %d", recruitID)
+		}
+		delete(d.participants, recruitID)
+		return nil
+	})
+}
@@ -0,0 +1,222 @@
+package inmemory
+
+import (
+	"at-bot/internal/recruit"
+	"at-bot/internal/stats"
+	"context"
+	"sort"
+)
+
+type inmemoryStatsRepository struct {
+	store *Store
+}
+
+func NewStatsRepository(store *Store) stats.StatsRepository {
+	return &inmemoryStatsRepository{
+		store: store,
+	}
+}
+
+func (r *inmemoryStatsRepository) GetUserStats(
+	ctx context.Context,
+	guildID recruit.GuildID,
+	userID recruit.UserID,
+	limit int,
+) (*stats.UserStats, error) {
+	result := &stats.UserStats{
+		GuildID: guildID,
+		UserID:  userID,
+	}
+
+	err := r.store.access(ctx, func(d *data) error {
+		coCounts := make(map[recruit.UserID]int)
+		for _, state := range guildRecruits(d, guildID) {
+			if state.AuthorID == userID {
+				result.Opened++
+			}
+
+			if c, ok := countAnswers(d, state)[userID]; ok {
+				result.Joined += c.joined
+				result.Declined += c.declined
+				result.Canceled += c.canceled
+			}
+
+			participants := d.participants[state.ID]
+
+			// 双方が参加している募集を共通の参加として数える
+			if !hasJoined(participants, userID) {
+				continue
+			}
+			for _, p := range participants {
+				if p.UserID != userID && p.Status == recruit.ParticipantStatusJoined {
+					coCounts[p.UserID]++
+				}
+			}
+		}
+
+		for u, count := range coCounts {
+			result.CoParticipants = append(result.CoParticipants, stats.CoParticipant{UserID: u, Count: count})
+		}
+		sort.Slice(result.CoParticipants, func(i, j int) bool {
+			a, b := result.CoParticipants[i], result.CoParticipants[j]
+			if a.Count != b.Count {
+				return a.Count > b.Count
+			}
+			return a.UserID < b.UserID
+		})
+		result.CoParticipants = truncate(result.CoParticipants, limit)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *inmemoryStatsRepository) GetGuildStats(
+	ctx context.Context,
+	guildID recruit.GuildID,
+	limit int,
+) (*stats.GuildStats, error) {
+	result := &stats.GuildStats{
+		GuildID: guildID,
+	}
+
+	err := r.store.access(ctx, func(d *data) error {
+		members := make(map[recruit.UserID]*stats.MemberStats)
+		pairCounts := make(map[[2]recruit.UserID]int)
+		for _, state := range guildRecruits(d, guildID) {
+			result.Recruits++
+
+			for u, c := range countAnswers(d, state) {
+				result.Joined += c.joined
+				result.Declined += c.declined
+				result.Canceled += c.canceled
+
+				m, ok := members[u]
+				if !ok {
+					m = &stats.MemberStats{UserID: u}
+					members[u] = m
+				}
+				m.Joined += c.joined
+				m.Canceled += c.canceled
+			}
+
+			participants := d.participants[state.ID]
+
+			// 同じ組を二重に数えないようユーザーIDの大小で向きを揃える
+			for _, a := range participants {
+				for _, b := range participants {
+					if a.UserID < b.UserID &&
+						a.Status == recruit.ParticipantStatusJoined &&
+						b.Status == recruit.ParticipantStatusJoined {
+						pairCounts[[2]recruit.UserID{a.UserID, b.UserID}]++
+					}
+				}
+			}
+		}
+
+		for _, m := range members {
+			if m.Joined+m.Canceled > 0 {
+				result.TopMembers = append(result.TopMembers, *m)
+			}
+		}
+		sort.Slice(result.TopMembers, func(i, j int) bool {
+			a, b := result.TopMembers[i], result.TopMembers[j]
+			if a.Joined != b.Joined {
+				return a.Joined > b.Joined
+			}
+			if a.Canceled != b.Canceled {
+				return a.Canceled < b.Canceled
+			}
+			return a.UserID < b.UserID
+		})
+		result.TopMembers = truncate(result.TopMembers, limit)
+
+		for pair, count := range pairCounts {
+			result.TopPairs = append(result.TopPairs, stats.CoParticipantPair{UserA: pair[0], UserB: pair[1], Count: count})
+		}
+		sort.Slice(result.TopPairs, func(i, j int) bool {
+			a, b := result.TopPairs[i], result.TopPairs[j]
+			if a.Count != b.Count {
+				return a.Count > b.Count
+			}
+			if a.UserA != b.UserA {
+				return a.UserA < b.UserA
+			}
+			return a.UserB < b.UserB
+		})
+		result.TopPairs = truncate(result.TopPairs, limit)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// guildRecruits はギルド内の募集を作成順に返す
+func guildRecruits(d *data, guildID recruit.GuildID) []*recruit.RecruitState {
+	var states []*recruit.RecruitState
+	for _, state := range sortedRecruits(d) {
+		if state.GuildID == guildID {
+			states = append(states, state)
+		}
+	}
+	return states
+}
+
+func hasJoined(participants []*recruit.Participant, userID recruit.UserID) bool {
+	for _, p := range participants {
+		if p.UserID == userID && p.Status == recruit.ParticipantStatusJoined {
+			return true
+		}
+	}
+	return false
+}
+
+// answerCount は1つの募集に対するユーザーの回答の集計
+type answerCount struct {
+	joined   int
+	declined int
+	canceled int
+}
+
+// countAnswers は作成者以外のユーザーごとに募集に対する回答を集計する
+// 参加は現在参加しているか、辞退は一度でも辞退したか、キャンセルは参加からキャンセルした回数を数える
+func countAnswers(d *data, state *recruit.RecruitState) map[recruit.UserID]*answerCount {
+	counts := make(map[recruit.UserID]*answerCount)
+	get := func(userID recruit.UserID) *answerCount {
+		c, ok := counts[userID]
+		if !ok {
+			c = &answerCount{}
+			counts[userID] = c
+		}
+		return c
+	}
+
+	for _, p := range d.participants[state.ID] {
+		if p.UserID != state.AuthorID && p.Status == recruit.ParticipantStatusJoined {
+			get(p.UserID).joined = 1
+		}
+	}
+	for _, e := range d.participantEvents[state.ID] {
+		if e.userID == state.AuthorID {
+			continue
+		}
+		switch {
+		case e.to == recruit.ParticipantStatusDeclined:
+			get(e.userID).declined = 1
+		case e.from == recruit.ParticipantStatusJoined && e.to == recruit.ParticipantStatusCanceled:
+			get(e.userID).canceled++
+		}
+	}
+	return counts
+}
+
+func truncate[T any](items []T, limit int) []T {
+	if len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,188 @@
+package inmemory
+
+import (
+	"at-bot/internal/recruit"
+	"at-bot/internal/stats"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// seedStatsRecruit は参加者付きの募集を作成する。作成者は参加者として登録される
+// participantsは参加者ごとの回答を回答した順に持つ
+func seedStatsRecruit(
+	t *testing.T,
+	ctx context.Context,
+	recruitRepo recruit.RecruitRepository,
+	participantRepo recruit.ParticipantRepository,
+	guildID recruit.GuildID,
+	authorID recruit.UserID,
+	status recruit.RecruitStatus,
+	participants map[recruit.UserID][]recruit.ParticipantStatus,
+) {
+	t.Helper()
+
+	id, err := recruitRepo.Create(ctx, &recruit.RecruitState{
+		GuildID:     guildID,
+		ChannelID:   "channel-1",
+		MessageID:   recruit.MessageID(time.Now().Format(time.RFC3339Nano)),
+		AuthorID:    authorID,
+		MaxCapacity: 5,
+		Status:      status,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := participantRepo.Upsert(ctx, id, authorID, recruit.ParticipantStatusJoined); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	for userID, answers := range participants {
+		for _, s := range answers {
+			if err := participantRepo.Upsert(ctx, id, userID, s); err != nil {
+				t.Fatalf("Upsert() error = %v", err)
+			}
+		}
+	}
+}
+
+func TestStatsRepository(t *testing.T) {
+	store := NewStore()
+
+	recruitRepo := NewRecruitRepository(store)
+	participantRepo := NewParticipantRepository(store)
+	repo := NewStatsRepository(store)
+	ctx := context.Background()
+
+	seedStatsRecruit(t, ctx, recruitRepo, participantRepo, "guild-1", "author-1", recruit.RecruitStatusClosed,
+		map[recruit.UserID][]recruit.ParticipantStatus{
+			"user-1": {recruit.ParticipantStatusJoined},
+			"user-2": {recruit.ParticipantStatusJoined},
+		})
+	seedStatsRecruit(t, ctx, recruitRepo, participantRepo, "guild-1", "author-1", recruit.RecruitStatusOpened,
+		map[recruit.UserID][]recruit.ParticipantStatus{
+			"user-1": {recruit.ParticipantStatusJoined},
+			"user-2": {recruit.ParticipantStatusJoined, recruit.ParticipantStatusCanceled},
+		})
+	seedStatsRecruit(t, ctx, recruitRepo, participantRepo, "guild-1", "user-1", recruit.RecruitStatusOpened,
+		map[recruit.UserID][]recruit.ParticipantStatus{
+			"user-2": {recruit.ParticipantStatusDeclined},
+			// 作成者に外された参加者はキャンセルとして集計しない
+			"user-3": {recruit.ParticipantStatusJoined, recruit.ParticipantStatusKicked},
+		})
+	// 他のギルドの募集は集計しない
+	seedStatsRecruit(t, ctx, recruitRepo, participantRepo, "guild-2", "author-1", recruit.RecruitStatusOpened,
+		map[recruit.UserID][]recruit.ParticipantStatus{
+			"user-1": {recruit.ParticipantStatusJoined, recruit.ParticipantStatusCanceled},
+		})
+
+	t.Run("ユーザーの集計", func(t *testing.T) {
+		got, err := repo.GetUserStats(ctx, "guild-1", "user-1", 5)
+		if err != nil {
+			t.Fatalf("GetUserStats() error = %v", err)
+		}
+
+		if got.Opened != 1 || got.Joined != 2 || got.Declined != 0 || got.Canceled != 0 {
+			t.Errorf("GetUserStats() = %+v, want opened 1, joined 2", got)
+		}
+		if len(got.CoParticipants) != 2 {
+			t.Fatalf("CoParticipants = %+v, want 2 entries", got.CoParticipants)
+		}
+		if got.CoParticipants[0].UserID != "author-1" || got.CoParticipants[0].Count != 2 {
+			t.Errorf("CoParticipants[0] = %+v, want author-1 x2", got.CoParticipants[0])
+		}
+		if got.CoParticipants[1].UserID != "user-2" || got.CoParticipants[1].Count != 1 {
+			t.Errorf("CoParticipants[1] = %+v, want user-2 x1", got.CoParticipants[1])
+		}
+	})
+
+	t.Run("外された参加者はキャンセルに含めない", func(t *testing.T) {
+		got, err := repo.GetUserStats(ctx, "guild-1", "user-3", 5)
+		if err != nil {
+			t.Fatalf("GetUserStats() error = %v", err)
+		}
+
+		if got.Joined != 0 || got.Declined != 0 || got.Canceled != 0 {
+			t.Errorf("GetUserStats() = %+v, want no participations", got)
+		}
+	})
+
+	t.Run("ギルドの集計", func(t *testing.T) {
+		got, err := repo.GetGuildStats(ctx, "guild-1", 1)
+		if err != nil {
+			t.Fatalf("GetGuildStats() error = %v", err)
+		}
+
+		if got.Recruits != 3 || got.Joined != 3 || got.Declined != 1 || got.Canceled != 1 {
+			t.Errorf("GetGuildStats() = %+v, want recruits 3, joined 3, declined 1, canceled 1", got)
+		}
+		if len(got.TopMembers) != 1 || got.TopMembers[0].UserID != "user-1" || got.TopMembers[0].Joined != 2 {
+			t.Errorf("TopMembers = %+v, want user-1 joined 2", got.TopMembers)
+		}
+		if len(got.TopPairs) != 1 || got.TopPairs[0].UserA != "author-1" || got.TopPairs[0].UserB != "user-1" || got.TopPairs[0].Count != 2 {
+			t.Errorf("TopPairs = %+v, want author-1 & user-1 x2", got.TopPairs)
+		}
+	})
+
+	t.Run("記録がない場合は0件", func(t *testing.T) {
+		got, err := repo.GetUserStats(ctx, "guild-1", "user-9", 5)
+		if err != nil {
+			t.Fatalf("GetUserStats() error = %v", err)
+		}
+		if got.Opened != 0 || got.Joined != 0 || len(got.CoParticipants) != 0 {
+			t.Errorf("GetUserStats() = %+v, want empty", got)
+		}
+	})
+}
+
+func TestStatsRepository_AnswerHistory(t *testing.T) {
+	store := NewStore()
+
+	recruitRepo := NewRecruitRepository(store)
+	participantRepo := NewParticipantRepository(store)
+	repo := NewStatsRepository(store)
+	ctx := context.Background()
+
+	seedStatsRecruit(t, ctx, recruitRepo, participantRepo, "guild-1", "author-1", recruit.RecruitStatusOpened,
+		map[recruit.UserID][]recruit.ParticipantStatus{
+			// 辞退の取り消しは参加のキャンセルではなく、辞退した記録は残る
+			"user-1": {recruit.ParticipantStatusDeclined, recruit.ParticipantStatusCanceled},
+			// 参加し直してもキャンセルした記録は残る
+			"user-2": {recruit.ParticipantStatusJoined, recruit.ParticipantStatusCanceled, recruit.ParticipantStatusJoined},
+			// キャンセル待ちや未定の取り消しは参加のキャンセルではない
+			"user-3": {recruit.ParticipantStatusWaitlisted, recruit.ParticipantStatusCanceled},
+			"user-4": {recruit.ParticipantStatusTentative, recruit.ParticipantStatusCanceled},
+			// キャンセル待ちから繰り上がった後のキャンセルは参加のキャンセル
+			"user-5": {recruit.ParticipantStatusWaitlisted, recruit.ParticipantStatusJoined, recruit.ParticipantStatusCanceled},
+		})
+
+	t.Run("辞退を取り消したユーザー", func(t *testing.T) {
+		got, err := repo.GetUserStats(ctx, "guild-1", "user-1", 5)
+		if err != nil {
+			t.Fatalf("GetUserStats() error = %v", err)
+		}
+
+		if got.Joined != 0 || got.Declined != 1 || got.Canceled != 0 {
+			t.Errorf("GetUserStats() = %+v, want declined 1", got)
+		}
+	})
+
+	t.Run("ギルドの集計", func(t *testing.T) {
+		got, err := repo.GetGuildStats(ctx, "guild-1", 5)
+		if err != nil {
+			t.Fatalf("GetGuildStats() error = %v", err)
+		}
+
+		if got.Joined != 1 || got.Declined != 1 || got.Canceled != 2 {
+			t.Errorf("GetGuildStats() = %+v, want joined 1, declined 1, canceled 2", got)
+		}
+		want := []stats.MemberStats{
+			{UserID: "user-2", Joined: 1, Canceled: 1},
+			{UserID: "user-5", Joined: 0, Canceled: 1},
+		}
+		if !reflect.DeepEqual(got.TopMembers, want) {
+			t.Errorf("TopMembers = %+v, want %+v", got.TopMembers, want)
+		}
+	})
+}
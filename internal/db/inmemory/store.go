@@ -0,0 +1,98 @@
+// Package inmemory はプロセス内のメモリにデータを保持するリポジトリ実装を提供する
+// テストやローカルでの動作確認に使用し、データはプロセスの終了とともに失われる
+package inmemory
+
+import (
+	"at-bot/internal/dice"
+	"at-bot/internal/recruit"
+	"context"
+	"sync"
+)
+
+// Store は全てのリポジトリが共有するデータ
+// 同じStoreから作成したリポジトリとTxManagerを組み合わせて使用する
+type Store struct {
+	mu   sync.Mutex
+	data *data
+}
+
+func NewStore() *Store {
+	return &Store{
+		data: newData(),
+	}
+}
+
+type data struct {
+	recruits      map[recruit.RecruitID]*recruit.RecruitState
+	lastRecruitID recruit.RecruitID
+	// participants は募集ごとの参加者。初回登録順に並ぶ
+	participants map[recruit.RecruitID][]*recruit.Participant
+	// participantEvents は募集ごとの参加状態の変更履歴。変更順に並ぶ
+	participantEvents map[recruit.RecruitID][]participantEvent
+	rolls             []*dice.RollRecord
+	lastRollID        dice.RollID
+}
+
+// participantEvent は参加状態の変更1回分
+// fromが空の場合は初回の回答
+type participantEvent struct {
+	userID recruit.UserID
+	from   recruit.ParticipantStatus
+	to     recruit.ParticipantStatus
+}
+
+func newData() *data {
+	return &data{
+		recruits:          make(map[recruit.RecruitID]*recruit.RecruitState),
+		participants:      make(map[recruit.RecruitID][]*recruit.Participant),
+		participantEvents: make(map[recruit.RecruitID][]participantEvent),
+	}
+}
+
+// clone はロールバック用にデータを複製する
+func (d *data) clone() *data {
+	c := &data{
+		recruits:          make(map[recruit.RecruitID]*recruit.RecruitState, len(d.recruits)),
+		lastRecruitID:     d.lastRecruitID,
+		participants:      make(map[recruit.RecruitID][]*recruit.Participant, len(d.participants)),
+		participantEvents: make(map[recruit.RecruitID][]participantEvent, len(d.participantEvents)),
+		rolls:             make([]*dice.RollRecord, 0, len(d.rolls)),
+		lastRollID:        d.lastRollID,
+	}
+	for id, state := range d.recruits {
+		c.recruits[id] = copyRecruit(state)
+	}
+	for id, participants := range d.participants {
+		copied := make([]*recruit.Participant, 0, len(participants))
+		for _, p := range participants {
+			copied = append(copied, copyParticipant(p))
+		}
+		c.participants[id] = copied
+	}
+	for id, events := range d.participantEvents {
+		c.participantEvents[id] = append([]participantEvent(nil), events...)
+	}
+	for _, record := range d.rolls {
+		c.rolls = append(c.rolls, copyRoll(record))
+	}
+	return c
+}
+
+type txKey struct{}
+
+// inTx はcontextがこのStoreのトランザクション内かを返す
+func (s *Store) inTx(ctx context.Context) bool {
+	tx, ok := ctx.Value(txKey{}).(*Store)
+	return ok && tx == s
+}
+
+// access はStoreのデータに排他的にアクセスする
+// トランザクション内ではTxManagerがロックを保持しているため、ロックせずにアクセスする
+func (s *Store) access(ctx context.Context, fn func(d *data) error) error {
+	if s.inTx(ctx) {
+		return fn(s.data)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(s.data)
+}
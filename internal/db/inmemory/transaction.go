@@ -0,0 +1,46 @@
+package inmemory
+
+import (
+	"at-bot/internal/uow"
+	"context"
+)
+
+type txManager struct {
+	store *Store
+}
+
+func NewTxManager(store *Store) uow.UnitOfWork {
+	return &txManager{
+		store: store,
+	}
+}
+
+// Do はStoreをロックしてfnを実行し、エラーまたはpanicの場合はfn実行前のデータに戻す
+// トランザクションは直列に実行され、トランザクション内で呼び出した場合は外側のトランザクションに含める
+func (m *txManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if m.store.inTx(ctx) {
+		return fn(ctx)
+	}
+
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	snapshot := m.store.data.clone()
+	txCtx := context.WithValue(ctx, txKey{}, m.store)
+
+	var fnErr error
+	defer func() {
+		if p := recover(); p != nil {
+			// panicが発生した場合はロールバック
+			m.store.data = snapshot
+			// panicを再度投げる
+			panic(p)
+		} else if fnErr != nil {
+			// エラーが発生した場合はロールバック
+			m.store.data = snapshot
+		}
+	}()
+
+	fnErr = fn(txCtx)
+	return fnErr
+}
@@ -0,0 +1,81 @@
+package inmemory
+
+import (
+	"at-bot/internal/recruit"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTxManager_Do_Panic(t *testing.T) {
+	store := NewStore()
+	repo := NewRecruitRepository(store)
+	txManager := NewTxManager(store)
+	ctx := context.Background()
+
+	func() {
+		defer func() {
+			if p := recover(); p == nil {
+				t.Error("Do() should re-panic")
+			}
+		}()
+		_ = txManager.Do(ctx, func(ctx context.Context) error {
+			if _, err := repo.Create(ctx, &recruit.RecruitState{MessageID: "message-1", CreatedAt: time.Now()}); err != nil {
+				return err
+			}
+			panic("test panic")
+		})
+	}()
+
+	// panicでロールバックされ、ロックも解放されている
+	if _, err := repo.GetByMessage(ctx, "", "message-1"); err == nil {
+		t.Error("GetByMessage() after panic error = nil, want error")
+	}
+}
+
+func TestTxManager_Do_Nested(t *testing.T) {
+	store := NewStore()
+	repo := NewRecruitRepository(store)
+	txManager := NewTxManager(store)
+	ctx := context.Background()
+
+	// 内側のトランザクションは外側に含まれ、デッドロックしない
+	err := txManager.Do(ctx, func(ctx context.Context) error {
+		return txManager.Do(ctx, func(ctx context.Context) error {
+			_, err := repo.Create(ctx, &recruit.RecruitState{MessageID: "message-1", CreatedAt: time.Now()})
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if _, err := repo.GetByMessage(ctx, "", "message-1"); err != nil {
+		t.Errorf("GetByMessage() error = %v", err)
+	}
+}
+
+func TestRecruitRepository_ReturnsCopy(t *testing.T) {
+	store := NewStore()
+	repo := NewRecruitRepository(store)
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &recruit.RecruitState{MessageID: "message-1", MaxCapacity: 3, CreatedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// 取得した募集を変更しても保存されたデータは変わらない
+	got, err := repo.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	got.MaxCapacity = 10
+
+	again, err := repo.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if again.MaxCapacity != 3 {
+		t.Errorf("MaxCapacity = %v, want 3", again.MaxCapacity)
+	}
+}
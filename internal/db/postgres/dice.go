@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"at-bot/internal/dice"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type postgresRollRepository struct {
+	db *sql.DB
+}
+
+func NewRollRepository(db *sql.DB) dice.RollRepository {
+	return &postgresRollRepository{
+		db: db,
+	}
+}
+
+// Create は履歴と出目を保存する
+// 出目を複数のINSERTで保存するため、UnitOfWorkの中で呼び出すこと
+func (r *postgresRollRepository) Create(ctx context.Context, record *dice.RollRecord) (dice.RollID, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO dice_rolls (guild_id, channel_id, user_id, expression, total, secret, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	var id int64
+	err := executor.QueryRowContext(
+		ctx,
+		query,
+		record.Roller.GuildID,
+		record.Roller.ChannelID,
+		record.Roller.UserID,
+		record.Expression,
+		record.Total,
+		record.Secret,
+		record.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create dice roll: %w", err)
+	}
+
+	for i, d := range record.Dice {
+		_, err := executor.ExecContext(
+			ctx,
+			`INSERT INTO dice_roll_results (roll_id, position, sides, value, dropped, exploded)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			id,
+			i,
+			d.Sides,
+			d.Value,
+			d.Dropped,
+			d.Exploded,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create dice roll result: %w", err)
+		}
+	}
+
+	return dice.RollID(id), nil
+}
+
+func (r *postgresRollRepository) ListRecent(
+	ctx context.Context,
+	guildID dice.GuildID,
+	userID dice.UserID,
+	limit int,
+) ([]*dice.RollRecord, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	query := `
+		SELECT id, guild_id, channel_id, user_id, expression, total, secret, created_at
+		FROM dice_rolls
+		WHERE guild_id = $1 AND user_id = $2
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`
+
+	rows, err := executor.QueryContext(ctx, query, guildID, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dice rolls: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*dice.RollRecord
+	byID := make(map[dice.RollID]*dice.RollRecord)
+	for rows.Next() {
+		var record dice.RollRecord
+		err := rows.Scan(
+			&record.ID,
+			&record.Roller.GuildID,
+			&record.Roller.ChannelID,
+			&record.Roller.UserID,
+			&record.Expression,
+			&record.Total,
+			&record.Secret,
+			&record.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dice roll: %w", err)
+		}
+		records = append(records, &record)
+		byID[record.ID] = &record
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate dice rolls: %w", err)
+	}
+
+	if len(records) == 0 {
+		return records, nil
+	}
+
+	if err := r.loadDice(ctx, executor, byID); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// loadDice は履歴ごとの出目を読み込む
+func (r *postgresRollRepository) loadDice(
+	ctx context.Context,
+	executor executor,
+	byID map[dice.RollID]*dice.RollRecord,
+) error {
+	ids := make([]int64, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, int64(id))
+	}
+
+	query := `
+		SELECT roll_id, sides, value, dropped, exploded
+		FROM dice_roll_results
+		WHERE roll_id = ANY($1)
+		ORDER BY roll_id, position
+	`
+
+	rows, err := executor.QueryContext(ctx, query, ids)
+	if err != nil {
+		return fmt.Errorf("failed to list dice roll results: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id dice.RollID
+		var d dice.RecordedDie
+		if err := rows.Scan(&id, &d.Sides, &d.Value, &d.Dropped, &d.Exploded); err != nil {
+			return fmt.Errorf("failed to scan dice roll result: %w", err)
+		}
+		byID[id].Dice = append(byID[id].Dice, d)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate dice roll results: %w", err)
+	}
+
+	return nil
+}
+
+func (r *postgresRollRepository) CountFaces(
+	ctx context.Context,
+	guildID dice.GuildID,
+	userID dice.UserID,
+) ([]dice.FaceCount, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	query := `
+		SELECT d.sides, d.value, COUNT(*) AS cnt,
+			SUM(COUNT(*)) OVER (PARTITION BY d.sides)::bigint AS sides_total
+		FROM dice_roll_results d
+		JOIN dice_rolls r ON r.id = d.roll_id
+		WHERE r.guild_id = $1 AND r.user_id = $2 AND NOT r.secret
+		GROUP BY d.sides, d.value
+		ORDER BY sides_total DESC, d.sides ASC, d.value ASC
+	`
+
+	rows, err := executor.QueryContext(ctx, query, guildID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count dice faces: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []dice.FaceCount
+	for rows.Next() {
+		var c dice.FaceCount
+		var sidesTotal int
+		if err := rows.Scan(&c.Sides, &c.Value, &c.Count, &sidesTotal); err != nil {
+			return nil, fmt.Errorf("failed to scan dice face count: %w", err)
+		}
+		counts = append(counts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate dice face counts: %w", err)
+	}
+
+	return counts, nil
+}
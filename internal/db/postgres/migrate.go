@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// migration はバージョン付きのスキーマ変更
+// 適用済みのマイグレーションは変更せず、スキーマを変更する場合は末尾に追加する
+// バージョンはSQLiteのマイグレーションとは独立して管理する
+type migration struct {
+	version int
+	name    string
+	up      string
+}
+
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "create recruits, participants and dice rolls",
+		up: `
+		-- 募集テーブル
+		CREATE TABLE recruits (
+			id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+			guild_id TEXT NOT NULL,
+			channel_id TEXT NOT NULL,
+			message_id TEXT NOT NULL,
+			author_id TEXT NOT NULL,
+			max_capacity INTEGER NOT NULL,
+			status TEXT NOT NULL DEFAULT 'opened',
+			start_at TIMESTAMPTZ,
+			reminded_at TIMESTAMPTZ,
+			close_reason TEXT NOT NULL DEFAULT '',
+			reopened_at TIMESTAMPTZ,
+			title TEXT NOT NULL DEFAULT '',
+			description TEXT NOT NULL DEFAULT '',
+			role_id TEXT NOT NULL DEFAULT '',
+			role_restricted BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ
+		);
+
+		-- 参加者テーブル
+		CREATE TABLE participants (
+			recruit_id BIGINT NOT NULL REFERENCES recruits(id) ON DELETE CASCADE,
+			user_id TEXT NOT NULL,
+			status TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ,
+			PRIMARY KEY (recruit_id, user_id)
+		);
+
+		-- ダイス履歴テーブル
+		CREATE TABLE dice_rolls (
+			id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+			guild_id TEXT NOT NULL,
+			channel_id TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			expression TEXT NOT NULL,
+			total INTEGER NOT NULL,
+			secret BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
+		-- ダイス履歴の出目テーブル
+		CREATE TABLE dice_roll_results (
+			roll_id BIGINT NOT NULL REFERENCES dice_rolls(id) ON DELETE CASCADE,
+			position INTEGER NOT NULL,
+			sides INTEGER NOT NULL,
+			value INTEGER NOT NULL,
+			dropped BOOLEAN NOT NULL DEFAULT FALSE,
+			exploded BOOLEAN NOT NULL DEFAULT FALSE,
+			PRIMARY KEY (roll_id, position)
+		);
+
+		CREATE INDEX idx_recruits_status ON recruits(status);
+		CREATE INDEX idx_recruits_guild_id ON recruits(guild_id);
+		CREATE INDEX idx_recruits_message ON recruits(channel_id, message_id);
+		CREATE INDEX idx_participants_user_id ON participants(user_id);
+		CREATE INDEX idx_dice_rolls_guild_user ON dice_rolls(guild_id, user_id, created_at);
+		`,
+	},
+	{
+		version: 2,
+		name:    "create participant events",
+		up: `
+		-- 参加状態の変更履歴テーブル
+		-- from_statusがNULLの場合は初回の回答
+		CREATE TABLE participant_events (
+			id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+			recruit_id BIGINT NOT NULL REFERENCES recruits(id) ON DELETE CASCADE,
+			user_id TEXT NOT NULL,
+			from_status TEXT,
+			to_status TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX idx_participant_events_recruit_id ON participant_events(recruit_id);
+		CREATE INDEX idx_participant_events_user_id ON participant_events(user_id);
+
+		-- 既存の参加者は変更前の状態が分からないため、現在の状態を初回の回答として記録する
+		INSERT INTO participant_events (recruit_id, user_id, from_status, to_status, created_at)
+		SELECT recruit_id, user_id, NULL, status, COALESCE(updated_at, created_at)
+		FROM participants;
+		`,
+	},
+}
+
+// migrationLockID は複数のレプリカが同時にマイグレーションしないよう取得するアドバイザリロックのID
+const migrationLockID = 7_301_457_209
+
+// latestVersion は最新のスキーマバージョンを返す
+func latestVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// migrate は未適用のマイグレーションを順に全て適用する
+func migrate(ctx context.Context, db *sql.DB) error {
+	return migrateTo(ctx, db, latestVersion())
+}
+
+// migrateTo は未適用のマイグレーションをtargetのバージョンまで順に適用する
+// マイグレーションごとにトランザクションを分け、失敗した場合はそのマイグレーションのみロールバックする
+func migrateTo(ctx context.Context, db *sql.DB, target int) error {
+	// アドバイザリロックはセッション単位のため、同じ接続でロックから解放までを行う
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	_, err = conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	current, err := schemaVersion(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if current > latestVersion() {
+		return fmt.Errorf("database schema version %d is newer than supported version %d", current, latestVersion())
+	}
+
+	for _, m := range migrations {
+		if m.version <= current || m.version > target {
+			continue
+		}
+		if err := applyMigration(ctx, conn, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func applyMigration(ctx context.Context, conn *sql.Conn, m migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %d: %w", m.version, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, m.up); err != nil {
+		return fmt.Errorf("failed to apply migration %d (%s): %w", m.version, m.name, err)
+	}
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
+		m.version, m.name,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", m.version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %d: %w", m.version, err)
+	}
+	return nil
+}
+
+// schemaVersion は適用済みの最新のスキーマバージョンを返す
+// マイグレーションを1つも適用していない場合は0を返す
+func schemaVersion(ctx context.Context, exec executor) (int, error) {
+	var version int
+	err := exec.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	return version, nil
+}
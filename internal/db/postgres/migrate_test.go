@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMigrations_Ordered(t *testing.T) {
+	for i, m := range migrations {
+		if m.version != i+1 {
+			t.Errorf("migrations[%d].version = %v, want %v", i, m.version, i+1)
+		}
+	}
+}
+
+func TestMigrate_Idempotent(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	// InitDBで適用済みのため、再実行しても何も適用しない
+	if err := migrate(ctx, db); err != nil {
+		t.Fatalf("migrate() error = %v", err)
+	}
+
+	got, err := schemaVersion(ctx, db)
+	if err != nil {
+		t.Fatalf("schemaVersion() error = %v", err)
+	}
+	if got != latestVersion() {
+		t.Errorf("schemaVersion() = %v, want %v", got, latestVersion())
+	}
+}
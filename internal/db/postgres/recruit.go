@@ -0,0 +1,492 @@
+package postgres
+
+import (
+	"at-bot/internal/recruit"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// recruitColumns はrecruitsテーブルのSELECT対象カラム
+// scanRecruitのScan順と一致させること
+const recruitColumns = `
+	id, guild_id, channel_id, message_id, author_id, max_capacity, status,
+	start_at, reminded_at, close_reason, reopened_at, title, description,
+	role_id, role_restricted, created_at, updated_at
+`
+
+// rowScanner はsql.Rowとsql.Rowsの共通インターフェース
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRecruit(row rowScanner) (*recruit.RecruitState, error) {
+	var state recruit.RecruitState
+	var startAt, remindedAt, reopenedAt, updatedAt sql.NullTime
+	err := row.Scan(
+		&state.ID,
+		&state.GuildID,
+		&state.ChannelID,
+		&state.MessageID,
+		&state.AuthorID,
+		&state.MaxCapacity,
+		&state.Status,
+		&startAt,
+		&remindedAt,
+		&state.CloseReason,
+		&reopenedAt,
+		&state.Title,
+		&state.Description,
+		&state.RoleID,
+		&state.RoleRestricted,
+		&state.CreatedAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	state.StartAt = nullTimeToPtr(startAt)
+	state.RemindedAt = nullTimeToPtr(remindedAt)
+	state.ReopenedAt = nullTimeToPtr(reopenedAt)
+	state.UpdatedAt = nullTimeToPtr(updatedAt)
+	return &state, nil
+}
+
+func nullTimeToPtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func ptrToNullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+type postgresRecruitRepository struct {
+	db *sql.DB
+}
+
+func NewRecruitRepository(db *sql.DB) recruit.RecruitRepository {
+	return &postgresRecruitRepository{
+		db: db,
+	}
+}
+
+func (r *postgresRecruitRepository) Get(ctx context.Context, id recruit.RecruitID) (*recruit.RecruitState, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	query := `SELECT ` + recruitColumns + `
+		FROM recruits
+		WHERE id = $1
+	` + lockClause(ctx)
+
+	state, err := scanRecruit(executor.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("recruit not found: %d", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recruit: %w", err)
+	}
+
+	return state, nil
+}
+
+func (r *postgresRecruitRepository) GetByMessage(
+	ctx context.Context,
+	channelID recruit.ChannelID,
+	messageID recruit.MessageID,
+) (*recruit.RecruitState, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	query := `SELECT ` + recruitColumns + `
+		FROM recruits
+		WHERE channel_id = $1 AND message_id = $2 AND close_reason <> $3
+	` + lockClause(ctx)
+
+	// 作成者が削除した募集はアーカイブとして残っているが、メッセージからは取得できない
+	state, err := scanRecruit(executor.QueryRowContext(ctx, query, channelID, messageID, recruit.CloseReasonDeleted))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s, %s", recruit.ErrRecruitNotFound, channelID, messageID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recruit: %w", err)
+	}
+
+	return state, nil
+}
+
+func (r *postgresRecruitRepository) Create(ctx context.Context, state *recruit.RecruitState) (recruit.RecruitID, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO recruits (
+			guild_id, channel_id, message_id, author_id, max_capacity, status,
+			start_at, title, description, role_id, role_restricted, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id
+	`
+
+	var id int64
+	err := executor.QueryRowContext(
+		ctx,
+		query,
+		state.GuildID,
+		state.ChannelID,
+		state.MessageID,
+		state.AuthorID,
+		state.MaxCapacity,
+		state.Status,
+		ptrToNullTime(state.StartAt),
+		state.Title,
+		state.Description,
+		state.RoleID,
+		state.RoleRestricted,
+		state.CreatedAt,
+	).Scan(&id)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to create recruit: %w", err)
+	}
+
+	return recruit.RecruitID(id), nil
+}
+
+func (r *postgresRecruitRepository) Update(ctx context.Context, state *recruit.RecruitState) error {
+	executor := GetExecutor(ctx, r.db)
+
+	now := time.Now()
+	query := `
+		UPDATE recruits
+		SET guild_id = $1, channel_id = $2, message_id = $3, author_id = $4,
+		    max_capacity = $5, status = $6, start_at = $7, reminded_at = $8, close_reason = $9,
+		    reopened_at = $10, title = $11, description = $12, role_id = $13, role_restricted = $14,
+		    updated_at = $15
+		WHERE id = $16
+	`
+
+	result, err := executor.ExecContext(
+		ctx,
+		query,
+		state.GuildID,
+		state.ChannelID,
+		state.MessageID,
+		state.AuthorID,
+		state.MaxCapacity,
+		state.Status,
+		ptrToNullTime(state.StartAt),
+		ptrToNullTime(state.RemindedAt),
+		state.CloseReason,
+		ptrToNullTime(state.ReopenedAt),
+		state.Title,
+		state.Description,
+		state.RoleID,
+		state.RoleRestricted,
+		now,
+		state.ID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update recruit: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("recruit not found: %d", state.ID)
+	}
+
+	return nil
+}
+
+func (r *postgresRecruitRepository) Delete(ctx context.Context, id recruit.RecruitID) error {
+	executor := GetExecutor(ctx, r.db)
+
+	query := `DELETE FROM recruits WHERE id = $1`
+
+	result, err := executor.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete recruit: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("recruit not found: %d", id)
+	}
+
+	return nil
+}
+
+func (r *postgresRecruitRepository) ListScheduled(ctx context.Context, until time.Time) ([]*recruit.RecruitState, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	query := `SELECT ` + recruitColumns + `
+		FROM recruits
+		WHERE status = $1 AND start_at IS NOT NULL AND start_at <= $2
+		ORDER BY start_at ASC
+	`
+
+	rows, err := executor.QueryContext(ctx, query, recruit.RecruitStatusOpened, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled recruits: %w", err)
+	}
+
+	return scanRecruits(rows)
+}
+
+func (r *postgresRecruitRepository) ListExpired(ctx context.Context, before time.Time) ([]*recruit.RecruitState, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	// GREATESTはNULLを無視するため、未設定の開始時刻と再開日時は比較対象にならない
+	query := `SELECT ` + recruitColumns + `
+		FROM recruits
+		WHERE status != $1
+		  AND GREATEST(created_at, start_at, reopened_at) <= $2
+		ORDER BY created_at ASC
+	`
+
+	rows, err := executor.QueryContext(ctx, query, recruit.RecruitStatusClosed, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired recruits: %w", err)
+	}
+
+	return scanRecruits(rows)
+}
+
+func (r *postgresRecruitRepository) ListOpened(
+	ctx context.Context,
+	guildID recruit.GuildID,
+	channelID recruit.ChannelID,
+) ([]*recruit.RecruitState, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	// idx_recruits_guild_id, idx_recruits_status を利用できるよう等価条件で絞り込む
+	query := `SELECT ` + recruitColumns + `
+		FROM recruits
+		WHERE guild_id = $1
+		  AND status IN ($2, $3)
+		  AND ($4::text = '' OR channel_id = $4)
+		ORDER BY created_at ASC
+	`
+
+	rows, err := executor.QueryContext(
+		ctx,
+		query,
+		guildID,
+		recruit.RecruitStatusOpened,
+		recruit.RecruitStatusStarted,
+		channelID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list opened recruits: %w", err)
+	}
+
+	return scanRecruits(rows)
+}
+
+// scanRecruits は複数行の募集を読み込み、rowsをクローズする
+func scanRecruits(rows *sql.Rows) ([]*recruit.RecruitState, error) {
+	defer rows.Close()
+
+	var states []*recruit.RecruitState
+	for rows.Next() {
+		state, err := scanRecruit(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan recruit: %w", err)
+		}
+		states = append(states, state)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate recruits: %w", err)
+	}
+
+	return states, nil
+}
+
+type postgresParticipantRepository struct {
+	db *sql.DB
+}
+
+func NewParticipantRepository(db *sql.DB) recruit.ParticipantRepository {
+	return &postgresParticipantRepository{
+		db: db,
+	}
+}
+
+func (r *postgresParticipantRepository) Upsert(ctx context.Context, recruitID recruit.RecruitID, userID recruit.UserID, status recruit.ParticipantStatus) error {
+	executor := GetExecutor(ctx, r.db)
+
+	now := time.Now()
+	if err := r.recordEvent(ctx, executor, recruitID, userID, status, now); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO participants (recruit_id, user_id, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT(recruit_id, user_id) DO UPDATE SET
+			status = excluded.status,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := executor.ExecContext(ctx, query, recruitID, userID, status, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to upsert participant: %w", err)
+	}
+
+	return nil
+}
+
+// recordEvent は参加状態が変わる場合に変更前の状態とともに履歴に記録する
+func (r *postgresParticipantRepository) recordEvent(
+	ctx context.Context,
+	executor executor,
+	recruitID recruit.RecruitID,
+	userID recruit.UserID,
+	status recruit.ParticipantStatus,
+	now time.Time,
+) error {
+	var previous sql.NullString
+	err := executor.QueryRowContext(ctx,
+		`SELECT status FROM participants WHERE recruit_id = $1 AND user_id = $2`,
+		recruitID, userID,
+	).Scan(&previous)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get participant status: %w", err)
+	}
+	if previous.Valid && recruit.ParticipantStatus(previous.String) == status {
+		return nil
+	}
+
+	_, err = executor.ExecContext(ctx, `
+		INSERT INTO participant_events (recruit_id, user_id, from_status, to_status, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, recruitID, userID, previous, status, now)
+	if err != nil {
+		return fmt.Errorf("failed to record participant event: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresParticipantRepository) FindByRecruitAndUser(ctx context.Context, recruitID recruit.RecruitID, userID recruit.UserID) (*recruit.Participant, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	query := `
+		SELECT recruit_id, user_id, status, created_at, updated_at
+		FROM participants
+		WHERE recruit_id = $1 AND user_id = $2
+	`
+	var p recruit.Participant
+	var updatedAt sql.NullTime
+	err := executor.
+		QueryRowContext(ctx, query, recruitID, userID).
+		Scan(
+			&p.RecruitID,
+			&p.UserID,
+			&p.Status,
+			&p.CreatedAt,
+			&updatedAt,
+		)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get participant: %w", err)
+	}
+
+	if updatedAt.Valid {
+		p.UpdatedAt = &updatedAt.Time
+	}
+
+	return &p, nil
+}
+
+func (r *postgresParticipantRepository) List(ctx context.Context, recruitID recruit.RecruitID) ([]recruit.Participant, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	query := `
+		SELECT recruit_id, user_id, status, created_at, updated_at
+		FROM participants
+		WHERE recruit_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := executor.QueryContext(ctx, query, recruitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list participants: %w", err)
+	}
+	defer rows.Close()
+
+	var participants []recruit.Participant
+	for rows.Next() {
+		var p recruit.Participant
+		var updatedAt sql.NullTime
+
+		err := rows.Scan(
+			&p.RecruitID,
+			&p.UserID,
+			&p.Status,
+			&p.CreatedAt,
+			&updatedAt,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan participant: %w", err)
+		}
+
+		if updatedAt.Valid {
+			p.UpdatedAt = &updatedAt.Time
+		}
+
+		participants = append(participants, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate participants: %w", err)
+	}
+
+	return participants, nil
+}
+
+func (r *postgresParticipantRepository) DeleteAll(ctx context.Context, recruitID recruit.RecruitID) error {
+	executor := GetExecutor(ctx, r.db)
+
+	query := `
+		DELETE FROM participants
+		WHERE recruit_id = $1
+	`
+
+	result, err := executor.ExecContext(ctx, query, recruitID)
+	if err != nil {
+		return fmt.Errorf("failed to delete participants: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("participants not found: %d", recruitID)
+	}
+
+	return nil
+}
@@ -0,0 +1,296 @@
+package postgres
+
+import (
+	"at-bot/internal/recruit"
+	"at-bot/internal/stats"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type postgresStatsRepository struct {
+	db *sql.DB
+}
+
+func NewStatsRepository(db *sql.DB) stats.StatsRepository {
+	return &postgresStatsRepository{
+		db: db,
+	}
+}
+
+func (r *postgresStatsRepository) GetUserStats(
+	ctx context.Context,
+	guildID recruit.GuildID,
+	userID recruit.UserID,
+	limit int,
+) (*stats.UserStats, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	result := &stats.UserStats{
+		GuildID: guildID,
+		UserID:  userID,
+	}
+
+	err := executor.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*) FROM recruits WHERE guild_id = $1 AND author_id = $2`,
+		guildID,
+		userID,
+	).Scan(&result.Opened)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count opened recruits: %w", err)
+	}
+
+	result.Joined, result.Declined, result.Canceled, err = r.countParticipations(ctx, executor, guildID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 双方が参加している募集を共通の参加として数える
+	query := `
+		SELECT other.user_id, COUNT(*) AS cnt
+		FROM participants self
+		JOIN participants other
+		  ON other.recruit_id = self.recruit_id
+		 AND other.user_id != self.user_id
+		JOIN recruits r ON r.id = self.recruit_id
+		WHERE r.guild_id = $1
+		  AND self.user_id = $2
+		  AND self.status = $3
+		  AND other.status = $4
+		GROUP BY other.user_id
+		ORDER BY cnt DESC, other.user_id ASC
+		LIMIT $5
+	`
+	rows, err := executor.QueryContext(
+		ctx,
+		query,
+		guildID,
+		userID,
+		recruit.ParticipantStatusJoined,
+		recruit.ParticipantStatusJoined,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list co-participants: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c stats.CoParticipant
+		if err := rows.Scan(&c.UserID, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan co-participant: %w", err)
+		}
+		result.CoParticipants = append(result.CoParticipants, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate co-participants: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *postgresStatsRepository) GetGuildStats(
+	ctx context.Context,
+	guildID recruit.GuildID,
+	limit int,
+) (*stats.GuildStats, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	result := &stats.GuildStats{
+		GuildID: guildID,
+	}
+
+	err := executor.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*) FROM recruits WHERE guild_id = $1`,
+		guildID,
+	).Scan(&result.Recruits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recruits: %w", err)
+	}
+
+	result.Joined, result.Declined, result.Canceled, err = r.countParticipations(ctx, executor, guildID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	result.TopMembers, err = r.listTopMembers(ctx, executor, guildID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result.TopPairs, err = r.listTopPairs(ctx, executor, guildID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// countParticipations は他のユーザーが作成した募集に対する参加/辞退/キャンセルの回数を返す
+// 参加は現在参加している募集、辞退は一度でも辞退した募集、キャンセルは参加からキャンセルした回数を数える
+// userIDが空の場合はギルド内の全ユーザーを対象にする
+func (r *postgresStatsRepository) countParticipations(
+	ctx context.Context,
+	executor executor,
+	guildID recruit.GuildID,
+	userID recruit.UserID,
+) (joined, declined, canceled int, err error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*)
+			 FROM participants p
+			 JOIN recruits r ON r.id = p.recruit_id
+			 WHERE r.guild_id = $1
+			   AND p.user_id != r.author_id
+			   AND ($2::text = '' OR p.user_id = $2)
+			   AND p.status = $3),
+			(SELECT COUNT(DISTINCT (e.recruit_id, e.user_id))
+			 FROM participant_events e
+			 JOIN recruits r ON r.id = e.recruit_id
+			 WHERE r.guild_id = $1
+			   AND e.user_id != r.author_id
+			   AND ($2::text = '' OR e.user_id = $2)
+			   AND e.to_status = $4),
+			(SELECT COUNT(*)
+			 FROM participant_events e
+			 JOIN recruits r ON r.id = e.recruit_id
+			 WHERE r.guild_id = $1
+			   AND e.user_id != r.author_id
+			   AND ($2::text = '' OR e.user_id = $2)
+			   AND e.from_status = $3
+			   AND e.to_status = $5)
+	`
+	err = executor.QueryRowContext(
+		ctx,
+		query,
+		guildID,
+		userID,
+		recruit.ParticipantStatusJoined,
+		recruit.ParticipantStatusDeclined,
+		recruit.ParticipantStatusCanceled,
+	).Scan(&joined, &declined, &canceled)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to count participations: %w", err)
+	}
+	return joined, declined, canceled, nil
+}
+
+func (r *postgresStatsRepository) listTopMembers(
+	ctx context.Context,
+	executor executor,
+	guildID recruit.GuildID,
+	limit int,
+) ([]stats.MemberStats, error) {
+	// 参加は現在参加している募集、キャンセルは参加からキャンセルした回数を数える
+	query := `
+		WITH joined AS (
+			SELECT p.user_id, COUNT(*) AS cnt
+			FROM participants p
+			JOIN recruits r ON r.id = p.recruit_id
+			WHERE r.guild_id = $1
+			  AND p.user_id != r.author_id
+			  AND p.status = $2
+			GROUP BY p.user_id
+		),
+		canceled AS (
+			SELECT e.user_id, COUNT(*) AS cnt
+			FROM participant_events e
+			JOIN recruits r ON r.id = e.recruit_id
+			WHERE r.guild_id = $1
+			  AND e.user_id != r.author_id
+			  AND e.from_status = $2
+			  AND e.to_status = $3
+			GROUP BY e.user_id
+		)
+		SELECT
+			m.user_id,
+			COALESCE(j.cnt, 0) AS joined,
+			COALESCE(c.cnt, 0) AS canceled
+		FROM (
+			SELECT user_id FROM joined
+			UNION
+			SELECT user_id FROM canceled
+		) m
+		LEFT JOIN joined j ON j.user_id = m.user_id
+		LEFT JOIN canceled c ON c.user_id = m.user_id
+		ORDER BY joined DESC, canceled ASC, m.user_id ASC
+		LIMIT $4
+	`
+	rows, err := executor.QueryContext(
+		ctx,
+		query,
+		guildID,
+		recruit.ParticipantStatusJoined,
+		recruit.ParticipantStatusCanceled,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list top members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []stats.MemberStats
+	for rows.Next() {
+		var m stats.MemberStats
+		if err := rows.Scan(&m.UserID, &m.Joined, &m.Canceled); err != nil {
+			return nil, fmt.Errorf("failed to scan member stats: %w", err)
+		}
+		members = append(members, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate member stats: %w", err)
+	}
+
+	return members, nil
+}
+
+func (r *postgresStatsRepository) listTopPairs(
+	ctx context.Context,
+	executor executor,
+	guildID recruit.GuildID,
+	limit int,
+) ([]stats.CoParticipantPair, error) {
+	// 同じ組を二重に数えないようユーザーIDの大小で向きを揃える
+	query := `
+		SELECT a.user_id, b.user_id, COUNT(*) AS cnt
+		FROM participants a
+		JOIN participants b
+		  ON b.recruit_id = a.recruit_id
+		 AND a.user_id < b.user_id
+		JOIN recruits r ON r.id = a.recruit_id
+		WHERE r.guild_id = $1
+		  AND a.status = $2
+		  AND b.status = $3
+		GROUP BY a.user_id, b.user_id
+		ORDER BY cnt DESC, a.user_id ASC, b.user_id ASC
+		LIMIT $4
+	`
+	rows, err := executor.QueryContext(
+		ctx,
+		query,
+		guildID,
+		recruit.ParticipantStatusJoined,
+		recruit.ParticipantStatusJoined,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list top pairs: %w", err)
+	}
+	defer rows.Close()
+
+	var pairs []stats.CoParticipantPair
+	for rows.Next() {
+		var p stats.CoParticipantPair
+		if err := rows.Scan(&p.UserA, &p.UserB, &p.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan co-participant pair: %w", err)
+		}
+		pairs = append(pairs, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate co-participant pairs: %w", err)
+	}
+
+	return pairs, nil
+}
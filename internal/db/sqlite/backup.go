@@ -0,0 +1,246 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Backup はオンラインバックアップAPIでdbの内容をdestPathに書き出す
+// Botの稼働中でも実行できる。書き込み途中のファイルを残さないよう、一時ファイルに書き出してから置き換える
+func Backup(ctx context.Context, db *sql.DB, destPath string) error {
+	dir := filepath.Dir(destPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(destPath)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create backup file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	// 置き換え後は既に存在しないため、失敗した場合のみ削除される
+	defer os.Remove(tmpPath)
+
+	dest, err := sql.Open("sqlite3", fileURI(tmpPath))
+	if err != nil {
+		return fmt.Errorf("failed to open backup file: %w", err)
+	}
+	defer dest.Close()
+
+	if err := copyDatabase(ctx, dest, db); err != nil {
+		return fmt.Errorf("failed to backup database: %w", err)
+	}
+	// 複製元のWALモードも複製されるため、単一のファイルで完結するよう戻す
+	if _, err := dest.ExecContext(ctx, "PRAGMA journal_mode = DELETE"); err != nil {
+		return fmt.Errorf("failed to set backup journal mode: %w", err)
+	}
+	if err := dest.Close(); err != nil {
+		return fmt.Errorf("failed to close backup file: %w", err)
+	}
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		return fmt.Errorf("failed to save backup file: %w", err)
+	}
+	return nil
+}
+
+// Restore はsrcPathのバックアップでdbの内容を置き換え、最新のスキーマにマイグレーションする
+// 他の接続の書き込みが失われないよう、dbはOpenExclusiveで開いたものを渡す
+func Restore(ctx context.Context, db *sql.DB, srcPath string) error {
+	if _, err := os.Stat(srcPath); err != nil {
+		return fmt.Errorf("failed to open backup file: %w", err)
+	}
+
+	src, err := sql.Open("sqlite3", fileURI(srcPath)+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("failed to open backup file: %w", err)
+	}
+	defer src.Close()
+
+	// 現在のデータを上書きする前に、バックアップが復元できるものか確認する
+	if err := verifyBackup(ctx, src); err != nil {
+		return err
+	}
+	if err := copyDatabase(ctx, db, src); err != nil {
+		return fmt.Errorf("failed to restore database: %w", err)
+	}
+	return migrate(ctx, db)
+}
+
+// fileURI はpathのファイルを開く接続文字列を返す
+// パスに?や#が含まれていてもパラメータとして解釈されないようエスケープする
+func fileURI(path string) string {
+	return "file:" + url.PathEscape(path)
+}
+
+// verifyBackup はバックアップが破損しておらず、このバージョンで扱えるスキーマであることを確認する
+func verifyBackup(ctx context.Context, db *sql.DB) error {
+	var result string
+	if err := db.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&result); err != nil {
+		return fmt.Errorf("failed to check backup integrity: %w", err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("backup file is corrupted: %s", result)
+	}
+
+	versioned, err := tableExists(ctx, db, "schema_migrations")
+	if err != nil {
+		return err
+	}
+	if !versioned {
+		// マイグレーション導入前のデータベースは募集テーブルの有無で判定する
+		legacy, err := tableExists(ctx, db, "recruits")
+		if err != nil {
+			return err
+		}
+		if !legacy {
+			return errors.New("backup file is not a bot database")
+		}
+		return nil
+	}
+
+	version, err := schemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+	if version > latestVersion() {
+		return fmt.Errorf("backup schema version %d is newer than supported version %d", version, latestVersion())
+	}
+	return nil
+}
+
+// copyDatabase はsrcの全ページをdestに複製する
+func copyDatabase(ctx context.Context, dest *sql.DB, src *sql.DB) error {
+	destConn, err := dest.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer destConn.Close()
+
+	srcConn, err := src.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer srcConn.Close()
+
+	return destConn.Raw(func(destDriverConn any) error {
+		return srcConn.Raw(func(srcDriverConn any) error {
+			destSQLite, ok := destDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("unexpected driver connection: %T", destDriverConn)
+			}
+			srcSQLite, ok := srcDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("unexpected driver connection: %T", srcDriverConn)
+			}
+
+			backup, err := destSQLite.Backup("main", srcSQLite, "main")
+			if err != nil {
+				return err
+			}
+			// 分割して複製すると途中の書き込みで最初からやり直しになるため、一度に全ページを複製する
+			if _, err := backup.Step(-1); err != nil {
+				backup.Close()
+				return err
+			}
+			return backup.Finish()
+		})
+	})
+}
+
+const (
+	backupFilePrefix = "bot-"
+	backupFileSuffix = ".db"
+)
+
+// BackupFileName はnowの時点のバックアップのファイル名を返す
+// 名前順に並べると作成順になる
+func BackupFileName(now time.Time) string {
+	return backupFilePrefix + now.Format("20060102-150405") + backupFileSuffix
+}
+
+// PruneBackups はdirのバックアップのうち、新しいものからretention個を残して削除する
+func PruneBackups(dir string, retention int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && strings.HasPrefix(name, backupFilePrefix) && strings.HasSuffix(name, backupFileSuffix) {
+			names = append(names, name)
+		}
+	}
+	if len(names) <= retention {
+		return nil
+	}
+
+	sort.Strings(names)
+	for _, name := range names[:len(names)-retention] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return fmt.Errorf("failed to remove old backup: %w", err)
+		}
+	}
+	return nil
+}
+
+type backupScheduler struct {
+	db        *sql.DB
+	dir       string
+	retention int
+}
+
+// NewBackupScheduler は定期的にdirへバックアップを作成し、新しいものからretention個を保持するスケジューラを作成する
+func NewBackupScheduler(db *sql.DB, dir string, retention int) *backupScheduler {
+	return &backupScheduler{
+		db:        db,
+		dir:       dir,
+		retention: retention,
+	}
+}
+
+// Run はctxがキャンセルされるまでinterval毎にバックアップを作成する
+func (scheduler *backupScheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// 停止時に書き出しの途中で中断しないよう、ctxのキャンセルは引き継がない
+		if _, err := scheduler.snapshot(context.WithoutCancel(ctx), time.Now()); err != nil {
+			log.Printf("[BACKUP] %v", err)
+		}
+	}
+}
+
+// snapshot はバックアップを作成し、保持数を超えた古いバックアップを削除する
+func (scheduler *backupScheduler) snapshot(ctx context.Context, now time.Time) (string, error) {
+	path := filepath.Join(scheduler.dir, BackupFileName(now))
+	if err := Backup(ctx, scheduler.db, path); err != nil {
+		return "", err
+	}
+	log.Printf("[BACKUP] database backed up to %s", path)
+
+	if err := PruneBackups(scheduler.dir, scheduler.retention); err != nil {
+		return path, err
+	}
+	return path, nil
+}
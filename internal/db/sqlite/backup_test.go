@@ -0,0 +1,324 @@
+package sqlite
+
+import (
+	"at-bot/internal/recruit"
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func openFileDB(t *testing.T, path string) *sql.DB {
+	t.Helper()
+
+	db, err := InitDB(DefaultConfig(path))
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func createTestRecruit(t *testing.T, db *sql.DB, messageID recruit.MessageID) recruit.RecruitID {
+	t.Helper()
+
+	id, err := NewRecruitRepository(db).Create(context.Background(), &recruit.RecruitState{
+		GuildID:     "guild-1",
+		ChannelID:   "channel-1",
+		MessageID:   messageID,
+		AuthorID:    "author-1",
+		MaxCapacity: 5,
+		Status:      recruit.RecruitStatusOpened,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return id
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	db := openFileDB(t, filepath.Join(dir, "bot.db"))
+	repo := NewRecruitRepository(db)
+	ctx := context.Background()
+
+	backedUp := createTestRecruit(t, db, "message-1")
+
+	backupPath := filepath.Join(dir, "backups", "backup.db")
+	if err := Backup(ctx, db, backupPath); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	// バックアップ後の変更は復元で取り消される
+	if err := repo.Delete(ctx, backedUp); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	createTestRecruit(t, db, "message-2")
+
+	if err := Restore(ctx, db, backupPath); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	if _, err := repo.Get(ctx, backedUp); err != nil {
+		t.Errorf("Get() after Restore() error = %v", err)
+	}
+	if _, err := repo.GetByMessage(ctx, "channel-1", "message-2"); err == nil {
+		t.Error("GetByMessage(message-2) after Restore() error = nil, want error")
+	}
+	assertSchemaVersion(t, db, latestVersion())
+
+	// 一時ファイルが残っていない
+	entries, err := os.ReadDir(filepath.Dir(backupPath))
+	if err != nil {
+		t.Fatalf("failed to read backup directory: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("backup directory has %d entries, want 1", len(entries))
+	}
+}
+
+func TestRestore_OlderSchema(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	// 古いバージョンのBotで作成されたバックアップ
+	old := openEmptyDB(t)
+	if err := migrateTo(ctx, old, 1); err != nil {
+		t.Fatalf("migrateTo() error = %v", err)
+	}
+	backupPath := filepath.Join(dir, "old.db")
+	if err := Backup(ctx, old, backupPath); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	db := openFileDB(t, filepath.Join(dir, "bot.db"))
+	if err := Restore(ctx, db, backupPath); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	assertSchemaVersion(t, db, latestVersion())
+}
+
+func TestRestore_InvalidBackup(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, path string)
+	}{
+		{
+			name:  "存在しないファイル",
+			setup: func(t *testing.T, path string) {},
+		},
+		{
+			name: "Botのデータベースではない",
+			setup: func(t *testing.T, path string) {
+				db, err := sql.Open("sqlite3", path)
+				if err != nil {
+					t.Fatalf("failed to open database: %v", err)
+				}
+				defer db.Close()
+				if _, err := db.Exec("CREATE TABLE other (id INTEGER)"); err != nil {
+					t.Fatalf("failed to create table: %v", err)
+				}
+			},
+		},
+		{
+			name: "SQLiteのファイルではない",
+			setup: func(t *testing.T, path string) {
+				if err := os.WriteFile(path, []byte("not a database"), 0644); err != nil {
+					t.Fatalf("failed to write file: %v", err)
+				}
+			},
+		},
+		{
+			name: "新しいバージョンのスキーマ",
+			setup: func(t *testing.T, path string) {
+				db := openFileDB(t, path)
+				if _, err := db.Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", latestVersion()+1, "future"); err != nil {
+					t.Fatalf("failed to insert migration: %v", err)
+				}
+				db.Close()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			backupPath := filepath.Join(dir, "backup.db")
+			tt.setup(t, backupPath)
+
+			db := openFileDB(t, filepath.Join(dir, "bot.db"))
+			id := createTestRecruit(t, db, "message-1")
+
+			if err := Restore(context.Background(), db, backupPath); err == nil {
+				t.Fatal("Restore() error = nil, want error")
+			}
+			// 復元に失敗した場合は現在のデータを変更しない
+			if _, err := NewRecruitRepository(db).Get(context.Background(), id); err != nil {
+				t.Errorf("Get() after failed Restore() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestPruneBackups(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	backups := []string{
+		BackupFileName(base),
+		BackupFileName(base.Add(time.Hour)),
+		BackupFileName(base.Add(24 * time.Hour)),
+		BackupFileName(base.Add(48 * time.Hour)),
+	}
+
+	tests := []struct {
+		name      string
+		retention int
+		want      []string
+	}{
+		{
+			name:      "保持数を超えた古いバックアップを削除",
+			retention: 2,
+			want:      append(slices.Clone(backups[2:]), "bot.db", "notes.txt"),
+		},
+		{
+			name:      "保持数以下の場合は削除しない",
+			retention: 4,
+			want:      append(slices.Clone(backups), "bot.db", "notes.txt"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			// バックアップ以外のファイルは削除しない
+			for _, name := range append(slices.Clone(backups), "bot.db", "notes.txt") {
+				if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+					t.Fatalf("failed to write file: %v", err)
+				}
+			}
+
+			if err := PruneBackups(dir, tt.retention); err != nil {
+				t.Fatalf("PruneBackups() error = %v", err)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatalf("failed to read directory: %v", err)
+			}
+			var got []string
+			for _, entry := range entries {
+				got = append(got, entry.Name())
+			}
+			slices.Sort(got)
+			want := slices.Sorted(slices.Values(tt.want))
+			if !slices.Equal(got, want) {
+				t.Errorf("files = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestBackupScheduler_Snapshot(t *testing.T) {
+	dir := t.TempDir()
+	db := openFileDB(t, filepath.Join(dir, "bot.db"))
+	createTestRecruit(t, db, "message-1")
+
+	backupDir := filepath.Join(dir, "backups")
+	scheduler := NewBackupScheduler(db, backupDir, 2)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var paths []string
+	for i := 0; i < 3; i++ {
+		path, err := scheduler.snapshot(context.Background(), base.Add(time.Duration(i)*time.Hour))
+		if err != nil {
+			t.Fatalf("snapshot() error = %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	if _, err := os.Stat(paths[0]); !os.IsNotExist(err) {
+		t.Errorf("oldest backup exists, want removed: %v", err)
+	}
+	for _, path := range paths[1:] {
+		backup := openFileDB(t, path)
+		if _, err := NewRecruitRepository(backup).GetByMessage(context.Background(), "channel-1", "message-1"); err != nil {
+			t.Errorf("GetByMessage() from %s error = %v", path, err)
+		}
+	}
+}
+
+func TestOpenExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.db")
+	config := DefaultConfig(path)
+	config.BusyTimeout = 0
+
+	// Botが開いている間は排他的に開けない
+	bot := openFileDB(t, path)
+	if _, err := OpenExclusive(config); !errors.Is(err, ErrDatabaseInUse) {
+		t.Fatalf("OpenExclusive() while bot is running error = %v, want ErrDatabaseInUse", err)
+	}
+	bot.Close()
+
+	db, err := OpenExclusive(config)
+	if err != nil {
+		t.Fatalf("OpenExclusive() error = %v", err)
+	}
+	defer db.Close()
+
+	// 排他的に開いている間はBotを起動できない
+	if _, err := InitDB(config); err == nil {
+		t.Error("InitDB() while opened exclusively error = nil, want error")
+	}
+}
+
+func TestOpenExclusive_DoesNotMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.db")
+	ctx := context.Background()
+
+	old, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := migrateTo(ctx, old, 1); err != nil {
+		t.Fatalf("migrateTo() error = %v", err)
+	}
+	old.Close()
+
+	// 復元前に退避するデータベースは開いた時点のスキーマのまま
+	db, err := OpenExclusive(DefaultConfig(path))
+	if err != nil {
+		t.Fatalf("OpenExclusive() error = %v", err)
+	}
+	defer db.Close()
+	assertSchemaVersion(t, db, 1)
+}
+
+func TestRestore_SpecialCharactersInPath(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	src := openFileDB(t, filepath.Join(dir, "src.db"))
+	createTestRecruit(t, src, "message-1")
+	backupPath := filepath.Join(dir, "backup?#1.db")
+	if err := Backup(ctx, src, backupPath); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	db, err := OpenExclusive(DefaultConfig(filepath.Join(dir, "bot.db")))
+	if err != nil {
+		t.Fatalf("OpenExclusive() error = %v", err)
+	}
+	defer db.Close()
+	if err := Restore(ctx, db, backupPath); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if _, err := NewRecruitRepository(db).GetByMessage(ctx, "channel-1", "message-1"); err != nil {
+		t.Errorf("GetByMessage() after Restore() error = %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config はSQLiteの接続設定
+// PRAGMAは接続ごとの設定のため、接続文字列で全ての接続に適用する
+type Config struct {
+	Path string
+	// JournalMode はジャーナルモード。WALの場合は読み込みと書き込みを同時に行える
+	JournalMode string
+	// BusyTimeout は他の接続がロックを保持している場合に待機する時間
+	BusyTimeout time.Duration
+	// Synchronous はディスクへの同期の頻度。WALではNORMALでもコミット済みのデータは破損しない
+	Synchronous string
+	// MaxOpenConns は接続プールの最大接続数
+	MaxOpenConns int
+}
+
+// DefaultConfig はpathのDBに対する既定の設定を返す
+func DefaultConfig(path string) Config {
+	return Config{
+		Path:         path,
+		JournalMode:  "WAL",
+		BusyTimeout:  5 * time.Second,
+		Synchronous:  "NORMAL",
+		MaxOpenConns: 4,
+	}
+}
+
+var (
+	journalModes = []string{"DELETE", "TRUNCATE", "PERSIST", "MEMORY", "WAL", "OFF"}
+	synchronous  = []string{"OFF", "NORMAL", "FULL", "EXTRA"}
+)
+
+func (c Config) validate() error {
+	if !slices.Contains(journalModes, strings.ToUpper(c.JournalMode)) {
+		return fmt.Errorf("invalid journal mode: %q", c.JournalMode)
+	}
+	if !slices.Contains(synchronous, strings.ToUpper(c.Synchronous)) {
+		return fmt.Errorf("invalid synchronous: %q", c.Synchronous)
+	}
+	if c.BusyTimeout < 0 {
+		return fmt.Errorf("invalid busy timeout: %v", c.BusyTimeout)
+	}
+	if c.MaxOpenConns < 1 {
+		return fmt.Errorf("invalid max open conns: %d", c.MaxOpenConns)
+	}
+	return nil
+}
+
+// dsn は設定を接続文字列に変換する
+func (c Config) dsn() string {
+	return c.Path + "?" + c.params().Encode()
+}
+
+// params は設定を接続文字列のパラメータに変換する
+func (c Config) params() url.Values {
+	params := url.Values{}
+	params.Set("_journal_mode", strings.ToUpper(c.JournalMode))
+	params.Set("_busy_timeout", strconv.FormatInt(c.BusyTimeout.Milliseconds(), 10))
+	params.Set("_synchronous", strings.ToUpper(c.Synchronous))
+	params.Set("_foreign_keys", "on")
+	// トランザクション開始時に書き込みロックを取得する
+	// 読み込み後に書き込みへ昇格する場合はbusy timeoutで待機できずに失敗するため
+	params.Set("_txlock", "immediate")
+	return params
+}
@@ -0,0 +1,175 @@
+package sqlite
+
+import (
+	"at-bot/internal/recruit"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		wantErr bool
+	}{
+		{
+			name:    "既定の設定",
+			modify:  func(c *Config) {},
+			wantErr: false,
+		},
+		{
+			name:    "小文字のジャーナルモード",
+			modify:  func(c *Config) { c.JournalMode = "delete" },
+			wantErr: false,
+		},
+		{
+			name:    "不正なジャーナルモード",
+			modify:  func(c *Config) { c.JournalMode = "UNKNOWN" },
+			wantErr: true,
+		},
+		{
+			name:    "不正な同期レベル",
+			modify:  func(c *Config) { c.Synchronous = "SOMETIMES" },
+			wantErr: true,
+		},
+		{
+			name:    "負の待機時間",
+			modify:  func(c *Config) { c.BusyTimeout = -time.Second },
+			wantErr: true,
+		},
+		{
+			name:    "最大接続数が0",
+			modify:  func(c *Config) { c.MaxOpenConns = 0 },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig("test.db")
+			tt.modify(&config)
+
+			err := config.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInitDB_InvalidConfig(t *testing.T) {
+	config := DefaultConfig(t.TempDir() + "/test.db")
+	config.JournalMode = "UNKNOWN"
+
+	if db, err := InitDB(config); err == nil {
+		db.Close()
+		t.Fatal("InitDB() error = nil, want error")
+	}
+}
+
+// PRAGMAは接続ごとの設定のため、プール内の全ての接続に適用されていることを確認する
+func TestInitDB_PragmasPerConnection(t *testing.T) {
+	config := DefaultConfig(t.TempDir() + "/test.db")
+	config.BusyTimeout = 3 * time.Second
+	config.Synchronous = "FULL"
+
+	db, err := InitDB(config)
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	// 同時に保持することで、それぞれ別の接続を取得する
+	conns := make([]*sql.Conn, config.MaxOpenConns)
+	for i := range conns {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatalf("failed to get connection: %v", err)
+		}
+		defer conn.Close()
+		conns[i] = conn
+	}
+
+	tests := []struct {
+		pragma string
+		want   string
+	}{
+		{pragma: "journal_mode", want: "wal"},
+		{pragma: "busy_timeout", want: "3000"},
+		// FULLは2
+		{pragma: "synchronous", want: "2"},
+		{pragma: "foreign_keys", want: "1"},
+	}
+
+	for i, conn := range conns {
+		for _, tt := range tests {
+			var got string
+			if err := conn.QueryRowContext(ctx, "PRAGMA "+tt.pragma).Scan(&got); err != nil {
+				t.Fatalf("failed to query pragma %s: %v", tt.pragma, err)
+			}
+			if !strings.EqualFold(got, tt.want) {
+				t.Errorf("conn[%d] PRAGMA %s = %q, want %q", i, tt.pragma, got, tt.want)
+			}
+		}
+	}
+}
+
+// 複数のゴルーチンから同時に参加しても"database is locked"で失敗しないことを確認する
+func TestInitDB_ConcurrentJoin(t *testing.T) {
+	const users = 50
+
+	db, err := InitDB(DefaultConfig(t.TempDir() + "/test.db"))
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	defer db.Close()
+
+	uc := recruit.NewRecruitUsecase(
+		NewRecruitRepository(db),
+		NewParticipantRepository(db),
+		NewTxManager(db),
+	)
+
+	ctx := context.Background()
+	if _, err := uc.Open(ctx, "guild-1", "channel-1", "message-1", users+1, "author-1"); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, users)
+	for i := 0; i < users; i++ {
+		wg.Add(1)
+		go func(userID recruit.UserID) {
+			defer wg.Done()
+			if _, err := uc.Join(ctx, "channel-1", "message-1", userID, nil); err != nil {
+				errs <- fmt.Errorf("Join(%s) error = %w", userID, err)
+			}
+		}(recruit.UserID(fmt.Sprintf("user-%d", i)))
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	view, err := uc.Get(ctx, "channel-1", "message-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	// 作成者を含む
+	if len(view.JoinedUsers) != users+1 {
+		t.Errorf("len(JoinedUsers) = %d, want %d", len(view.JoinedUsers), users+1)
+	}
+	if len(view.WaitlistedUsers) != 0 {
+		t.Errorf("WaitlistedUsers = %v, want empty", view.WaitlistedUsers)
+	}
+}
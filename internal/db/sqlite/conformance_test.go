@@ -0,0 +1,35 @@
+package sqlite
+
+import (
+	"at-bot/internal/dice"
+	"at-bot/internal/dice/dicetest"
+	"at-bot/internal/recruit/recruittest"
+	"context"
+	"testing"
+)
+
+func TestRecruitRepositoryConformance(t *testing.T) {
+	recruittest.Run(t, func(t *testing.T) recruittest.Backend {
+		db := openEmptyDB(t)
+		if err := migrate(context.Background(), db); err != nil {
+			t.Fatalf("failed to migrate: %v", err)
+		}
+
+		return recruittest.Backend{
+			Recruits:     NewRecruitRepository(db),
+			Participants: NewParticipantRepository(db),
+			UnitOfWork:   NewTxManager(db),
+		}
+	})
+}
+
+func TestRollRepositoryConformance(t *testing.T) {
+	dicetest.Run(t, func(t *testing.T) dice.RollRepository {
+		db := openEmptyDB(t)
+		if err := migrate(context.Background(), db); err != nil {
+			t.Fatalf("failed to migrate: %v", err)
+		}
+
+		return NewRollRepository(db)
+	})
+}
@@ -0,0 +1,205 @@
+package sqlite
+
+import (
+	"at-bot/internal/dice"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+type sqliteRollRepository struct {
+	db *sql.DB
+}
+
+func NewRollRepository(db *sql.DB) dice.RollRepository {
+	return &sqliteRollRepository{
+		db: db,
+	}
+}
+
+// Create は履歴と出目を保存する
+// 出目を複数のINSERTで保存するため、UnitOfWorkの中で呼び出すこと
+func (r *sqliteRollRepository) Create(ctx context.Context, record *dice.RollRecord) (dice.RollID, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO dice_rolls (guild_id, channel_id, user_id, expression, total, secret, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := executor.ExecContext(
+		ctx,
+		query,
+		record.Roller.GuildID,
+		record.Roller.ChannelID,
+		record.Roller.UserID,
+		record.Expression,
+		record.Total,
+		record.Secret,
+		record.CreatedAt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create dice roll: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	for i, d := range record.Dice {
+		_, err := executor.ExecContext(
+			ctx,
+			`INSERT INTO dice_roll_results (roll_id, position, sides, value, dropped, exploded)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			id,
+			i,
+			d.Sides,
+			d.Value,
+			d.Dropped,
+			d.Exploded,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create dice roll result: %w", err)
+		}
+	}
+
+	return dice.RollID(id), nil
+}
+
+func (r *sqliteRollRepository) ListRecent(
+	ctx context.Context,
+	guildID dice.GuildID,
+	userID dice.UserID,
+	limit int,
+) ([]*dice.RollRecord, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	query := `
+		SELECT id, guild_id, channel_id, user_id, expression, total, secret, created_at
+		FROM dice_rolls
+		WHERE guild_id = ? AND user_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+
+	rows, err := executor.QueryContext(ctx, query, guildID, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dice rolls: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*dice.RollRecord
+	byID := make(map[dice.RollID]*dice.RollRecord)
+	for rows.Next() {
+		var record dice.RollRecord
+		err := rows.Scan(
+			&record.ID,
+			&record.Roller.GuildID,
+			&record.Roller.ChannelID,
+			&record.Roller.UserID,
+			&record.Expression,
+			&record.Total,
+			&record.Secret,
+			&record.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dice roll: %w", err)
+		}
+		records = append(records, &record)
+		byID[record.ID] = &record
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate dice rolls: %w", err)
+	}
+
+	if len(records) == 0 {
+		return records, nil
+	}
+
+	if err := r.loadDice(ctx, executor, byID); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// loadDice は履歴ごとの出目を読み込む
+func (r *sqliteRollRepository) loadDice(
+	ctx context.Context,
+	executor executor,
+	byID map[dice.RollID]*dice.RollRecord,
+) error {
+	placeholders := make([]string, 0, len(byID))
+	args := make([]any, 0, len(byID))
+	for id := range byID {
+		placeholders = append(placeholders, "?")
+		args = append(args, id)
+	}
+
+	query := `
+		SELECT roll_id, sides, value, dropped, exploded
+		FROM dice_roll_results
+		WHERE roll_id IN (` + strings.Join(placeholders, ", ") + `)
+		ORDER BY roll_id, position
+	`
+
+	rows, err := executor.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to list dice roll results: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id dice.RollID
+		var d dice.RecordedDie
+		if err := rows.Scan(&id, &d.Sides, &d.Value, &d.Dropped, &d.Exploded); err != nil {
+			return fmt.Errorf("failed to scan dice roll result: %w", err)
+		}
+		byID[id].Dice = append(byID[id].Dice, d)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate dice roll results: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sqliteRollRepository) CountFaces(
+	ctx context.Context,
+	guildID dice.GuildID,
+	userID dice.UserID,
+) ([]dice.FaceCount, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	query := `
+		SELECT d.sides, d.value, COUNT(*) AS cnt,
+			SUM(COUNT(*)) OVER (PARTITION BY d.sides) AS sides_total
+		FROM dice_roll_results d
+		JOIN dice_rolls r ON r.id = d.roll_id
+		WHERE r.guild_id = ? AND r.user_id = ? AND r.secret = 0
+		GROUP BY d.sides, d.value
+		ORDER BY sides_total DESC, d.sides ASC, d.value ASC
+	`
+
+	rows, err := executor.QueryContext(ctx, query, guildID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count dice faces: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []dice.FaceCount
+	for rows.Next() {
+		var c dice.FaceCount
+		var sidesTotal int
+		if err := rows.Scan(&c.Sides, &c.Value, &c.Count, &sidesTotal); err != nil {
+			return nil, fmt.Errorf("failed to scan dice face count: %w", err)
+		}
+		counts = append(counts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate dice face counts: %w", err)
+	}
+
+	return counts, nil
+}
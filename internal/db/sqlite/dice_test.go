@@ -0,0 +1,99 @@
+package sqlite
+
+import (
+	"at-bot/internal/dice"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRollRepository_CreateAndListRecent(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRollRepository(db)
+	ctx := context.Background()
+	roller := dice.Roller{GuildID: "guild-1", ChannelID: "channel-1", UserID: "user-1"}
+	base := time.Now()
+
+	for i, total := range []int{3, 22, 8} {
+		_, err := repo.Create(ctx, &dice.RollRecord{
+			Roller:     roller,
+			Expression: "2d20kh1+5",
+			Total:      total,
+			Secret:     i == 2,
+			Dice: []dice.RecordedDie{
+				{Sides: 20, Value: 4, Dropped: true},
+				{Sides: 20, Value: 17},
+			},
+			CreatedAt: base.Add(time.Duration(i) * time.Second),
+		})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	// 他のユーザーの履歴は含めない
+	other := roller
+	other.UserID = "user-2"
+	if _, err := repo.Create(ctx, &dice.RollRecord{Roller: other, Expression: "1d6", Total: 1, CreatedAt: base}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	got, err := repo.ListRecent(ctx, "guild-1", "user-1", 2)
+	if err != nil {
+		t.Fatalf("ListRecent() error = %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("ListRecent() length = %v, want 2", len(got))
+	}
+	if got[0].Total != 8 || got[1].Total != 22 {
+		t.Errorf("ListRecent() totals = %v, %v, want 8, 22", got[0].Total, got[1].Total)
+	}
+	if !got[0].Secret || got[1].Secret {
+		t.Errorf("ListRecent() secrets = %v, %v, want true, false", got[0].Secret, got[1].Secret)
+	}
+	if len(got[0].Dice) != 2 || !got[0].Dice[0].Dropped || got[0].Dice[1].Value != 17 {
+		t.Errorf("ListRecent()[0].Dice = %+v, want dropped 4 and 17", got[0].Dice)
+	}
+}
+
+func TestRollRepository_CountFaces(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRollRepository(db)
+	ctx := context.Background()
+	roller := dice.Roller{GuildID: "guild-1", ChannelID: "channel-1", UserID: "user-1"}
+
+	records := []*dice.RollRecord{
+		{Dice: []dice.RecordedDie{{Sides: 20, Value: 20}}},
+		{Dice: []dice.RecordedDie{{Sides: 6, Value: 1}, {Sides: 6, Value: 1}, {Sides: 6, Value: 3}}},
+	}
+	for _, record := range records {
+		record.Roller = roller
+		record.CreatedAt = time.Now()
+		if _, err := repo.Create(ctx, record); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	got, err := repo.CountFaces(ctx, "guild-1", "user-1")
+	if err != nil {
+		t.Fatalf("CountFaces() error = %v", err)
+	}
+
+	want := []dice.FaceCount{
+		{Sides: 6, Value: 1, Count: 2},
+		{Sides: 6, Value: 3, Count: 1},
+		{Sides: 20, Value: 20, Count: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("CountFaces() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("CountFaces()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
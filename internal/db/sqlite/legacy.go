@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// legacyVersion はマイグレーション導入前のスキーマに相当するバージョン
+// マイグレーション導入前はCREATE TABLE IF NOT EXISTSでバージョン1と同じスキーマを作成していた
+const legacyVersion = 1
+
+// adoptLegacySchema はマイグレーション導入前に作成されたDBについて、
+// legacyVersionまでのマイグレーションを適用済みとして記録する
+// 導入前のDBはschema_migrationsを持たずrecruitsを持つことで判別する
+func adoptLegacySchema(ctx context.Context, db *sql.DB) error {
+	tracked, err := tableExists(ctx, db, "schema_migrations")
+	if err != nil {
+		return err
+	}
+	if tracked {
+		return nil
+	}
+	legacy, err := tableExists(ctx, db, "recruits")
+	if err != nil {
+		return err
+	}
+	if !legacy {
+		return nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin legacy schema adoption: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	for _, m := range migrations {
+		if m.version > legacyVersion {
+			break
+		}
+		if err := recordMigration(ctx, tx, m); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit legacy schema adoption: %w", err)
+	}
+	return nil
+}
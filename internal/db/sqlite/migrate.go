@@ -0,0 +1,254 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// migration はバージョン付きのスキーマ変更
+// 適用済みのマイグレーションは変更せず、スキーマを変更する場合は末尾に追加する
+type migration struct {
+	version int
+	name    string
+	up      string
+}
+
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "create recruits and participants",
+		up: `
+		-- 募集テーブル
+		CREATE TABLE recruits (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			guild_id TEXT NOT NULL,
+			channel_id TEXT NOT NULL,
+			message_id TEXT NOT NULL,
+			author_id TEXT NOT NULL,
+			max_capacity INTEGER NOT NULL,
+			status TEXT NOT NULL DEFAULT 'opened',
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP
+		);
+
+		-- 参加者テーブル
+		CREATE TABLE participants (
+			recruit_id INTEGER NOT NULL,
+			user_id TEXT NOT NULL,
+			status TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP,
+			PRIMARY KEY (recruit_id, user_id),
+			FOREIGN KEY (recruit_id) REFERENCES recruits(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX idx_recruits_status ON recruits(status);
+		CREATE INDEX idx_recruits_guild_id ON recruits(guild_id);
+		CREATE INDEX idx_participants_recruit_id ON participants(recruit_id);
+		`,
+	},
+	{
+		version: 2,
+		name:    "add start time and reminder to recruits",
+		up: `
+		ALTER TABLE recruits ADD COLUMN start_at TIMESTAMP;
+		ALTER TABLE recruits ADD COLUMN reminded_at TIMESTAMP;
+		`,
+	},
+	{
+		version: 3,
+		name:    "add close reason and reopen time to recruits",
+		up: `
+		ALTER TABLE recruits ADD COLUMN close_reason TEXT NOT NULL DEFAULT '';
+		ALTER TABLE recruits ADD COLUMN reopened_at TIMESTAMP;
+		`,
+	},
+	{
+		version: 4,
+		name:    "add title and description to recruits",
+		up: `
+		ALTER TABLE recruits ADD COLUMN title TEXT NOT NULL DEFAULT '';
+		ALTER TABLE recruits ADD COLUMN description TEXT NOT NULL DEFAULT '';
+		`,
+	},
+	{
+		version: 5,
+		name:    "add role to recruits",
+		up: `
+		ALTER TABLE recruits ADD COLUMN role_id TEXT NOT NULL DEFAULT '';
+		ALTER TABLE recruits ADD COLUMN role_restricted BOOLEAN NOT NULL DEFAULT 0;
+		`,
+	},
+	{
+		version: 6,
+		name:    "add participants user index",
+		up: `
+		CREATE INDEX idx_participants_user_id ON participants(user_id);
+		`,
+	},
+	{
+		version: 7,
+		name:    "create dice rolls",
+		up: `
+		-- ダイス履歴テーブル
+		CREATE TABLE dice_rolls (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			guild_id TEXT NOT NULL,
+			channel_id TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			expression TEXT NOT NULL,
+			total INTEGER NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
+		-- ダイス履歴の出目テーブル
+		CREATE TABLE dice_roll_results (
+			roll_id INTEGER NOT NULL,
+			position INTEGER NOT NULL,
+			sides INTEGER NOT NULL,
+			value INTEGER NOT NULL,
+			dropped BOOLEAN NOT NULL DEFAULT 0,
+			exploded BOOLEAN NOT NULL DEFAULT 0,
+			PRIMARY KEY (roll_id, position),
+			FOREIGN KEY (roll_id) REFERENCES dice_rolls(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX idx_dice_rolls_guild_user ON dice_rolls(guild_id, user_id, created_at);
+		`,
+	},
+	{
+		version: 8,
+		name:    "add secret to dice rolls",
+		up: `
+		ALTER TABLE dice_rolls ADD COLUMN secret BOOLEAN NOT NULL DEFAULT 0;
+		`,
+	},
+	{
+		version: 9,
+		name:    "create participant events",
+		up: `
+		-- 参加状態の変更履歴テーブル
+		-- from_statusがNULLの場合は初回の回答
+		CREATE TABLE participant_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			recruit_id INTEGER NOT NULL,
+			user_id TEXT NOT NULL,
+			from_status TEXT,
+			to_status TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (recruit_id) REFERENCES recruits(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX idx_participant_events_recruit_id ON participant_events(recruit_id);
+		CREATE INDEX idx_participant_events_user_id ON participant_events(user_id);
+
+		-- 既存の参加者は変更前の状態が分からないため、現在の状態を初回の回答として記録する
+		INSERT INTO participant_events (recruit_id, user_id, from_status, to_status, created_at)
+		SELECT recruit_id, user_id, NULL, status, COALESCE(updated_at, created_at)
+		FROM participants;
+		`,
+	},
+}
+
+// latestVersion は最新のスキーマバージョンを返す
+func latestVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// migrate は未適用のマイグレーションを順に全て適用する
+func migrate(ctx context.Context, db *sql.DB) error {
+	return migrateTo(ctx, db, latestVersion())
+}
+
+// migrateTo は未適用のマイグレーションをtargetのバージョンまで順に適用する
+// マイグレーションごとにトランザクションを分け、失敗した場合はそのマイグレーションのみロールバックする
+func migrateTo(ctx context.Context, db *sql.DB, target int) error {
+	if err := adoptLegacySchema(ctx, db); err != nil {
+		return err
+	}
+
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	current, err := schemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+	if current > latestVersion() {
+		return fmt.Errorf("database schema version %d is newer than supported version %d", current, latestVersion())
+	}
+
+	for _, m := range migrations {
+		if m.version <= current || m.version > target {
+			continue
+		}
+		if err := applyMigration(ctx, db, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, m migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %d: %w", m.version, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, m.up); err != nil {
+		return fmt.Errorf("failed to apply migration %d (%s): %w", m.version, m.name, err)
+	}
+	if err := recordMigration(ctx, tx, m); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %d: %w", m.version, err)
+	}
+	return nil
+}
+
+func recordMigration(ctx context.Context, exec executor, m migration) error {
+	_, err := exec.ExecContext(ctx,
+		"INSERT INTO schema_migrations (version, name) VALUES (?, ?)",
+		m.version, m.name,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", m.version, err)
+	}
+	return nil
+}
+
+// schemaVersion は適用済みの最新のスキーマバージョンを返す
+// マイグレーションを1つも適用していない場合は0を返す
+func schemaVersion(ctx context.Context, exec executor) (int, error) {
+	var version int
+	err := exec.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	return version, nil
+}
+
+func tableExists(ctx context.Context, exec executor, table string) (bool, error) {
+	var count int
+	err := exec.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?",
+		table,
+	).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check table %s: %w", table, err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,285 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// openEmptyDB はマイグレーションを適用していないテスト用のDBを作成
+// インメモリDBは接続ごとに別のDBになるため、一時ファイルを使用する
+// 外部キー制約は接続ごとの設定のため、接続文字列で全ての接続に設定する
+func openEmptyDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", t.TempDir()+"/test.db?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// schemaSnapshot はテーブルごとのカラム定義とインデックス名を返す
+// ALTER TABLEで追加したカラムとCREATE TABLEで定義したカラムを比較できるよう、カラムは名前順に並べる
+func schemaSnapshot(t *testing.T, db *sql.DB) map[string][]string {
+	t.Helper()
+	ctx := context.Background()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT type, name, tbl_name FROM sqlite_master
+		WHERE type IN ('table', 'index') AND name NOT LIKE 'sqlite_%'
+	`)
+	if err != nil {
+		t.Fatalf("failed to query sqlite_master: %v", err)
+	}
+	defer rows.Close()
+
+	snapshot := make(map[string][]string)
+	var tables []string
+	for rows.Next() {
+		var typ, name, table string
+		if err := rows.Scan(&typ, &name, &table); err != nil {
+			t.Fatalf("failed to scan sqlite_master: %v", err)
+		}
+		if typ == "table" {
+			tables = append(tables, name)
+			continue
+		}
+		snapshot["index:"+table] = append(snapshot["index:"+table], name)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("failed to iterate sqlite_master: %v", err)
+	}
+
+	for _, table := range tables {
+		columns, err := db.QueryContext(ctx, fmt.Sprintf("PRAGMA table_info(%s)", table))
+		if err != nil {
+			t.Fatalf("failed to get table info: %v", err)
+		}
+		for columns.Next() {
+			var (
+				cid        int
+				name       string
+				columnType string
+				notNull    int
+				defaultVal sql.NullString
+				primaryKey int
+			)
+			if err := columns.Scan(&cid, &name, &columnType, &notNull, &defaultVal, &primaryKey); err != nil {
+				t.Fatalf("failed to scan table info: %v", err)
+			}
+			snapshot[table] = append(snapshot[table], fmt.Sprintf(
+				"%s %s notnull=%d default=%s pk=%d",
+				name, columnType, notNull, defaultVal.String, primaryKey,
+			))
+		}
+		columns.Close()
+	}
+
+	for _, values := range snapshot {
+		sort.Strings(values)
+	}
+	return snapshot
+}
+
+func latestSnapshot(t *testing.T) map[string][]string {
+	t.Helper()
+
+	db := openEmptyDB(t)
+	if err := migrate(context.Background(), db); err != nil {
+		t.Fatalf("migrate() error = %v", err)
+	}
+	return schemaSnapshot(t, db)
+}
+
+func assertSchemaVersion(t *testing.T, db *sql.DB, want int) {
+	t.Helper()
+
+	got, err := schemaVersion(context.Background(), db)
+	if err != nil {
+		t.Fatalf("schemaVersion() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("schemaVersion() = %v, want %v", got, want)
+	}
+}
+
+func TestMigrations_Ordered(t *testing.T) {
+	for i, m := range migrations {
+		if m.version != i+1 {
+			t.Errorf("migrations[%d].version = %v, want %v", i, m.version, i+1)
+		}
+	}
+}
+
+func TestMigrate_FromEmpty(t *testing.T) {
+	db := openEmptyDB(t)
+	ctx := context.Background()
+
+	if err := migrate(ctx, db); err != nil {
+		t.Fatalf("migrate() error = %v", err)
+	}
+	assertSchemaVersion(t, db, latestVersion())
+
+	// 再実行しても何も適用しない
+	if err := migrate(ctx, db); err != nil {
+		t.Fatalf("migrate() second run error = %v", err)
+	}
+	assertSchemaVersion(t, db, latestVersion())
+
+	for _, table := range []string{"recruits", "participants", "dice_rolls", "dice_roll_results"} {
+		exists, err := tableExists(ctx, db, table)
+		if err != nil {
+			t.Fatalf("tableExists() error = %v", err)
+		}
+		if !exists {
+			t.Errorf("table %s does not exist", table)
+		}
+	}
+}
+
+func TestMigrate_FromEachVersion(t *testing.T) {
+	want := latestSnapshot(t)
+
+	for version := 1; version < latestVersion(); version++ {
+		t.Run(fmt.Sprintf("バージョン%dから最新まで適用", version), func(t *testing.T) {
+			db := openEmptyDB(t)
+			ctx := context.Background()
+
+			if err := migrateTo(ctx, db, version); err != nil {
+				t.Fatalf("migrateTo(%d) error = %v", version, err)
+			}
+			assertSchemaVersion(t, db, version)
+
+			if err := migrate(ctx, db); err != nil {
+				t.Fatalf("migrate() error = %v", err)
+			}
+			assertSchemaVersion(t, db, latestVersion())
+
+			if got := schemaSnapshot(t, db); !reflect.DeepEqual(got, want) {
+				t.Errorf("schema = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMigrate_LegacySchema(t *testing.T) {
+	db := openEmptyDB(t)
+	ctx := context.Background()
+
+	// マイグレーション導入前に作成していたスキーマ
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE recruits (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			guild_id TEXT NOT NULL,
+			channel_id TEXT NOT NULL,
+			message_id TEXT NOT NULL,
+			author_id TEXT NOT NULL,
+			max_capacity INTEGER NOT NULL,
+			status TEXT NOT NULL DEFAULT 'opened',
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP
+		);
+		CREATE TABLE participants (
+			recruit_id INTEGER NOT NULL,
+			user_id TEXT NOT NULL,
+			status TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP,
+			PRIMARY KEY (recruit_id, user_id),
+			FOREIGN KEY (recruit_id) REFERENCES recruits(id) ON DELETE CASCADE
+		);
+		CREATE INDEX idx_recruits_status ON recruits(status);
+		CREATE INDEX idx_recruits_guild_id ON recruits(guild_id);
+		CREATE INDEX idx_participants_recruit_id ON participants(recruit_id);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create legacy schema: %v", err)
+	}
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO recruits (guild_id, channel_id, message_id, author_id, max_capacity)
+		VALUES ('guild-1', 'channel-1', 'message-1', 'author-1', 3)
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert legacy recruit: %v", err)
+	}
+
+	if err := migrate(ctx, db); err != nil {
+		t.Fatalf("migrate() error = %v", err)
+	}
+	assertSchemaVersion(t, db, latestVersion())
+
+	if got, want := schemaSnapshot(t, db), latestSnapshot(t); !reflect.DeepEqual(got, want) {
+		t.Errorf("schema = %v, want %v", got, want)
+	}
+
+	// 既存のデータは保持され、追加したカラムは既定値になる
+	var messageID, closeReason string
+	err = db.QueryRowContext(ctx, "SELECT message_id, close_reason FROM recruits").Scan(&messageID, &closeReason)
+	if err != nil {
+		t.Fatalf("failed to query recruit: %v", err)
+	}
+	if messageID != "message-1" || closeReason != "" {
+		t.Errorf("recruit = (%q, %q), want (message-1, empty)", messageID, closeReason)
+	}
+}
+
+func TestMigrate_ParticipantEvents(t *testing.T) {
+	db := openEmptyDB(t)
+	ctx := context.Background()
+
+	if err := migrateTo(ctx, db, 8); err != nil {
+		t.Fatalf("migrateTo(8) error = %v", err)
+	}
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO recruits (id, guild_id, channel_id, message_id, author_id, max_capacity)
+		VALUES (1, 'guild-1', 'channel-1', 'message-1', 'author-1', 3);
+		INSERT INTO participants (recruit_id, user_id, status)
+		VALUES (1, 'user-1', 'canceled');
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert participant: %v", err)
+	}
+
+	if err := migrate(ctx, db); err != nil {
+		t.Fatalf("migrate() error = %v", err)
+	}
+
+	// 変更前の状態が分からない既存の参加者は、現在の状態を初回の回答として記録する
+	var userID, toStatus string
+	var fromStatus sql.NullString
+	err = db.QueryRowContext(ctx, "SELECT user_id, from_status, to_status FROM participant_events").
+		Scan(&userID, &fromStatus, &toStatus)
+	if err != nil {
+		t.Fatalf("failed to query participant event: %v", err)
+	}
+	if userID != "user-1" || fromStatus.Valid || toStatus != "canceled" {
+		t.Errorf("event = (%q, %v, %q), want (user-1, NULL, canceled)", userID, fromStatus, toStatus)
+	}
+}
+
+func TestMigrate_NewerVersion(t *testing.T) {
+	db := openEmptyDB(t)
+	ctx := context.Background()
+
+	if err := migrate(ctx, db); err != nil {
+		t.Fatalf("migrate() error = %v", err)
+	}
+	_, err := db.ExecContext(ctx,
+		"INSERT INTO schema_migrations (version, name) VALUES (?, ?)",
+		latestVersion()+1, "future",
+	)
+	if err != nil {
+		t.Fatalf("failed to insert future migration: %v", err)
+	}
+
+	if err := migrate(ctx, db); err == nil {
+		t.Error("migrate() error = nil, want error for newer schema")
+	}
+}
@@ -9,6 +9,66 @@ import (
 	"time"
 )
 
+// recruitColumns はrecruitsテーブルのSELECT対象カラム
+// scanRecruitのScan順と一致させること
+const recruitColumns = `
+	id, guild_id, channel_id, message_id, author_id, max_capacity, status,
+	start_at, reminded_at, close_reason, reopened_at, title, description,
+	role_id, role_restricted, created_at, updated_at
+`
+
+// rowScanner はsql.Rowとsql.Rowsの共通インターフェース
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRecruit(row rowScanner) (*recruit.RecruitState, error) {
+	var state recruit.RecruitState
+	var startAt, remindedAt, reopenedAt, updatedAt sql.NullTime
+	err := row.Scan(
+		&state.ID,
+		&state.GuildID,
+		&state.ChannelID,
+		&state.MessageID,
+		&state.AuthorID,
+		&state.MaxCapacity,
+		&state.Status,
+		&startAt,
+		&remindedAt,
+		&state.CloseReason,
+		&reopenedAt,
+		&state.Title,
+		&state.Description,
+		&state.RoleID,
+		&state.RoleRestricted,
+		&state.CreatedAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	state.StartAt = nullTimeToPtr(startAt)
+	state.RemindedAt = nullTimeToPtr(remindedAt)
+	state.ReopenedAt = nullTimeToPtr(reopenedAt)
+	state.UpdatedAt = nullTimeToPtr(updatedAt)
+	return &state, nil
+}
+
+func nullTimeToPtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func ptrToNullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
 type sqliteRecruitRepository struct {
 	db *sql.DB
 }
@@ -22,28 +82,12 @@ func NewRecruitRepository(db *sql.DB) recruit.RecruitRepository {
 func (r *sqliteRecruitRepository) Get(ctx context.Context, id recruit.RecruitID) (*recruit.RecruitState, error) {
 	executor := GetExecutor(ctx, r.db)
 
-	query := `
-		SELECT id, guild_id, channel_id, message_id, author_id, max_capacity, status, created_at, updated_at
+	query := `SELECT ` + recruitColumns + `
 		FROM recruits
 		WHERE id = ?
 	`
 
-	var state recruit.RecruitState
-	var updatedAt sql.NullTime
-	err := executor.
-		QueryRowContext(ctx, query, id).
-		Scan(
-			&state.ID,
-			&state.GuildID,
-			&state.ChannelID,
-			&state.MessageID,
-			&state.AuthorID,
-			&state.MaxCapacity,
-			&state.Status,
-			&state.CreatedAt,
-			&updatedAt,
-		)
-
+	state, err := scanRecruit(executor.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("recruit not found: %d", id)
 	}
@@ -52,11 +96,7 @@ func (r *sqliteRecruitRepository) Get(ctx context.Context, id recruit.RecruitID)
 		return nil, fmt.Errorf("failed to get recruit: %w", err)
 	}
 
-	if updatedAt.Valid {
-		state.UpdatedAt = &updatedAt.Time
-	}
-
-	return &state, nil
+	return state, nil
 }
 
 func (r *sqliteRecruitRepository) GetByMessage(
@@ -66,49 +106,33 @@ func (r *sqliteRecruitRepository) GetByMessage(
 ) (*recruit.RecruitState, error) {
 	executor := GetExecutor(ctx, r.db)
 
-	query := `
-		SELECT id, guild_id, channel_id, message_id, author_id, max_capacity, status, created_at, updated_at
+	query := `SELECT ` + recruitColumns + `
 		FROM recruits
-		WHERE channel_id = ? AND message_id = ?
+		WHERE channel_id = ? AND message_id = ? AND close_reason <> ?
 	`
 
-	var state recruit.RecruitState
-	var updatedAt sql.NullTime
-	err := executor.
-		QueryRowContext(ctx, query, channelID, messageID).
-		Scan(
-			&state.ID,
-			&state.GuildID,
-			&state.ChannelID,
-			&state.MessageID,
-			&state.AuthorID,
-			&state.MaxCapacity,
-			&state.Status,
-			&state.CreatedAt,
-			&updatedAt,
-		)
-
+	// 作成者が削除した募集はアーカイブとして残っているが、メッセージからは取得できない
+	state, err := scanRecruit(executor.QueryRowContext(ctx, query, channelID, messageID, recruit.CloseReasonDeleted))
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("recruit not found: %s, %s", channelID, messageID)
+		return nil, fmt.Errorf("%w: %s, %s", recruit.ErrRecruitNotFound, channelID, messageID)
 	}
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get recruit: %w", err)
 	}
 
-	if updatedAt.Valid {
-		state.UpdatedAt = &updatedAt.Time
-	}
-
-	return &state, nil
+	return state, nil
 }
 
 func (r *sqliteRecruitRepository) Create(ctx context.Context, state *recruit.RecruitState) (recruit.RecruitID, error) {
 	executor := GetExecutor(ctx, r.db)
 
 	query := `
-		INSERT INTO recruits (guild_id, channel_id, message_id, author_id, max_capacity, status, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO recruits (
+			guild_id, channel_id, message_id, author_id, max_capacity, status,
+			start_at, title, description, role_id, role_restricted, created_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := executor.ExecContext(
@@ -120,6 +144,11 @@ func (r *sqliteRecruitRepository) Create(ctx context.Context, state *recruit.Rec
 		state.AuthorID,
 		state.MaxCapacity,
 		state.Status,
+		ptrToNullTime(state.StartAt),
+		state.Title,
+		state.Description,
+		state.RoleID,
+		state.RoleRestricted,
 		state.CreatedAt,
 	)
 
@@ -142,7 +171,9 @@ func (r *sqliteRecruitRepository) Update(ctx context.Context, state *recruit.Rec
 	query := `
 		UPDATE recruits
 		SET guild_id = ?, channel_id = ?, message_id = ?, author_id = ?,
-		    max_capacity = ?, status = ?, updated_at = ?
+		    max_capacity = ?, status = ?, start_at = ?, reminded_at = ?, close_reason = ?,
+		    reopened_at = ?, title = ?, description = ?, role_id = ?, role_restricted = ?,
+		    updated_at = ?
 		WHERE id = ?
 	`
 
@@ -155,6 +186,14 @@ func (r *sqliteRecruitRepository) Update(ctx context.Context, state *recruit.Rec
 		state.AuthorID,
 		state.MaxCapacity,
 		state.Status,
+		ptrToNullTime(state.StartAt),
+		ptrToNullTime(state.RemindedAt),
+		state.CloseReason,
+		ptrToNullTime(state.ReopenedAt),
+		state.Title,
+		state.Description,
+		state.RoleID,
+		state.RoleRestricted,
 		now,
 		state.ID,
 	)
@@ -197,6 +236,98 @@ func (r *sqliteRecruitRepository) Delete(ctx context.Context, id recruit.Recruit
 	return nil
 }
 
+func (r *sqliteRecruitRepository) ListScheduled(ctx context.Context, until time.Time) ([]*recruit.RecruitState, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	// 保存時のタイムゾーンに依存しないようjuliandayで比較する
+	query := `SELECT ` + recruitColumns + `
+		FROM recruits
+		WHERE status = ? AND start_at IS NOT NULL AND julianday(start_at) <= julianday(?)
+		ORDER BY start_at ASC
+	`
+
+	rows, err := executor.QueryContext(ctx, query, recruit.RecruitStatusOpened, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled recruits: %w", err)
+	}
+
+	return scanRecruits(rows)
+}
+
+func (r *sqliteRecruitRepository) ListExpired(ctx context.Context, before time.Time) ([]*recruit.RecruitState, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	query := `SELECT ` + recruitColumns + `
+		FROM recruits
+		WHERE status != ?
+		  AND max(
+		      julianday(created_at),
+		      COALESCE(julianday(start_at), 0),
+		      COALESCE(julianday(reopened_at), 0)
+		  ) <= julianday(?)
+		ORDER BY created_at ASC
+	`
+
+	rows, err := executor.QueryContext(ctx, query, recruit.RecruitStatusClosed, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired recruits: %w", err)
+	}
+
+	return scanRecruits(rows)
+}
+
+func (r *sqliteRecruitRepository) ListOpened(
+	ctx context.Context,
+	guildID recruit.GuildID,
+	channelID recruit.ChannelID,
+) ([]*recruit.RecruitState, error) {
+	executor := GetExecutor(ctx, r.db)
+
+	// idx_recruits_guild_id, idx_recruits_status を利用できるよう等価条件で絞り込む
+	query := `SELECT ` + recruitColumns + `
+		FROM recruits
+		WHERE guild_id = ?
+		  AND status IN (?, ?)
+		  AND (? = '' OR channel_id = ?)
+		ORDER BY created_at ASC
+	`
+
+	rows, err := executor.QueryContext(
+		ctx,
+		query,
+		guildID,
+		recruit.RecruitStatusOpened,
+		recruit.RecruitStatusStarted,
+		channelID,
+		channelID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list opened recruits: %w", err)
+	}
+
+	return scanRecruits(rows)
+}
+
+// scanRecruits は複数行の募集を読み込み、rowsをクローズする
+func scanRecruits(rows *sql.Rows) ([]*recruit.RecruitState, error) {
+	defer rows.Close()
+
+	var states []*recruit.RecruitState
+	for rows.Next() {
+		state, err := scanRecruit(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan recruit: %w", err)
+		}
+		states = append(states, state)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate recruits: %w", err)
+	}
+
+	return states, nil
+}
+
 type sqliteParticipantRepository struct {
 	db *sql.DB
 }
@@ -211,6 +342,10 @@ func (r *sqliteParticipantRepository) Upsert(ctx context.Context, recruitID recr
 	executor := GetExecutor(ctx, r.db)
 
 	now := time.Now()
+	if err := r.recordEvent(ctx, executor, recruitID, userID, status, now); err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO participants (recruit_id, user_id, status, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?)
@@ -227,6 +362,37 @@ func (r *sqliteParticipantRepository) Upsert(ctx context.Context, recruitID recr
 	return nil
 }
 
+// recordEvent は参加状態が変わる場合に変更前の状態とともに履歴に記録する
+func (r *sqliteParticipantRepository) recordEvent(
+	ctx context.Context,
+	executor executor,
+	recruitID recruit.RecruitID,
+	userID recruit.UserID,
+	status recruit.ParticipantStatus,
+	now time.Time,
+) error {
+	var previous sql.NullString
+	err := executor.QueryRowContext(ctx,
+		`SELECT status FROM participants WHERE recruit_id = ? AND user_id = ?`,
+		recruitID, userID,
+	).Scan(&previous)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get participant status: %w", err)
+	}
+	if previous.Valid && recruit.ParticipantStatus(previous.String) == status {
+		return nil
+	}
+
+	_, err = executor.ExecContext(ctx, `
+		INSERT INTO participant_events (recruit_id, user_id, from_status, to_status, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, recruitID, userID, previous, status, now)
+	if err != nil {
+		return fmt.Errorf("failed to record participant event: %w", err)
+	}
+	return nil
+}
+
 func (r *sqliteParticipantRepository) FindByRecruitAndUser(ctx context.Context, recruitID recruit.RecruitID, userID recruit.UserID) (*recruit.Participant, error) {
 	executor := GetExecutor(ctx, r.db)
 
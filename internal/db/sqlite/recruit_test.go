@@ -19,33 +19,8 @@ func setupTestDB(t *testing.T) *sql.DB {
 		t.Fatalf("failed to open database: %v", err)
 	}
 
-	// テーブル作成
-	schema := `
-	CREATE TABLE IF NOT EXISTS recruits (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		guild_id TEXT NOT NULL,
-		channel_id TEXT NOT NULL,
-		message_id TEXT NOT NULL,
-		author_id TEXT NOT NULL,
-		max_capacity INTEGER NOT NULL,
-		status TEXT NOT NULL,
-		created_at DATETIME NOT NULL,
-		updated_at DATETIME
-	);
-
-	CREATE TABLE IF NOT EXISTS participants (
-		recruit_id INTEGER NOT NULL,
-		user_id TEXT NOT NULL,
-		status TEXT NOT NULL,
-		created_at DATETIME NOT NULL,
-		updated_at DATETIME,
-		PRIMARY KEY (recruit_id, user_id),
-		FOREIGN KEY (recruit_id) REFERENCES recruits(id) ON DELETE CASCADE
-	);
-	`
-
-	if _, err := db.Exec(schema); err != nil {
-		t.Fatalf("failed to create tables: %v", err)
+	if err := migrate(context.Background(), db); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
 	}
 
 	return db
@@ -87,13 +62,17 @@ func TestRecruitRepository_Get(t *testing.T) {
 
 	// データを作成
 	state := &recruit.RecruitState{
-		GuildID:     "guild-1",
-		ChannelID:   "channel-1",
-		MessageID:   "message-1",
-		AuthorID:    "author-1",
-		MaxCapacity: 5,
-		Status:      recruit.RecruitStatusOpened,
-		CreatedAt:   time.Now(),
+		GuildID:        "guild-1",
+		ChannelID:      "channel-1",
+		MessageID:      "message-1",
+		AuthorID:       "author-1",
+		MaxCapacity:    5,
+		Status:         recruit.RecruitStatusOpened,
+		Title:          "Apex ランク",
+		Description:    "ゴールド帯\nVC必須",
+		RoleID:         "role-1",
+		RoleRestricted: true,
+		CreatedAt:      time.Now(),
 	}
 
 	id, err := repo.Create(ctx, state)
@@ -128,6 +107,15 @@ func TestRecruitRepository_Get(t *testing.T) {
 	if got.Status != state.Status {
 		t.Errorf("Get() Status = %v, want %v", got.Status, state.Status)
 	}
+	if got.Title != state.Title {
+		t.Errorf("Get() Title = %v, want %v", got.Title, state.Title)
+	}
+	if got.Description != state.Description {
+		t.Errorf("Get() Description = %v, want %v", got.Description, state.Description)
+	}
+	if got.RoleID != state.RoleID || got.RoleRestricted != state.RoleRestricted {
+		t.Errorf("Get() Role = %v/%v, want %v/%v", got.RoleID, got.RoleRestricted, state.RoleID, state.RoleRestricted)
+	}
 }
 
 func TestRecruitRepository_GetByMessage(t *testing.T) {
@@ -194,7 +182,10 @@ func TestRecruitRepository_Update(t *testing.T) {
 	// 更新
 	state.ID = id
 	state.Status = recruit.RecruitStatusClosed
+	state.CloseReason = recruit.CloseReasonExpired
 	state.MaxCapacity = 10
+	remindedAt := time.Now()
+	state.RemindedAt = &remindedAt
 
 	err = repo.Update(ctx, state)
 	if err != nil {
@@ -213,6 +204,12 @@ func TestRecruitRepository_Update(t *testing.T) {
 	if got.MaxCapacity != 10 {
 		t.Errorf("Update() MaxCapacity = %v, want 10", got.MaxCapacity)
 	}
+	if got.CloseReason != recruit.CloseReasonExpired {
+		t.Errorf("Update() CloseReason = %v, want %v", got.CloseReason, recruit.CloseReasonExpired)
+	}
+	if got.RemindedAt == nil || !got.RemindedAt.Equal(remindedAt) {
+		t.Errorf("Update() RemindedAt = %v, want %v", got.RemindedAt, remindedAt)
+	}
 }
 
 func TestRecruitRepository_Delete(t *testing.T) {
@@ -250,6 +247,167 @@ func TestRecruitRepository_Delete(t *testing.T) {
 	}
 }
 
+func TestRecruitRepository_ListScheduled(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRecruitRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	soon := now.Add(10 * time.Minute)
+	later := now.Add(2 * time.Hour)
+	// 保存時と異なるタイムゾーンで比較しても正しく判定されること
+	utcSoon := soon.UTC()
+
+	states := []*recruit.RecruitState{
+		{MessageID: "soon", Status: recruit.RecruitStatusOpened, StartAt: &soon},
+		{MessageID: "later", Status: recruit.RecruitStatusOpened, StartAt: &later},
+		{MessageID: "unscheduled", Status: recruit.RecruitStatusOpened},
+		{MessageID: "started", Status: recruit.RecruitStatusStarted, StartAt: &soon},
+		{MessageID: "utc", Status: recruit.RecruitStatusOpened, StartAt: &utcSoon},
+	}
+	for _, state := range states {
+		state.GuildID = "guild-1"
+		state.ChannelID = "channel-1"
+		state.AuthorID = "author-1"
+		state.MaxCapacity = 5
+		state.CreatedAt = now
+		if _, err := repo.Create(ctx, state); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	got, err := repo.ListScheduled(ctx, now.Add(30*time.Minute))
+	if err != nil {
+		t.Fatalf("ListScheduled() error = %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("ListScheduled() length = %v, want 2", len(got))
+	}
+	for _, state := range got {
+		if state.MessageID != "soon" && state.MessageID != "utc" {
+			t.Errorf("ListScheduled() returned unexpected recruit: %v", state.MessageID)
+		}
+		if state.StartAt == nil || !state.StartAt.Equal(soon) {
+			t.Errorf("ListScheduled() StartAt = %v, want %v", state.StartAt, soon)
+		}
+	}
+}
+
+func TestRecruitRepository_ListExpired(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRecruitRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+	future := now.Add(time.Hour)
+
+	states := []*recruit.RecruitState{
+		{MessageID: "old", Status: recruit.RecruitStatusOpened, CreatedAt: old},
+		{MessageID: "old-started", Status: recruit.RecruitStatusStarted, CreatedAt: old},
+		{MessageID: "old-closed", Status: recruit.RecruitStatusClosed, CreatedAt: old},
+		{MessageID: "new", Status: recruit.RecruitStatusOpened, CreatedAt: now},
+		// 作成日時が古くても開始時刻が未来なら対象外
+		{MessageID: "old-scheduled", Status: recruit.RecruitStatusOpened, CreatedAt: old, StartAt: &future},
+		// 作成日時が古くても最近再開していれば対象外
+		{MessageID: "old-reopened", Status: recruit.RecruitStatusOpened, CreatedAt: old, ReopenedAt: &now},
+	}
+	for _, state := range states {
+		state.GuildID = "guild-1"
+		state.ChannelID = "channel-1"
+		state.AuthorID = "author-1"
+		state.MaxCapacity = 5
+		id, err := repo.Create(ctx, state)
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		// 再開日時は作成時には保存されないため更新で反映する
+		state.ID = id
+		if err := repo.Update(ctx, state); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+
+	got, err := repo.ListExpired(ctx, now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("ListExpired() error = %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("ListExpired() length = %v, want 2", len(got))
+	}
+	for _, state := range got {
+		if state.MessageID != "old" && state.MessageID != "old-started" {
+			t.Errorf("ListExpired() returned unexpected recruit: %v", state.MessageID)
+		}
+	}
+}
+
+func TestRecruitRepository_ListOpened(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRecruitRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	states := []*recruit.RecruitState{
+		{MessageID: "opened", GuildID: "guild-1", ChannelID: "channel-1", Status: recruit.RecruitStatusOpened},
+		{MessageID: "started", GuildID: "guild-1", ChannelID: "channel-1", Status: recruit.RecruitStatusStarted},
+		{MessageID: "other-channel", GuildID: "guild-1", ChannelID: "channel-2", Status: recruit.RecruitStatusOpened},
+		{MessageID: "closed", GuildID: "guild-1", ChannelID: "channel-1", Status: recruit.RecruitStatusClosed},
+		{MessageID: "other-guild", GuildID: "guild-2", ChannelID: "channel-3", Status: recruit.RecruitStatusOpened},
+	}
+	for i, state := range states {
+		state.AuthorID = "author-1"
+		state.MaxCapacity = 5
+		state.CreatedAt = now.Add(time.Duration(i) * time.Minute)
+		if _, err := repo.Create(ctx, state); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		channelID recruit.ChannelID
+		want      []recruit.MessageID
+	}{
+		{
+			name:      "チャンネル未指定の場合はギルド内の全チャンネル",
+			channelID: "",
+			want:      []recruit.MessageID{"opened", "started", "other-channel"},
+		},
+		{
+			name:      "チャンネル指定の場合はチャンネル内のみ",
+			channelID: "channel-1",
+			want:      []recruit.MessageID{"opened", "started"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.ListOpened(ctx, "guild-1", tt.channelID)
+			if err != nil {
+				t.Fatalf("ListOpened() error = %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("ListOpened() length = %v, want %v", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got[i].MessageID != want {
+					t.Errorf("ListOpened()[%d].MessageID = %v, want %v", i, got[i].MessageID, want)
+				}
+			}
+		})
+	}
+}
+
 func TestParticipantRepository_Upsert(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -1,71 +1,86 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
-func InitDB(dbPath string) (*sql.DB, error) {
-	dir := filepath.Dir(dbPath)
+// ErrDatabaseInUse は他のプロセスがデータベースを開いているため排他的に開けない場合のエラー
+var ErrDatabaseInUse = errors.New("database is in use by another process")
+
+func InitDB(config Config) (*sql.DB, error) {
+	db, err := open(config, config.dsn(), config.MaxOpenConns)
+	if err != nil {
+		return nil, err
+	}
+	// スキーマのマイグレーション
+	if err := migrate(context.Background(), db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// OpenExclusive はマイグレーションを行わずにデータベースを開き、閉じるまで他の接続を排除する
+// Botが稼働中などで他のプロセスがデータベースを開いている場合はErrDatabaseInUseを返す
+// 復元のように他の接続の書き込みを失わせる操作に使用する
+func OpenExclusive(config Config) (*sql.DB, error) {
+	params := config.params()
+	// 排他ロックを接続を閉じるまで保持する
+	params.Set("_locking_mode", "EXCLUSIVE")
+	// ロックは1つの接続が保持するため、全ての操作を同じ接続で行う
+	db, err := open(config, config.Path+"?"+params.Encode(), 1)
+	if err != nil {
+		return nil, err
+	}
+
+	// 書き込みロックを取得し、以降は他の接続が読み書きできないようにする
+	if _, err := db.Exec("BEGIN EXCLUSIVE"); err != nil {
+		db.Close()
+		return nil, inUseError(err)
+	}
+	if _, err := db.Exec("COMMIT"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to lock database: %w", err)
+	}
+	return db, nil
+}
+
+// open はデータベースに接続する
+func open(config Config, dsn string, maxOpenConns int) (*sql.DB, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(config.Path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create db directory: %w", err)
 	}
 	// データベース接続
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
-	// 外部キー制約を有効化
-	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
-		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
-	}
-	// テーブル作成
-	if err := createTables(db); err != nil {
+	db.SetMaxOpenConns(maxOpenConns)
+	// 接続を閉じるとWALのチェックポイントなどの処理が走るため、アイドル接続を保持する
+	db.SetMaxIdleConns(maxOpenConns)
+	if err := db.Ping(); err != nil {
 		db.Close()
-		return nil, err
+		return nil, inUseError(fmt.Errorf("failed to connect to database: %w", err))
 	}
 	return db, nil
 }
 
-func createTables(db *sql.DB) error {
-	schema := `
-	-- 募集テーブル
-	CREATE TABLE IF NOT EXISTS recruits (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		guild_id TEXT NOT NULL,
-		channel_id TEXT NOT NULL,
-		message_id TEXT NOT NULL,
-		author_id TEXT NOT NULL,
-		max_capacity INTEGER NOT NULL,
-		status TEXT NOT NULL DEFAULT 'opened',
-		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMP
-	);
-
-	-- 参加者テーブル
-	CREATE TABLE IF NOT EXISTS participants (
-		recruit_id INTEGER NOT NULL,
-		user_id TEXT NOT NULL,
-		status TEXT NOT NULL,
-		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMP,
-		PRIMARY KEY (recruit_id, user_id),
-		FOREIGN KEY (recruit_id) REFERENCES recruits(id) ON DELETE CASCADE
-	);
-
-	-- インデックス
-	CREATE INDEX IF NOT EXISTS idx_recruits_status ON recruits(status);
-	CREATE INDEX IF NOT EXISTS idx_recruits_guild_id ON recruits(guild_id);
-	CREATE INDEX IF NOT EXISTS idx_participants_recruit_id ON participants(recruit_id);
-	`
-
-	if _, err := db.Exec(schema); err != nil {
-		return fmt.Errorf("failed to create tables: %w", err)
+// inUseError は他の接続がロックを保持していることによるエラーをErrDatabaseInUseに変換する
+func inUseError(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && (sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked) {
+		return fmt.Errorf("%w: %v", ErrDatabaseInUse, err)
 	}
-
-	return nil
+	return err
 }
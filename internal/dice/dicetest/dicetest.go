@@ -0,0 +1,86 @@
+// Package dicetest はdiceのリポジトリ実装が満たすべき振る舞いを検証する共通のテストスイートを提供する
+package dicetest
+
+import (
+	"at-bot/internal/dice"
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Run は空のDBに接続したリポジトリに対して共通のテストを実行する
+// newRepositoryはサブテストごとに呼び出され、他のサブテストのデータが見えないリポジトリを返すこと
+func Run(t *testing.T, newRepository func(t *testing.T) dice.RollRepository) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo dice.RollRepository)
+	}{
+		{name: "出目ごとの回数を振った個数が多い面数の順に返す", fn: testCountFaces},
+		{name: "シークレットダイスの出目は回数に含めない", fn: testCountFacesExcludesSecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepository(t))
+		})
+	}
+}
+
+// baseTime はDBごとの時刻の精度の違いを受けないよう秒単位に丸めた基準時刻
+var baseTime = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+var roller = dice.Roller{GuildID: "guild-1", ChannelID: "channel-1", UserID: "user-1"}
+
+func create(t *testing.T, repo dice.RollRepository, record *dice.RollRecord) {
+	t.Helper()
+
+	if record.Roller == (dice.Roller{}) {
+		record.Roller = roller
+	}
+	record.Expression = "1d20"
+	record.CreatedAt = baseTime
+	if _, err := repo.Create(context.Background(), record); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+}
+
+func countFaces(t *testing.T, repo dice.RollRepository) []dice.FaceCount {
+	t.Helper()
+
+	got, err := repo.CountFaces(context.Background(), roller.GuildID, roller.UserID)
+	if err != nil {
+		t.Fatalf("CountFaces() error = %v", err)
+	}
+	return got
+}
+
+func testCountFaces(t *testing.T, repo dice.RollRepository) {
+	create(t, repo, &dice.RollRecord{Dice: []dice.RecordedDie{{Sides: 20, Value: 20}}})
+	create(t, repo, &dice.RollRecord{Dice: []dice.RecordedDie{{Sides: 6, Value: 3}, {Sides: 6, Value: 1}, {Sides: 6, Value: 1}}})
+	// 他のユーザーの出目は含めない
+	other := roller
+	other.UserID = "user-2"
+	create(t, repo, &dice.RollRecord{Roller: other, Dice: []dice.RecordedDie{{Sides: 6, Value: 6}}})
+
+	want := []dice.FaceCount{
+		{Sides: 6, Value: 1, Count: 2},
+		{Sides: 6, Value: 3, Count: 1},
+		{Sides: 20, Value: 20, Count: 1},
+	}
+	if got := countFaces(t, repo); !slices.Equal(got, want) {
+		t.Errorf("CountFaces() = %+v, want %+v", got, want)
+	}
+}
+
+func testCountFacesExcludesSecret(t *testing.T, repo dice.RollRepository) {
+	create(t, repo, &dice.RollRecord{Dice: []dice.RecordedDie{{Sides: 20, Value: 7}}})
+	before := countFaces(t, repo)
+
+	// 統計は公開されるため、前後の差からシークレットダイスの出目がわからないようにする
+	create(t, repo, &dice.RollRecord{Secret: true, Dice: []dice.RecordedDie{{Sides: 20, Value: 13}, {Sides: 6, Value: 2}}})
+
+	if got := countFaces(t, repo); !slices.Equal(got, before) {
+		t.Errorf("CountFaces() after secret roll = %+v, want %+v", got, before)
+	}
+}
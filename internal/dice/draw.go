@@ -0,0 +1,47 @@
+package dice
+
+import "errors"
+
+var (
+	ErrNoCandidates     = errors.New("対象のメンバーがいません")
+	ErrInvalidTeamCount = errors.New("チーム数は2以上、メンバー数以下で指定してください")
+)
+
+// Pick は0以上n未満の番号を無作為に1つ選ぶ
+func (uc *DiceUsecase) Pick(n int) (int, error) {
+	if n < 1 {
+		return 0, ErrNoCandidates
+	}
+	return uc.source.IntN(n), nil
+}
+
+// Shuffle は0からn-1までの番号を無作為に並べ替えて返す
+func (uc *DiceUsecase) Shuffle(n int) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	// Fisher-Yates
+	for i := n - 1; i > 0; i-- {
+		j := uc.source.IntN(i + 1)
+		order[i], order[j] = order[j], order[i]
+	}
+	return order
+}
+
+// SplitTeams は0からn-1までの番号を無作為にteams個のチームに分ける
+// 各チームの人数の差は1以内になる
+func (uc *DiceUsecase) SplitTeams(n int, teams int) ([][]int, error) {
+	if n < 1 {
+		return nil, ErrNoCandidates
+	}
+	if teams < 2 || teams > n {
+		return nil, ErrInvalidTeamCount
+	}
+
+	result := make([][]int, teams)
+	for i, member := range uc.Shuffle(n) {
+		result[i%teams] = append(result[i%teams], member)
+	}
+	return result, nil
+}
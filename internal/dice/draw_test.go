@@ -0,0 +1,82 @@
+package dice
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+func TestDiceUsecase_Pick(t *testing.T) {
+	uc := NewDiceUsecase(NewSeededSource(1), &mockRollRepository{}, &mockUnitOfWork{})
+
+	for range 100 {
+		got, err := uc.Pick(3)
+		if err != nil {
+			t.Fatalf("Pick() error = %v", err)
+		}
+		if got < 0 || got >= 3 {
+			t.Fatalf("Pick() = %v, want between 0 and 2", got)
+		}
+	}
+
+	if _, err := uc.Pick(0); !errors.Is(err, ErrNoCandidates) {
+		t.Errorf("Pick(0) error = %v, want ErrNoCandidates", err)
+	}
+}
+
+func TestDiceUsecase_Shuffle(t *testing.T) {
+	uc := NewDiceUsecase(NewSeededSource(1), &mockRollRepository{}, &mockUnitOfWork{})
+
+	got := uc.Shuffle(10)
+	sorted := append([]int(nil), got...)
+	sort.Ints(sorted)
+	for i, v := range sorted {
+		if v != i {
+			t.Fatalf("Shuffle() = %v, want permutation of 0..9", got)
+		}
+	}
+}
+
+func TestDiceUsecase_SplitTeams(t *testing.T) {
+	uc := NewDiceUsecase(NewSeededSource(1), &mockRollRepository{}, &mockUnitOfWork{})
+
+	tests := []struct {
+		name      string
+		n         int
+		teams     int
+		wantSizes []int
+		wantErr   error
+	}{
+		{name: "割り切れる場合は同じ人数", n: 6, teams: 2, wantSizes: []int{3, 3}},
+		{name: "割り切れない場合は差が1以内", n: 7, teams: 3, wantSizes: []int{3, 2, 2}},
+		{name: "チーム数がメンバー数と同じ", n: 2, teams: 2, wantSizes: []int{1, 1}},
+		{name: "チーム数が1の場合はエラー", n: 4, teams: 1, wantErr: ErrInvalidTeamCount},
+		{name: "チーム数がメンバー数を超える場合はエラー", n: 2, teams: 3, wantErr: ErrInvalidTeamCount},
+		{name: "メンバーがいない場合はエラー", n: 0, teams: 2, wantErr: ErrNoCandidates},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := uc.SplitTeams(tt.n, tt.teams)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SplitTeams() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			seen := make(map[int]bool)
+			for i, team := range got {
+				if len(team) != tt.wantSizes[i] {
+					t.Errorf("team %d size = %v, want %v", i, len(team), tt.wantSizes[i])
+				}
+				for _, member := range team {
+					seen[member] = true
+				}
+			}
+			if len(seen) != tt.n {
+				t.Errorf("SplitTeams() = %v, want all %d members assigned once", got, tt.n)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package dice
+
+import (
+	"math"
+	"time"
+)
+
+type RollID int64
+type GuildID string
+type ChannelID string
+type UserID string
+
+// Roller はダイスを振ったユーザーと場所
+type Roller struct {
+	GuildID   GuildID
+	ChannelID ChannelID
+	UserID    UserID
+}
+
+// RecordedDie は履歴として保存する1個のダイスの出目
+type RecordedDie struct {
+	Sides    int
+	Value    int
+	Dropped  bool
+	Exploded bool
+}
+
+// RollRecord はダイスを振った履歴
+type RollRecord struct {
+	ID         RollID
+	Roller     Roller
+	Expression string
+	Total      int
+	// Secret は振った本人とGMにのみ結果を公開したシークレットダイス
+	Secret bool
+	// Dice は式の項の順、項の中では振った順に並ぶ
+	Dice      []RecordedDie
+	CreatedAt time.Time
+}
+
+func newRollRecord(roller Roller, result *RollResult, secret bool, now time.Time) *RollRecord {
+	record := &RollRecord{
+		Roller:     roller,
+		Expression: result.Expression.String(),
+		Total:      result.Total,
+		Secret:     secret,
+		CreatedAt:  now,
+	}
+	for _, term := range result.Terms {
+		if term.Term.Dice == nil {
+			continue
+		}
+		for _, d := range term.Dice {
+			record.Dice = append(record.Dice, RecordedDie{
+				Sides:    term.Term.Dice.Sides,
+				Value:    d.Value,
+				Dropped:  d.Dropped,
+				Exploded: d.Exploded,
+			})
+		}
+	}
+	return record
+}
+
+// FaceCount は面数ごとの出目の回数
+type FaceCount struct {
+	Sides int
+	Value int
+	Count int
+}
+
+// FaceDistribution は面数ごとの出目の分布
+// 採用/除外によらず振った全てのダイスを数える
+type FaceDistribution struct {
+	Sides int
+	// Counts は出目ごとの回数。Counts[i]は出目i+1の回数
+	Counts []int
+}
+
+// Total は振ったダイスの個数を返す
+func (d *FaceDistribution) Total() int {
+	total := 0
+	for _, c := range d.Counts {
+		total += c
+	}
+	return total
+}
+
+// Expected は出目ごとの期待回数を返す
+func (d *FaceDistribution) Expected() float64 {
+	return float64(d.Total()) / float64(d.Sides)
+}
+
+// Mean は出目の平均を返す
+func (d *FaceDistribution) Mean() float64 {
+	total := d.Total()
+	if total == 0 {
+		return 0
+	}
+	sum := 0
+	for i, c := range d.Counts {
+		sum += (i + 1) * c
+	}
+	return float64(sum) / float64(total)
+}
+
+// ExpectedMean は出目の平均の期待値を返す
+func (d *FaceDistribution) ExpectedMean() float64 {
+	return float64(d.Sides+1) / 2
+}
+
+// ChiSquare は出目が一様に分布しているとした場合のカイ二乗値を返す
+func (d *FaceDistribution) ChiSquare() float64 {
+	expected := d.Expected()
+	if expected == 0 {
+		return 0
+	}
+	chi := 0.0
+	for _, c := range d.Counts {
+		diff := float64(c) - expected
+		chi += diff * diff / expected
+	}
+	return chi
+}
+
+// minExpectedForTest はカイ二乗検定を行うために必要な出目ごとの期待回数
+const minExpectedForTest = 5
+
+// IsBiased は有意水準5%で出目に偏りがあるかを判定する
+// 期待回数が少なく検定できない場合はokにfalseを返す
+func (d *FaceDistribution) IsBiased() (biased bool, ok bool) {
+	if d.Sides < 2 || d.Expected() < minExpectedForTest {
+		return false, false
+	}
+	return d.ChiSquare() > chiSquareCritical(d.Sides-1), true
+}
+
+// chiSquareCritical は自由度dfのカイ二乗分布の上側5%点を返す
+// Wilson-Hilfertyの近似を使用する
+func chiSquareCritical(df int) float64 {
+	const z = 1.6449 // 標準正規分布の上側5%点
+	k := float64(df)
+	v := 2 / (9 * k)
+	return k * math.Pow(1-v+z*math.Sqrt(v), 3)
+}
+
+// newFaceDistributions は面数ごとの出目の回数を分布にまとめる
+// 戻り値はcountsに現れた面数の順に並ぶ
+func newFaceDistributions(counts []FaceCount) []*FaceDistribution {
+	var distributions []*FaceDistribution
+	bySides := make(map[int]*FaceDistribution)
+	for _, c := range counts {
+		if c.Value < 1 || c.Value > c.Sides {
+			continue
+		}
+		d, ok := bySides[c.Sides]
+		if !ok {
+			d = &FaceDistribution{Sides: c.Sides, Counts: make([]int, c.Sides)}
+			bySides[c.Sides] = d
+			distributions = append(distributions, d)
+		}
+		d.Counts[c.Value-1] += c.Count
+	}
+	return distributions
+}
@@ -0,0 +1,90 @@
+package dice
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNewRollRecord(t *testing.T) {
+	expr, err := Parse("2d20kh1+5")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	result := expr.Evaluate(sequenceRoll(4, 17))
+
+	got := newRollRecord(testRoller, result, false, time.Now())
+
+	if got.Expression != "2d20kh1+5" || got.Total != 22 {
+		t.Errorf("newRollRecord() = %+v, want 2d20kh1+5 total 22", got)
+	}
+	want := []RecordedDie{
+		{Sides: 20, Value: 4, Dropped: true},
+		{Sides: 20, Value: 17},
+	}
+	if len(got.Dice) != len(want) || got.Dice[0] != want[0] || got.Dice[1] != want[1] {
+		t.Errorf("newRollRecord().Dice = %+v, want %+v", got.Dice, want)
+	}
+}
+
+func TestFaceDistribution_IsBiased(t *testing.T) {
+	tests := []struct {
+		name       string
+		counts     []int
+		wantBiased bool
+		wantOK     bool
+	}{
+		{
+			name:   "期待回数が少ない場合は判定しない",
+			counts: []int{1, 2, 1, 0, 1, 1},
+			wantOK: false,
+		},
+		{
+			name:       "一様な場合は偏りなし",
+			counts:     []int{10, 11, 9, 10, 10, 10},
+			wantBiased: false,
+			wantOK:     true,
+		},
+		{
+			name:       "特定の出目に偏る場合は偏りあり",
+			counts:     []int{30, 6, 6, 6, 6, 6},
+			wantBiased: true,
+			wantOK:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &FaceDistribution{Sides: len(tt.counts), Counts: tt.counts}
+			biased, ok := d.IsBiased()
+			if ok != tt.wantOK || biased != tt.wantBiased {
+				t.Errorf("IsBiased() = (%v, %v), want (%v, %v)", biased, ok, tt.wantBiased, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestChiSquareCritical(t *testing.T) {
+	// カイ二乗分布表の上側5%点
+	tests := map[int]float64{
+		1:  3.841,
+		5:  11.070,
+		19: 30.144,
+	}
+
+	for df, want := range tests {
+		if got := chiSquareCritical(df); math.Abs(got-want)/want > 0.03 {
+			t.Errorf("chiSquareCritical(%d) = %v, want about %v", df, got, want)
+		}
+	}
+}
+
+func TestFaceDistribution_Mean(t *testing.T) {
+	d := &FaceDistribution{Sides: 6, Counts: []int{1, 0, 0, 0, 0, 1}}
+	if got := d.Mean(); got != 3.5 {
+		t.Errorf("Mean() = %v, want 3.5", got)
+	}
+	if got := d.ExpectedMean(); got != 3.5 {
+		t.Errorf("ExpectedMean() = %v, want 3.5", got)
+	}
+}
@@ -0,0 +1,398 @@
+package dice
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ダイス式の上限値
+const (
+	maxTerms      = 10
+	maxTotalDice  = 100
+	maxSides      = 1000
+	maxConstant   = 10000
+	maxExplosions = 100
+)
+
+var ErrInvalidNotation = errors.New("ダイスの式が正しくありません")
+
+// KeepMode はダイスの採用/除外の方法
+type KeepMode int
+
+const (
+	KeepAll KeepMode = iota
+	// KeepHighest は出目の大きい順にKeepCount個を採用する
+	KeepHighest
+	// KeepLowest は出目の小さい順にKeepCount個を採用する
+	KeepLowest
+	// DropHighest は出目の大きい順にKeepCount個を除外する
+	DropHighest
+	// DropLowest は出目の小さい順にKeepCount個を除外する
+	DropLowest
+)
+
+var keepModeNotations = map[KeepMode]string{
+	KeepHighest: "kh",
+	KeepLowest:  "kl",
+	DropHighest: "dh",
+	DropLowest:  "dl",
+}
+
+// DiceTerm は NdM 形式のダイスの項
+type DiceTerm struct {
+	Count int
+	Sides int
+	Keep  KeepMode
+	// KeepCount はKeepに応じて採用または除外するダイスの個数
+	KeepCount int
+	// Explode は最大値が出た場合にダイスを追加で振る
+	Explode bool
+}
+
+func (d *DiceTerm) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%dd%d", d.Count, d.Sides)
+	if d.Explode {
+		b.WriteString("!")
+	}
+	if d.Keep != KeepAll {
+		fmt.Fprintf(&b, "%s%d", keepModeNotations[d.Keep], d.KeepCount)
+	}
+	return b.String()
+}
+
+// Term は式の項。Diceがnilの場合は定数の修正値を表す
+type Term struct {
+	// Sign は1または-1
+	Sign     int
+	Dice     *DiceTerm
+	Constant int
+}
+
+func (t *Term) String() string {
+	if t.Dice != nil {
+		return t.Dice.String()
+	}
+	return strconv.Itoa(t.Constant)
+}
+
+// Expression はダイス式
+type Expression struct {
+	Terms []Term
+}
+
+func (e *Expression) String() string {
+	var b strings.Builder
+	for i, term := range e.Terms {
+		switch {
+		case term.Sign < 0:
+			b.WriteString("-")
+		case i > 0:
+			b.WriteString("+")
+		}
+		b.WriteString(term.String())
+	}
+	return b.String()
+}
+
+// Parse はダイス式を解析する
+// 例: 1d100, 2d20kh1+5, 4d6dl1, 3d10!, d%-2
+// 大文字と小文字、空白は区別しない
+func Parse(notation string) (*Expression, error) {
+	p := &parser{
+		input: strings.ToLower(strings.Join(strings.Fields(notation), "")),
+	}
+	if p.input == "" {
+		return nil, invalidNotation("式が空です")
+	}
+
+	expr := &Expression{}
+	totalDice := 0
+	for !p.done() {
+		sign := 1
+		switch p.peek() {
+		case '+':
+			p.pos++
+		case '-':
+			sign = -1
+			p.pos++
+		default:
+			if len(expr.Terms) > 0 {
+				return nil, invalidNotation(fmt.Sprintf("%d文字目の「%c」が不正です", p.pos+1, p.peek()))
+			}
+		}
+
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		term.Sign = sign
+
+		if term.Dice != nil {
+			totalDice += term.Dice.Count
+		}
+		expr.Terms = append(expr.Terms, *term)
+
+		if len(expr.Terms) > maxTerms {
+			return nil, invalidNotation(fmt.Sprintf("項は%d個以下で指定してください", maxTerms))
+		}
+		if totalDice > maxTotalDice {
+			return nil, invalidNotation(fmt.Sprintf("ダイスは合計%d個以下で指定してください", maxTotalDice))
+		}
+	}
+
+	return expr, nil
+}
+
+func invalidNotation(detail string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidNotation, detail)
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+// number は連続する数字を読み込む。数字がない場合はfalseを返す
+func (p *parser) number() (int, bool, error) {
+	start := p.pos
+	for !p.done() && p.peek() >= '0' && p.peek() <= '9' {
+		p.pos++
+	}
+	if start == p.pos {
+		return 0, false, nil
+	}
+	n, err := strconv.Atoi(p.input[start:p.pos])
+	if err != nil {
+		return 0, false, invalidNotation(fmt.Sprintf("数値「%s」が大きすぎます", p.input[start:p.pos]))
+	}
+	return n, true, nil
+}
+
+func (p *parser) parseTerm() (*Term, error) {
+	n, hasNumber, err := p.number()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek() != 'd' {
+		if !hasNumber {
+			return nil, invalidNotation(fmt.Sprintf("%d文字目に数値またはダイスを指定してください", p.pos+1))
+		}
+		if n > maxConstant {
+			return nil, invalidNotation(fmt.Sprintf("修正値は%d以下で指定してください", maxConstant))
+		}
+		return &Term{Constant: n}, nil
+	}
+	p.pos++
+
+	count := 1
+	if hasNumber {
+		count = n
+	}
+	if count < 1 {
+		return nil, invalidNotation("ダイスの個数は1以上で指定してください")
+	}
+
+	dice, err := p.parseDice(count)
+	if err != nil {
+		return nil, err
+	}
+	return &Term{Dice: dice}, nil
+}
+
+func (p *parser) parseDice(count int) (*DiceTerm, error) {
+	dice := &DiceTerm{Count: count}
+
+	if p.peek() == '%' {
+		p.pos++
+		dice.Sides = 100
+	} else {
+		sides, ok, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, invalidNotation("ダイスの面数を指定してください")
+		}
+		dice.Sides = sides
+	}
+	if dice.Sides < 1 || dice.Sides > maxSides {
+		return nil, invalidNotation(fmt.Sprintf("ダイスの面数は1以上%d以下で指定してください", maxSides))
+	}
+
+	for !p.done() && p.peek() != '+' && p.peek() != '-' {
+		switch p.peek() {
+		case '!':
+			p.pos++
+			if dice.Explode {
+				return nil, invalidNotation("「!」は1つのダイスに1回のみ指定できます")
+			}
+			if dice.Sides < 2 {
+				return nil, invalidNotation("1面ダイスには「!」を指定できません")
+			}
+			dice.Explode = true
+		case 'k', 'd':
+			if dice.Keep != KeepAll {
+				return nil, invalidNotation("採用/除外は1つのダイスに1回のみ指定できます")
+			}
+			if err := p.parseKeep(dice); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, invalidNotation(fmt.Sprintf("%d文字目の「%c」が不正です", p.pos+1, p.peek()))
+		}
+	}
+
+	return dice, nil
+}
+
+// parseKeep は kh/kl/dh/dl と省略形の k(=kh) と d(=dl) を読み込む
+func (p *parser) parseKeep(dice *DiceTerm) error {
+	keep := p.peek() == 'k'
+	p.pos++
+
+	highest := keep
+	switch p.peek() {
+	case 'h':
+		highest = true
+		p.pos++
+	case 'l':
+		highest = false
+		p.pos++
+	}
+
+	switch {
+	case keep && highest:
+		dice.Keep = KeepHighest
+	case keep:
+		dice.Keep = KeepLowest
+	case highest:
+		dice.Keep = DropHighest
+	default:
+		dice.Keep = DropLowest
+	}
+
+	n, ok, err := p.number()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return invalidNotation("採用/除外するダイスの個数を指定してください")
+	}
+
+	if keep && (n < 1 || n > dice.Count) {
+		return invalidNotation(fmt.Sprintf("採用するダイスの個数は1以上%d以下で指定してください", dice.Count))
+	}
+	if !keep && (n < 0 || n >= dice.Count) {
+		return invalidNotation(fmt.Sprintf("除外するダイスの個数は%d未満で指定してください", dice.Count))
+	}
+	dice.KeepCount = n
+	return nil
+}
+
+// Roll はダイスを1個振った出目(1以上sides以下)を返す
+type Roll func(sides int) int
+
+// DieResult は1個のダイスの出目
+type DieResult struct {
+	Value int
+	// Dropped は採用/除外の指定により合計から除外されたダイス
+	Dropped bool
+	// Exploded は最大値が出たことで追加されたダイス
+	Exploded bool
+}
+
+// TermResult は項ごとの結果
+type TermResult struct {
+	Term  Term
+	Dice  []DieResult
+	Total int
+}
+
+// RollResult はダイス式の結果
+type RollResult struct {
+	Expression *Expression
+	Terms      []TermResult
+	Total      int
+}
+
+// Evaluate はrollでダイスを振り、式を評価する
+func (e *Expression) Evaluate(roll Roll) *RollResult {
+	result := &RollResult{Expression: e}
+	for _, term := range e.Terms {
+		tr := TermResult{Term: term}
+		if term.Dice == nil {
+			tr.Total = term.Sign * term.Constant
+		} else {
+			tr.Dice = term.Dice.roll(roll)
+			for _, d := range tr.Dice {
+				if !d.Dropped {
+					tr.Total += d.Value
+				}
+			}
+			tr.Total *= term.Sign
+		}
+		result.Terms = append(result.Terms, tr)
+		result.Total += tr.Total
+	}
+	return result
+}
+
+func (d *DiceTerm) roll(roll Roll) []DieResult {
+	results := make([]DieResult, 0, d.Count)
+	explosions := 0
+	for range d.Count {
+		v := roll(d.Sides)
+		results = append(results, DieResult{Value: v})
+		// 最大値が出続ける限り追加で振る。無限に振らないよう回数を制限する
+		for d.Explode && v == d.Sides && explosions < maxExplosions {
+			v = roll(d.Sides)
+			results = append(results, DieResult{Value: v, Exploded: true})
+			explosions++
+		}
+	}
+
+	if d.Keep == KeepAll {
+		return results
+	}
+
+	// 出目の小さい順に並べたインデックス。同じ出目は先に振った方を小さいとみなす
+	order := make([]int, len(results))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return results[order[a]].Value < results[order[b]].Value
+	})
+
+	var dropped []int
+	switch d.Keep {
+	case KeepHighest:
+		dropped = order[:len(order)-d.KeepCount]
+	case KeepLowest:
+		dropped = order[d.KeepCount:]
+	case DropHighest:
+		dropped = order[len(order)-d.KeepCount:]
+	case DropLowest:
+		dropped = order[:d.KeepCount]
+	}
+	for _, i := range dropped {
+		results[i].Dropped = true
+	}
+	return results
+}
@@ -0,0 +1,147 @@
+package dice
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		notation string
+		want     string
+		wantErr  bool
+	}{
+		{name: "個数と面数", notation: "1d100", want: "1d100"},
+		{name: "個数の省略", notation: "d20", want: "1d20"},
+		{name: "百面ダイスの省略形", notation: "d%", want: "1d100"},
+		{name: "採用と修正値", notation: "2d20kh1+5", want: "2d20kh1+5"},
+		{name: "kはkhの省略形", notation: "2d20k1", want: "2d20kh1"},
+		{name: "dはdlの省略形", notation: "4d6d1", want: "4d6dl1"},
+		{name: "除外", notation: "4d6dl1", want: "4d6dl1"},
+		{name: "爆発", notation: "3d10!", want: "3d10!"},
+		{name: "大文字と空白を許容", notation: " 2D6 + 1D4 - 2 ", want: "2d6+1d4-2"},
+		{name: "先頭の符号", notation: "-1+1d6", want: "-1+1d6"},
+		{name: "空の式はエラー", notation: "", wantErr: true},
+		{name: "面数がない場合はエラー", notation: "2d", wantErr: true},
+		{name: "不正な文字はエラー", notation: "2d6x", wantErr: true},
+		{name: "演算子の連続はエラー", notation: "1d6++1", wantErr: true},
+		{name: "0個のダイスはエラー", notation: "0d6", wantErr: true},
+		{name: "面数の上限を超える場合はエラー", notation: "1d1001", wantErr: true},
+		{name: "ダイスの合計個数の上限を超える場合はエラー", notation: "60d6+41d6", wantErr: true},
+		{name: "採用する個数がダイスの個数を超える場合はエラー", notation: "2d20kh3", wantErr: true},
+		{name: "全てを除外する場合はエラー", notation: "2d6dl2", wantErr: true},
+		{name: "採用/除外の重複はエラー", notation: "4d6kh3dl1", wantErr: true},
+		{name: "1面ダイスの爆発はエラー", notation: "1d1!", wantErr: true},
+		{name: "桁あふれはエラー", notation: "99999999999999999999d6", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.notation)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidNotation) {
+					t.Errorf("Parse() error = %v, want ErrInvalidNotation", err)
+				}
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("Parse().String() = %v, want %v", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+// sequenceRoll は指定した出目を順番に返す
+func sequenceRoll(values ...int) Roll {
+	i := 0
+	return func(sides int) int {
+		v := values[i]
+		i++
+		return v
+	}
+}
+
+func TestExpression_Evaluate(t *testing.T) {
+	tests := []struct {
+		name      string
+		notation  string
+		rolls     []int
+		wantDice  []DieResult
+		wantTotal int
+	}{
+		{
+			name:     "高い方を採用して修正値を加算",
+			notation: "2d20kh1+5",
+			rolls:    []int{4, 17},
+			wantDice: []DieResult{
+				{Value: 4, Dropped: true},
+				{Value: 17},
+			},
+			wantTotal: 22,
+		},
+		{
+			name:     "低い方を除外",
+			notation: "4d6dl1",
+			rolls:    []int{3, 1, 6, 1},
+			wantDice: []DieResult{
+				{Value: 3},
+				{Value: 1, Dropped: true},
+				{Value: 6},
+				{Value: 1},
+			},
+			wantTotal: 10,
+		},
+		{
+			name:     "最大値が出た場合は追加で振る",
+			notation: "2d10!",
+			rolls:    []int{10, 10, 3, 5},
+			wantDice: []DieResult{
+				{Value: 10},
+				{Value: 10, Exploded: true},
+				{Value: 3, Exploded: true},
+				{Value: 5},
+			},
+			wantTotal: 28,
+		},
+		{
+			name:      "減算",
+			notation:  "1d6-1d4",
+			rolls:     []int{2, 4},
+			wantTotal: -2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Parse(tt.notation)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			got := expr.Evaluate(sequenceRoll(tt.rolls...))
+			if got.Total != tt.wantTotal {
+				t.Errorf("Evaluate().Total = %v, want %v", got.Total, tt.wantTotal)
+			}
+			if tt.wantDice != nil && !reflect.DeepEqual(got.Terms[0].Dice, tt.wantDice) {
+				t.Errorf("Evaluate().Terms[0].Dice = %+v, want %+v", got.Terms[0].Dice, tt.wantDice)
+			}
+		})
+	}
+}
+
+func TestExpression_Evaluate_ExplosionLimit(t *testing.T) {
+	expr, err := Parse("1d6!")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	got := expr.Evaluate(func(sides int) int { return sides })
+	if len(got.Terms[0].Dice) != maxExplosions+1 {
+		t.Errorf("dice length = %v, want %v", len(got.Terms[0].Dice), maxExplosions+1)
+	}
+}
@@ -0,0 +1,13 @@
+package dice
+
+import "context"
+
+type RollRepository interface {
+	Create(ctx context.Context, record *RollRecord) (RollID, error)
+	// ListRecent はギルド内のユーザーの履歴を新しい順に最大limit件返す
+	ListRecent(ctx context.Context, guildID GuildID, userID UserID, limit int) ([]*RollRecord, error)
+	// CountFaces はギルド内のユーザーが振ったダイスの面数と出目ごとの回数を返す
+	// 振ったダイスの個数が多い面数の順、同じ面数の中では出目の順に並ぶ
+	// 統計は公開されるため、シークレットダイスの出目は含めない
+	CountFaces(ctx context.Context, guildID GuildID, userID UserID) ([]FaceCount, error)
+}
@@ -0,0 +1,119 @@
+package dice
+
+import (
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+)
+
+// Source はダイスの出目を生成する乱数源
+type Source interface {
+	// IntN は0以上n未満の整数を返す。nは1以上
+	IntN(n int) int
+}
+
+// sourceRoll はSourceから1以上sides以下の出目を返すRollを作成する
+func sourceRoll(source Source) Roll {
+	return func(sides int) int {
+		return source.IntN(sides) + 1
+	}
+}
+
+type cryptoSource struct{}
+
+// NewCryptoSource は暗号論的に安全な乱数源を返す
+func NewCryptoSource() Source {
+	return cryptoSource{}
+}
+
+func (cryptoSource) IntN(n int) int {
+	// crypto/rand.Reader はOSの乱数源を使用するため、シードを共有せず並行して呼び出せる
+	return rand.New(cryptoReaderSource{}).IntN(n)
+}
+
+// cryptoReaderSource はcrypto/randをmath/rand/v2のSourceとして扱う
+type cryptoReaderSource struct{}
+
+func (cryptoReaderSource) Uint64() uint64 {
+	var b [8]byte
+	if _, err := cryptorand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("failed to read crypto random: %v", err))
+	}
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+// seededSource はシードから決定的に出目を生成する乱数源
+type seededSource struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// NewSeededSource はseedから同じ出目の列を再現できる乱数源を返す
+func NewSeededSource(seed uint64) Source {
+	return &seededSource{
+		r: rand.New(rand.NewPCG(seed, seed)),
+	}
+}
+
+// newChaCha8Source は検証可能モードのシードから乱数源を作成する
+func newChaCha8Source(seed [32]byte) Source {
+	return &seededSource{
+		r: rand.New(rand.NewChaCha8(seed)),
+	}
+}
+
+func (s *seededSource) IntN(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.r.IntN(n)
+}
+
+var ErrInvalidSeed = errors.New("シードの形式が正しくありません")
+
+// Commitment は検証可能モードのシード
+// 振る前にシードのハッシュ(コミット)を公開し、振った後にシードを公開する
+// シードのSHA-256がコミットと一致し、同じシードと式で出目を再現できることで結果を検証できる
+// 出目はシードを鍵とするChaCha8(math/rand/v2)で生成する
+type Commitment struct {
+	seed [32]byte
+}
+
+// NewCommitment は暗号論的に安全な乱数からシードを作成する
+func NewCommitment() (*Commitment, error) {
+	var c Commitment
+	if _, err := cryptorand.Read(c.seed[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate seed: %w", err)
+	}
+	return &c, nil
+}
+
+// ParseCommitment は公開されたシード(16進数64文字)から再現用のCommitmentを作成する
+func ParseCommitment(seed string) (*Commitment, error) {
+	b, err := hex.DecodeString(seed)
+	if err != nil || len(b) != 32 {
+		return nil, ErrInvalidSeed
+	}
+	var c Commitment
+	copy(c.seed[:], b)
+	return &c, nil
+}
+
+// Hash は振る前に公開するシードのSHA-256を16進数で返す
+func (c *Commitment) Hash() string {
+	sum := sha256.Sum256(c.seed[:])
+	return hex.EncodeToString(sum[:])
+}
+
+// Seed は振った後に公開するシードを16進数で返す
+func (c *Commitment) Seed() string {
+	return hex.EncodeToString(c.seed[:])
+}
+
+func (c *Commitment) source() Source {
+	return newChaCha8Source(c.seed)
+}
@@ -0,0 +1,63 @@
+package dice
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestSource_IntN(t *testing.T) {
+	tests := []struct {
+		name   string
+		source Source
+		n      int
+	}{
+		{name: "暗号論的乱数源", source: NewCryptoSource(), n: 6},
+		{name: "シード付き乱数源", source: NewSeededSource(1), n: 6},
+		{name: "n=1の場合は常に0", source: NewCryptoSource(), n: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 100 {
+				if got := tt.source.IntN(tt.n); got < 0 || got >= tt.n {
+					t.Fatalf("IntN(%d) = %v, want between 0 and %d", tt.n, got, tt.n-1)
+				}
+			}
+		})
+	}
+}
+
+func TestNewSeededSource_Reproducible(t *testing.T) {
+	a := NewSeededSource(7)
+	b := NewSeededSource(7)
+	for i := range 20 {
+		if x, y := a.IntN(100), b.IntN(100); x != y {
+			t.Fatalf("IntN()[%d] = %v and %v, want same value", i, x, y)
+		}
+	}
+}
+
+func TestCommitment(t *testing.T) {
+	c, err := NewCommitment()
+	if err != nil {
+		t.Fatalf("NewCommitment() error = %v", err)
+	}
+
+	seed, err := hex.DecodeString(c.Seed())
+	if err != nil {
+		t.Fatalf("Seed() is not hex: %v", err)
+	}
+	sum := sha256.Sum256(seed)
+	if c.Hash() != hex.EncodeToString(sum[:]) {
+		t.Errorf("Hash() = %v, want sha256 of seed", c.Hash())
+	}
+
+	parsed, err := ParseCommitment(c.Seed())
+	if err != nil {
+		t.Fatalf("ParseCommitment() error = %v", err)
+	}
+	if parsed.Hash() != c.Hash() {
+		t.Errorf("ParseCommitment().Hash() = %v, want %v", parsed.Hash(), c.Hash())
+	}
+}
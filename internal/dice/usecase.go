@@ -1,39 +1,170 @@
 package dice
 
 import (
-	"fmt"
-	"math/rand"
+	"at-bot/internal/uow"
+	"context"
 	"time"
 )
 
-var dice = [6]string{"1️⃣", "2️⃣", "3️⃣", "4️⃣", "5️⃣", "6️⃣"}
+// DefaultNotation は式を省略した場合に振るダイス
+const DefaultNotation = "1d6"
 
-type DiceUsecase struct{}
+// 履歴と統計の表示件数
+const (
+	historyLimit    = 10
+	statsSidesLimit = 5
+)
+
+type DiceUsecase struct {
+	source    Source
+	rollRepos RollRepository
+	uow       uow.UnitOfWork
+}
+
+func NewDiceUsecase(
+	source Source,
+	rollRepos RollRepository,
+	uow uow.UnitOfWork,
+) *DiceUsecase {
+	return &DiceUsecase{
+		source:    source,
+		rollRepos: rollRepos,
+		uow:       uow,
+	}
+}
+
+// Roll はダイス式を解析してダイスを振り、履歴に保存する
+func (uc *DiceUsecase) Roll(ctx context.Context, roller Roller, notation string) (*RollResult, error) {
+	return uc.roll(ctx, roller, notation, false)
+}
+
+// RollSecret はシークレットダイスとしてダイスを振り、履歴に保存する
+// 履歴には振ったことのみ表示し、結果は表示しない
+func (uc *DiceUsecase) RollSecret(ctx context.Context, roller Roller, notation string) (*RollResult, error) {
+	return uc.roll(ctx, roller, notation, true)
+}
+
+func (uc *DiceUsecase) roll(ctx context.Context, roller Roller, notation string, secret bool) (*RollResult, error) {
+	expr, err := Parse(notation)
+	if err != nil {
+		return nil, err
+	}
+
+	result := expr.Evaluate(sourceRoll(uc.source))
+	if err := uc.record(ctx, roller, result, secret); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (uc *DiceUsecase) record(ctx context.Context, roller Roller, result *RollResult, secret bool) error {
+	return uc.uow.Do(ctx, func(ctx context.Context) error {
+		_, err := uc.rollRepos.Create(ctx, newRollRecord(roller, result, secret, time.Now()))
+		return err
+	})
+}
+
+// PendingRoll はコミットを公開済みでまだ振っていない検証可能モードのダイス
+type PendingRoll struct {
+	expr       *Expression
+	commitment *Commitment
+}
+
+// Hash は振る前に公開するコミットを返す
+func (p *PendingRoll) Hash() string {
+	return p.commitment.Hash()
+}
 
-func NewDiceUsecase() *DiceUsecase {
-	return &DiceUsecase{}
+// VerifiableResult は検証可能モードの結果
+type VerifiableResult struct {
+	*RollResult
+	Hash string
+	Seed string
 }
 
-func (uc *DiceUsecase) Dice(diceCount int) ([]string, error) {
-	if diceCount < 1 {
-		return nil, fmt.Errorf("エラー")
+// reveal はコミットしたシードでダイスを振り、結果とシードを返す
+func (p *PendingRoll) reveal() *VerifiableResult {
+	return &VerifiableResult{
+		RollResult: p.expr.Evaluate(sourceRoll(p.commitment.source())),
+		Hash:       p.commitment.Hash(),
+		Seed:       p.commitment.Seed(),
 	}
+}
+
+// CommitRoll は検証可能モードでダイス式を解析し、シードを作成する
+// 呼び出し側はHashを公開した後にRevealで結果を得る
+func (uc *DiceUsecase) CommitRoll(notation string) (*PendingRoll, error) {
+	expr, err := Parse(notation)
+	if err != nil {
+		return nil, err
+	}
+
+	commitment, err := NewCommitment()
+	if err != nil {
+		return nil, err
+	}
+
+	return &PendingRoll{
+		expr:       expr,
+		commitment: commitment,
+	}, nil
+}
 
-	results := make([]string, 0)
-	for range diceCount {
-		r, _ := random(1, 6)
-		results = append(results, dice[r-1])
+// Reveal はコミットしたシードでダイスを振り、履歴に保存して結果とシードを返す
+func (uc *DiceUsecase) Reveal(ctx context.Context, roller Roller, pending *PendingRoll) (*VerifiableResult, error) {
+	result := pending.reveal()
+	if err := uc.record(ctx, roller, result.RollResult, false); err != nil {
+		return nil, err
 	}
-	return results, nil
+	return result, nil
 }
 
-func random(min int, max int) (int, error) {
-	if max < min {
-		return 0, fmt.Errorf("max %d must be >= min %d", max, min)
+// Replay は公開されたシードで検証可能モードのダイスを振り直す
+// 元の結果およびコミットと一致すれば、結果が改ざんされていないことを確認できる
+// 振り直した結果は履歴に保存しない
+func (uc *DiceUsecase) Replay(notation string, seed string) (*VerifiableResult, error) {
+	expr, err := Parse(notation)
+	if err != nil {
+		return nil, err
 	}
-	if max == min {
-		return min, nil
+
+	commitment, err := ParseCommitment(seed)
+	if err != nil {
+		return nil, err
 	}
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	return r.Intn(max-min+1) + min, nil
+
+	pending := &PendingRoll{
+		expr:       expr,
+		commitment: commitment,
+	}
+	return pending.reveal(), nil
+}
+
+// History はギルド内のユーザーの直近の履歴を新しい順に返す
+func (uc *DiceUsecase) History(ctx context.Context, guildID GuildID, userID UserID) ([]*RollRecord, error) {
+	var records []*RollRecord
+	err := uc.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		records, err = uc.rollRepos.ListRecent(ctx, guildID, userID, historyLimit)
+		return err
+	})
+	return records, err
+}
+
+// Stats はギルド内のユーザーが振ったダイスの出目の分布を、振った個数の多い面数から返す
+func (uc *DiceUsecase) Stats(ctx context.Context, guildID GuildID, userID UserID) ([]*FaceDistribution, error) {
+	var distributions []*FaceDistribution
+	err := uc.uow.Do(ctx, func(ctx context.Context) error {
+		counts, err := uc.rollRepos.CountFaces(ctx, guildID, userID)
+		if err != nil {
+			return err
+		}
+
+		distributions = newFaceDistributions(counts)
+		if len(distributions) > statsSidesLimit {
+			distributions = distributions[:statsSidesLimit]
+		}
+		return nil
+	})
+	return distributions, err
 }
@@ -1,126 +1,222 @@
 package dice
 
 import (
+	"context"
+	"errors"
+	"reflect"
 	"testing"
 )
 
-func TestRandom(t *testing.T) {
+// Mock repositories
+type mockRollRepository struct {
+	createFunc     func(ctx context.Context, record *RollRecord) (RollID, error)
+	listRecentFunc func(ctx context.Context, guildID GuildID, userID UserID, limit int) ([]*RollRecord, error)
+	countFacesFunc func(ctx context.Context, guildID GuildID, userID UserID) ([]FaceCount, error)
+}
+
+func (m *mockRollRepository) Create(ctx context.Context, record *RollRecord) (RollID, error) {
+	if m.createFunc != nil {
+		return m.createFunc(ctx, record)
+	}
+	return 1, nil
+}
+
+func (m *mockRollRepository) ListRecent(ctx context.Context, guildID GuildID, userID UserID, limit int) ([]*RollRecord, error) {
+	if m.listRecentFunc != nil {
+		return m.listRecentFunc(ctx, guildID, userID, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockRollRepository) CountFaces(ctx context.Context, guildID GuildID, userID UserID) ([]FaceCount, error) {
+	if m.countFacesFunc != nil {
+		return m.countFacesFunc(ctx, guildID, userID)
+	}
+	return nil, nil
+}
+
+type mockUnitOfWork struct{}
+
+func (m *mockUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+var testRoller = Roller{GuildID: "guild-1", ChannelID: "channel-1", UserID: "user-1"}
+
+func TestDiceUsecase_Roll(t *testing.T) {
+	ctx := context.Background()
+
 	tests := []struct {
-		name    string
-		min     int
-		max     int
-		wantErr bool
+		name     string
+		notation string
+		minTotal int
+		maxTotal int
+		wantErr  bool
 	}{
 		{
-			name:    "正常なレンジ",
-			min:     1,
-			max:     6,
-			wantErr: false,
-		},
-		{
-			name:    "minとmaxが同じ",
-			min:     5,
-			max:     5,
-			wantErr: false,
+			name:     "1d6を振る",
+			notation: DefaultNotation,
+			minTotal: 1,
+			maxTotal: 6,
 		},
 		{
-			name:    "maxがminより小さい場合はエラー",
-			min:     10,
-			max:     5,
-			wantErr: true,
+			name:     "修正値を含む式を振る",
+			notation: "2d20kh1+5",
+			minTotal: 6,
+			maxTotal: 25,
 		},
 		{
-			name:    "負の数を含むレンジ",
-			min:     -5,
-			max:     5,
-			wantErr: false,
+			name:     "不正な式の場合はエラー",
+			notation: "2x6",
+			wantErr:  true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := random(tt.min, tt.max)
+			var recorded *RollRecord
+			repo := &mockRollRepository{
+				createFunc: func(ctx context.Context, record *RollRecord) (RollID, error) {
+					recorded = record
+					return 1, nil
+				},
+			}
+			uc := NewDiceUsecase(NewSeededSource(1), repo, &mockUnitOfWork{})
+
+			got, err := uc.Roll(ctx, testRoller, tt.notation)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("random() error = %v, wantErr %v", err, tt.wantErr)
-				return
+				t.Fatalf("Roll() error = %v, wantErr %v", err, tt.wantErr)
 			}
-			if !tt.wantErr {
-				if tt.min == tt.max {
-					// min と max が同じ場合、結果も同じであるべき
-					if got != tt.min {
-						t.Errorf("random() = %v, want %v", got, tt.min)
-					}
-				} else {
-					// レンジ内に収まっているか確認
-					if got < tt.min || got > tt.max {
-						t.Errorf("random() = %v, want between %v and %v", got, tt.min, tt.max)
-					}
+			if tt.wantErr {
+				if recorded != nil {
+					t.Error("Create() should not be called")
 				}
+				return
+			}
+			if got.Total < tt.minTotal || got.Total > tt.maxTotal {
+				t.Errorf("Roll().Total = %v, want between %v and %v", got.Total, tt.minTotal, tt.maxTotal)
+			}
+			if recorded == nil || recorded.Total != got.Total || recorded.Roller != testRoller {
+				t.Errorf("recorded = %+v, want total %v by %+v", recorded, got.Total, testRoller)
 			}
 		})
 	}
 }
 
-func TestDiceUsecase_Dice(t *testing.T) {
-	uc := NewDiceUsecase()
-
-	tests := []struct {
-		name      string
-		diceCount int
-		wantLen   int
-		wantErr   bool
-	}{
-		{
-			name:      "1個のダイスを振る",
-			diceCount: 1,
-			wantLen:   1,
-			wantErr:   false,
-		},
-		{
-			name:      "複数のダイスを振る",
-			diceCount: 5,
-			wantLen:   5,
-			wantErr:   false,
-		},
-		{
-			name:      "0個の場合はエラー",
-			diceCount: 0,
-			wantLen:   0,
-			wantErr:   true,
+func TestDiceUsecase_RollSecret(t *testing.T) {
+	var recorded *RollRecord
+	repo := &mockRollRepository{
+		createFunc: func(ctx context.Context, record *RollRecord) (RollID, error) {
+			recorded = record
+			return 1, nil
 		},
-		{
-			name:      "負の数の場合はエラー",
-			diceCount: -1,
-			wantLen:   0,
-			wantErr:   true,
+	}
+	uc := NewDiceUsecase(NewSeededSource(1), repo, &mockUnitOfWork{})
+
+	if _, err := uc.RollSecret(context.Background(), testRoller, DefaultNotation); err != nil {
+		t.Fatalf("RollSecret() error = %v", err)
+	}
+	if recorded == nil || !recorded.Secret {
+		t.Errorf("recorded = %+v, want secret", recorded)
+	}
+}
+
+func TestDiceUsecase_Roll_RecordError(t *testing.T) {
+	repo := &mockRollRepository{
+		createFunc: func(ctx context.Context, record *RollRecord) (RollID, error) {
+			return 0, errors.New("db error")
 		},
 	}
+	uc := NewDiceUsecase(NewSeededSource(1), repo, &mockUnitOfWork{})
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := uc.Dice(tt.diceCount)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Dice() error = %v, wantErr %v", err, tt.wantErr)
-				return
+	if _, err := uc.Roll(context.Background(), testRoller, DefaultNotation); err == nil {
+		t.Error("Roll() error = nil, want error")
+	}
+}
+
+func TestDiceUsecase_Roll_Reproducible(t *testing.T) {
+	roll := func() int {
+		uc := NewDiceUsecase(NewSeededSource(42), &mockRollRepository{}, &mockUnitOfWork{})
+		got, err := uc.Roll(context.Background(), testRoller, "10d100")
+		if err != nil {
+			t.Fatalf("Roll() error = %v", err)
+		}
+		return got.Total
+	}
+
+	if first, second := roll(), roll(); first != second {
+		t.Errorf("Roll() with same seed = %v and %v, want same total", first, second)
+	}
+}
+
+func TestDiceUsecase_CommitRoll(t *testing.T) {
+	ctx := context.Background()
+	uc := NewDiceUsecase(NewCryptoSource(), &mockRollRepository{}, &mockUnitOfWork{})
+
+	pending, err := uc.CommitRoll("4d6dl1")
+	if err != nil {
+		t.Fatalf("CommitRoll() error = %v", err)
+	}
+	hash := pending.Hash()
+	result, err := uc.Reveal(ctx, testRoller, pending)
+	if err != nil {
+		t.Fatalf("Reveal() error = %v", err)
+	}
+
+	if result.Hash != hash {
+		t.Errorf("Reveal().Hash = %v, want %v", result.Hash, hash)
+	}
+
+	t.Run("公開されたシードで同じ結果を再現できる", func(t *testing.T) {
+		replayed, err := uc.Replay("4d6dl1", result.Seed)
+		if err != nil {
+			t.Fatalf("Replay() error = %v", err)
+		}
+		if replayed.Hash != hash {
+			t.Errorf("Replay().Hash = %v, want %v", replayed.Hash, hash)
+		}
+		if !reflect.DeepEqual(replayed.Terms, result.Terms) {
+			t.Errorf("Replay().Terms = %+v, want %+v", replayed.Terms, result.Terms)
+		}
+	})
+
+	t.Run("不正なシードはエラー", func(t *testing.T) {
+		if _, err := uc.Replay("4d6dl1", "zz"); !errors.Is(err, ErrInvalidSeed) {
+			t.Errorf("Replay() error = %v, want ErrInvalidSeed", err)
+		}
+	})
+
+	t.Run("不正な式の場合はシードを作成しない", func(t *testing.T) {
+		if _, err := uc.CommitRoll("d"); !errors.Is(err, ErrInvalidNotation) {
+			t.Errorf("CommitRoll() error = %v, want ErrInvalidNotation", err)
+		}
+	})
+}
+
+func TestDiceUsecase_Stats(t *testing.T) {
+	repo := &mockRollRepository{
+		countFacesFunc: func(ctx context.Context, guildID GuildID, userID UserID) ([]FaceCount, error) {
+			counts := []FaceCount{
+				{Sides: 6, Value: 1, Count: 3},
+				{Sides: 6, Value: 6, Count: 5},
 			}
-			if !tt.wantErr {
-				if len(got) != tt.wantLen {
-					t.Errorf("Dice() length = %v, want %v", len(got), tt.wantLen)
-				}
-				// 各結果が有効なダイス絵文字であることを確認
-				for i, result := range got {
-					valid := false
-					for _, validDice := range dice {
-						if result == validDice {
-							valid = true
-							break
-						}
-					}
-					if !valid {
-						t.Errorf("Dice()[%d] = %v, want valid dice emoji", i, result)
-					}
-				}
+			// 上限を超える面数は返さない
+			for sides := 2; sides <= statsSidesLimit+1; sides++ {
+				counts = append(counts, FaceCount{Sides: sides * 10, Value: 1, Count: 1})
 			}
-		})
+			return counts, nil
+		},
+	}
+	uc := NewDiceUsecase(NewSeededSource(1), repo, &mockUnitOfWork{})
+
+	got, err := uc.Stats(context.Background(), "guild-1", "user-1")
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if len(got) != statsSidesLimit {
+		t.Fatalf("Stats() length = %v, want %v", len(got), statsSidesLimit)
+	}
+	if want := []int{3, 0, 0, 0, 0, 5}; !reflect.DeepEqual(got[0].Counts, want) {
+		t.Errorf("Stats()[0].Counts = %v, want %v", got[0].Counts, want)
 	}
 }
@@ -0,0 +1,43 @@
+package discord
+
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// InteractionInfo はログやトレースで処理を識別するためのインタラクションの情報
+type InteractionInfo struct {
+	// RequestID はインタラクションIDを使用する
+	RequestID string
+	GuildID   string
+	UserID    string
+}
+
+type interactionInfoKey struct{}
+
+// WithInteractionInfo はinfoを保持するコンテキストを返す
+func WithInteractionInfo(ctx context.Context, info InteractionInfo) context.Context {
+	return context.WithValue(ctx, interactionInfoKey{}, info)
+}
+
+// InteractionInfoFrom はコンテキストに保持されたインタラクションの情報を返す
+func InteractionInfoFrom(ctx context.Context) (InteractionInfo, bool) {
+	info, ok := ctx.Value(interactionInfoKey{}).(InteractionInfo)
+	return info, ok
+}
+
+func newInteractionInfo(interaction *discordgo.Interaction) InteractionInfo {
+	info := InteractionInfo{
+		RequestID: interaction.ID,
+		GuildID:   interaction.GuildID,
+	}
+	// サーバーでのインタラクションユーザーはMember.User、DMではUserに入る
+	switch {
+	case interaction.Member != nil && interaction.Member.User != nil:
+		info.UserID = interaction.Member.User.ID
+	case interaction.User != nil:
+		info.UserID = interaction.User.ID
+	}
+	return info
+}
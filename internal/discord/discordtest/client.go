@@ -0,0 +1,123 @@
+// Package discordtest はDiscord APIを呼び出さずにハンドラーをテストするための実装を提供する
+package discordtest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Call はDiscord APIの呼び出し1回分
+// Argsはリクエストオプションを除いた引数を呼び出し時の順に持つ
+type Call struct {
+	Method string
+	Args   []any
+}
+
+// String はテストの失敗時に内容を比較できるよう、ポインタの引数は指す値を表示する
+func (call Call) String() string {
+	args := make([]string, 0, len(call.Args))
+	for _, arg := range call.Args {
+		if v := reflect.ValueOf(arg); v.Kind() == reflect.Pointer && !v.IsNil() {
+			arg = v.Elem().Interface()
+		}
+		args = append(args, fmt.Sprintf("%+v", arg))
+	}
+	return fmt.Sprintf("%s(%s)", call.Method, strings.Join(args, ", "))
+}
+
+// Client は呼び出されたDiscord APIを順に記録するdiscord.Clientの実装
+type Client struct {
+	// Errors はメソッド名ごとに返すエラー。設定されていないメソッドは成功する
+	Errors map[string]error
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewClient は全ての呼び出しが成功するClientを作成する
+func NewClient() *Client {
+	return &Client{
+		Errors: make(map[string]error),
+	}
+}
+
+// Calls はこれまでの呼び出しを順に返す
+func (client *Client) Calls() []Call {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return append([]Call(nil), client.calls...)
+}
+
+// Reset は記録した呼び出しを消去する
+func (client *Client) Reset() {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.calls = nil
+}
+
+func (client *Client) record(method string, args ...any) error {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.calls = append(client.calls, Call{Method: method, Args: args})
+	return client.Errors[method]
+}
+
+func (client *Client) InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, _ ...discordgo.RequestOption) error {
+	return client.record("InteractionRespond", interaction, resp)
+}
+
+func (client *Client) InteractionResponseEdit(interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	if err := client.record("InteractionResponseEdit", interaction, newresp); err != nil {
+		return nil, err
+	}
+	// 応答メッセージのIDはインタラクションのIDとする
+	return &discordgo.Message{ID: interaction.ID, ChannelID: interaction.ChannelID}, nil
+}
+
+func (client *Client) InteractionResponseDelete(interaction *discordgo.Interaction, _ ...discordgo.RequestOption) error {
+	return client.record("InteractionResponseDelete", interaction)
+}
+
+func (client *Client) FollowupMessageCreate(interaction *discordgo.Interaction, wait bool, data *discordgo.WebhookParams, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	if err := client.record("FollowupMessageCreate", interaction, wait, data); err != nil {
+		return nil, err
+	}
+	return &discordgo.Message{ChannelID: interaction.ChannelID, Content: data.Content}, nil
+}
+
+func (client *Client) ChannelMessageSend(channelID string, content string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	if err := client.record("ChannelMessageSend", channelID, content); err != nil {
+		return nil, err
+	}
+	return &discordgo.Message{ChannelID: channelID, Content: content}, nil
+}
+
+func (client *Client) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	if err := client.record("ChannelMessageSendComplex", channelID, data); err != nil {
+		return nil, err
+	}
+	return &discordgo.Message{ChannelID: channelID, Content: data.Content}, nil
+}
+
+func (client *Client) ChannelMessageEditComplex(m *discordgo.MessageEdit, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	if err := client.record("ChannelMessageEditComplex", m); err != nil {
+		return nil, err
+	}
+	return &discordgo.Message{ID: m.ID, ChannelID: m.Channel}, nil
+}
+
+func (client *Client) ChannelMessageDelete(channelID, messageID string, _ ...discordgo.RequestOption) error {
+	return client.record("ChannelMessageDelete", channelID, messageID)
+}
+
+func (client *Client) UserChannelCreate(recipientID string, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	if err := client.record("UserChannelCreate", recipientID); err != nil {
+		return nil, err
+	}
+	// DMチャンネルのIDは相手のユーザーIDとする
+	return &discordgo.Channel{ID: recipientID, Type: discordgo.ChannelTypeDM}, nil
+}
@@ -1,8 +1,11 @@
 package discord
 
 import (
+	"context"
 	"log"
+	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -71,14 +74,123 @@ type InteractionListener interface {
 	InteractionType() discordgo.InteractionType
 	InteractionID() string
 	MatchInteractionID(InteractionID string) bool
-	Handle(session *discordgo.Session, interaction *discordgo.Interaction) error
+	// Handle はインタラクションを処理する
+	// ctxはインタラクションごとの期限と停止時のキャンセルを持ち、InteractionInfoFromで実行者などの情報を取得できる
+	Handle(ctx context.Context, session Client, interaction *discordgo.Interaction) error
+}
+
+// defaultInteractionTimeout はインタラクションごとの処理の期限の既定値
+const defaultInteractionTimeout = 10 * time.Second
+
+// defaultQueueTimeout はプールで処理を待つ時間の上限の既定値
+// Discordは3秒以内に応答しなかった操作を失敗として表示するため、混雑を伝える応答の時間を残す
+const defaultQueueTimeout = 2 * time.Second
+
+// InteractionGate は処理中のインタラクションを管理する
+// 停止処理の開始後はokがfalseになり、インタラクションを受け付けない
+type InteractionGate interface {
+	Acquire() (release func(), ok bool)
 }
 
 type InteractionDispatcher struct {
 	Listeners []InteractionListener
+	// Gate が設定されている場合は、停止時に処理中のインタラクションの完了を待てるよう処理を登録する
+	Gate InteractionGate
+	// BaseContext はインタラクションごとのコンテキストの元になるコンテキスト
+	// 停止時に処理を中断させる場合に設定する。nilの場合はcontext.Background()を使用する
+	BaseContext context.Context
+	// Timeout はインタラクションを受け付けてからの処理の期限。0の場合はdefaultInteractionTimeoutを使用する
+	Timeout time.Duration
+	// Pool が設定されている場合は、イベントのゴルーチンで処理せずにプールで処理する
+	// 受け付けた順に処理するため、セッションのイベントは同期的に呼び出すこと(WithSyncEvents)
+	Pool *WorkerPool
+	// SerialKey はプールで順に処理するインタラクションをまとめるキーを返す
+	// nilの場合は操作されたメッセージのIDを使用する
+	SerialKey func(interaction *discordgo.Interaction) string
+	// QueueTimeout はプールで処理を待つ時間の上限。受け付けてからこの時間を過ぎたインタラクションは処理しない
+	// 0の場合はdefaultQueueTimeoutを使用する
+	QueueTimeout time.Duration
+	// PanicMessage はハンドラーがパニックした場合に実行者へ表示するメッセージ。空の場合は表示しない
+	PanicMessage string
+	// BusyMessage はプールが混雑していて処理しなかった場合に実行者へ表示するメッセージ。空の場合は表示しない
+	BusyMessage string
+}
+
+// interactionContext はインタラクションごとの期限とインタラクションの情報を持つコンテキストを作成する
+func (dispatcher *InteractionDispatcher) interactionContext(
+	interaction *discordgo.Interaction,
+) (context.Context, context.CancelFunc) {
+	ctx := dispatcher.BaseContext
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timeout := dispatcher.Timeout
+	if timeout <= 0 {
+		timeout = defaultInteractionTimeout
+	}
+
+	ctx = WithInteractionInfo(ctx, newInteractionInfo(interaction))
+	return context.WithTimeout(ctx, timeout)
 }
 
 func (dispatcher *InteractionDispatcher) OnInteractionCreate(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	// セッションがない場合にnilのポインタを持つClientにならないようにする
+	var client Client
+	if session != nil {
+		client = session
+	}
+	dispatcher.dispatch(client, interaction)
+}
+
+// dispatch はインタラクションを処理するリスナーを呼び出す
+func (dispatcher *InteractionDispatcher) dispatch(client Client, interaction *discordgo.InteractionCreate) {
+	release := func() {}
+	if dispatcher.Gate != nil {
+		var ok bool
+		release, ok = dispatcher.Gate.Acquire()
+		if !ok {
+			log.Printf("[DISCORD] rejected interaction during shutdown. id: %s", interaction.ID)
+			return
+		}
+	}
+
+	// 処理の期限はプールで待った時間を含め、受け付けた時点から数える
+	received := time.Now()
+	ctx, cancel := dispatcher.interactionContext(interaction.Interaction)
+
+	listeners := dispatcher.matchListeners(interaction.Interaction)
+	task := func() {
+		defer release()
+		defer cancel()
+
+		// 待っている間に応答の期限が近づいた場合は、処理せずに混雑していることを伝える
+		if waited := time.Since(received); waited > dispatcher.queueTimeout() {
+			log.Printf("[DISCORD] dropped interaction waiting too long. requestId: %s, waited: %v", interaction.ID, waited)
+			dispatcher.respondError(client, interaction.Interaction, dispatcher.BusyMessage)
+			return
+		}
+
+		for _, listener := range listeners {
+			dispatcher.handle(ctx, listener, client, interaction.Interaction)
+		}
+	}
+
+	if dispatcher.Pool == nil {
+		task()
+		return
+	}
+	if err := dispatcher.Pool.Submit(dispatcher.serialKey(interaction.Interaction), task); err != nil {
+		log.Printf("[DISCORD] rejected interaction. requestId: %s, %v", interaction.ID, err)
+		cancel()
+		release()
+		// セッションのイベントを止めないよう、応答は別のゴルーチンで行う
+		go dispatcher.respondError(client, interaction.Interaction, dispatcher.BusyMessage)
+	}
+}
+
+// matchListeners はインタラクションを処理するリスナーを返す
+func (dispatcher *InteractionDispatcher) matchListeners(interaction *discordgo.Interaction) []InteractionListener {
+	var listeners []InteractionListener
 	for _, listener := range dispatcher.Listeners {
 		if listener.InteractionType() != interaction.Type {
 			continue
@@ -102,8 +214,73 @@ func (dispatcher *InteractionDispatcher) OnInteractionCreate(session *discordgo.
 			}
 		}
 
-		if err := listener.Handle(session, interaction.Interaction); err != nil {
-			log.Printf("[DISCORD] failed to handle interaction: %v", err)
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
+
+// handle はリスナーを呼び出す
+// パニックした場合は他のインタラクションの処理を続けられるよう回復し、実行者にエラーを表示する
+func (dispatcher *InteractionDispatcher) handle(
+	ctx context.Context,
+	listener InteractionListener,
+	session Client,
+	interaction *discordgo.Interaction,
+) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[DISCORD] panic while handling interaction. requestId: %s, %v\n%s", interaction.ID, r, debug.Stack())
+			dispatcher.respondError(session, interaction, dispatcher.PanicMessage)
 		}
+	}()
+
+	if err := listener.Handle(ctx, session, interaction); err != nil {
+		log.Printf("[DISCORD] failed to handle interaction. requestId: %s, %v", interaction.ID, err)
+	}
+}
+
+// queueTimeout はプールで処理を待つ時間の上限を返す
+func (dispatcher *InteractionDispatcher) queueTimeout() time.Duration {
+	if dispatcher.QueueTimeout <= 0 {
+		return defaultQueueTimeout
+	}
+	return dispatcher.QueueTimeout
+}
+
+// respondError はハンドラーで処理できなかった場合のメッセージを実行者にのみ表示する
+// ハンドラーが応答済みの場合はフォローアップメッセージで表示する
+func (dispatcher *InteractionDispatcher) respondError(session Client, interaction *discordgo.Interaction, message string) {
+	if session == nil || message == "" {
+		return
+	}
+
+	err := session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err == nil {
+		return
+	}
+
+	_, err = session.FollowupMessageCreate(interaction, true, &discordgo.WebhookParams{
+		Content: message,
+		Flags:   discordgo.MessageFlagsEphemeral,
+	})
+	if err != nil {
+		log.Printf("[DISCORD] failed to respond error. requestId: %s, %v", interaction.ID, err)
+	}
+}
+
+// serialKey はプールで順に処理するためのキーを返す
+func (dispatcher *InteractionDispatcher) serialKey(interaction *discordgo.Interaction) string {
+	if dispatcher.SerialKey != nil {
+		return dispatcher.SerialKey(interaction)
+	}
+	if interaction.Message != nil {
+		return interaction.Message.ID
 	}
+	return ""
 }
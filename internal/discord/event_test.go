@@ -0,0 +1,378 @@
+package discord
+
+import (
+	"at-bot/internal/discord/discordtest"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// recordingListener は受け取ったコンテキストを記録するインタラクション
+type recordingListener struct {
+	ctx context.Context
+}
+
+func (l *recordingListener) InteractionType() discordgo.InteractionType {
+	return discordgo.InteractionApplicationCommand
+}
+
+func (l *recordingListener) InteractionID() string {
+	return "test"
+}
+
+func (l *recordingListener) MatchInteractionID(interactionID string) bool {
+	return l.InteractionID() == interactionID
+}
+
+func (l *recordingListener) Handle(ctx context.Context, _ Client, _ *discordgo.Interaction) error {
+	l.ctx = ctx
+	return nil
+}
+
+func newTestInteraction(member *discordgo.Member, user *discordgo.User) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:      "interaction-1",
+			Type:    discordgo.InteractionApplicationCommand,
+			GuildID: "guild-1",
+			Member:  member,
+			User:    user,
+			Data:    discordgo.ApplicationCommandInteractionData{Name: "test"},
+		},
+	}
+}
+
+func TestInteractionDispatcher_Context(t *testing.T) {
+	tests := []struct {
+		name        string
+		interaction *discordgo.InteractionCreate
+		want        InteractionInfo
+	}{
+		{
+			name:        "サーバーでのインタラクション",
+			interaction: newTestInteraction(&discordgo.Member{User: &discordgo.User{ID: "user-1"}}, nil),
+			want:        InteractionInfo{RequestID: "interaction-1", GuildID: "guild-1", UserID: "user-1"},
+		},
+		{
+			name:        "DMでのインタラクション",
+			interaction: newTestInteraction(nil, &discordgo.User{ID: "user-2"}),
+			want:        InteractionInfo{RequestID: "interaction-1", GuildID: "guild-1", UserID: "user-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener := &recordingListener{}
+			dispatcher := &InteractionDispatcher{
+				Listeners: []InteractionListener{listener},
+				Timeout:   time.Minute,
+			}
+
+			start := time.Now()
+			dispatcher.OnInteractionCreate(nil, tt.interaction)
+
+			if listener.ctx == nil {
+				t.Fatal("listener was not called")
+			}
+			got, ok := InteractionInfoFrom(listener.ctx)
+			if !ok {
+				t.Fatal("InteractionInfoFrom() ok = false, want true")
+			}
+			if got != tt.want {
+				t.Errorf("InteractionInfoFrom() = %+v, want %+v", got, tt.want)
+			}
+
+			deadline, ok := listener.ctx.Deadline()
+			if !ok {
+				t.Fatal("ctx has no deadline")
+			}
+			if deadline.Before(start.Add(time.Minute)) || deadline.After(time.Now().Add(time.Minute)) {
+				t.Errorf("deadline = %v, want about 1 minute from %v", deadline, start)
+			}
+			// 処理の完了後はキャンセルされる
+			if listener.ctx.Err() == nil {
+				t.Error("ctx is not canceled after Handle returned")
+			}
+		})
+	}
+}
+
+func TestInteractionDispatcher_BaseContext(t *testing.T) {
+	base, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	listener := &recordingListener{}
+	dispatcher := &InteractionDispatcher{
+		Listeners:   []InteractionListener{listener},
+		BaseContext: base,
+	}
+	dispatcher.OnInteractionCreate(nil, newTestInteraction(&discordgo.Member{User: &discordgo.User{ID: "user-1"}}, nil))
+
+	if listener.ctx == nil {
+		t.Fatal("listener was not called")
+	}
+	// 停止時のキャンセルが引き継がれる
+	if err := listener.ctx.Err(); err != context.Canceled {
+		t.Errorf("ctx.Err() = %v, want context.Canceled", err)
+	}
+}
+
+// panicListener は処理中にパニックするインタラクション
+type panicListener struct {
+	recordingListener
+}
+
+func (l *panicListener) Handle(context.Context, Client, *discordgo.Interaction) error {
+	panic("handler bug")
+}
+
+func TestInteractionDispatcher_PanicRecovery(t *testing.T) {
+	tests := []struct {
+		name string
+		pool *WorkerPool
+	}{
+		{name: "イベントのゴルーチンで処理", pool: nil},
+		{name: "プールで処理", pool: NewWorkerPool(2, 10)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener := &recordingListener{}
+			dispatcher := &InteractionDispatcher{
+				Listeners: []InteractionListener{&panicListener{}, listener},
+				Pool:      tt.pool,
+			}
+
+			dispatcher.OnInteractionCreate(nil, newTestInteraction(&discordgo.Member{User: &discordgo.User{ID: "user-1"}}, nil))
+			if tt.pool != nil {
+				tt.pool.Wait()
+			}
+
+			// パニックしたリスナーの後のリスナーも呼び出される
+			if listener.ctx == nil {
+				t.Error("listener after panic was not called")
+			}
+		})
+	}
+}
+
+func TestInteractionDispatcher_PanicResponse(t *testing.T) {
+	const message = "エラーが発生しました"
+	interaction := newTestInteraction(&discordgo.Member{User: &discordgo.User{ID: "user-1"}}, nil).Interaction
+
+	tests := []struct {
+		name   string
+		errors map[string]error
+		want   []discordtest.Call
+	}{
+		{
+			name: "エフェメラルメッセージで応答する",
+			want: []discordtest.Call{
+				{Method: "InteractionRespond", Args: []any{interaction, &discordgo.InteractionResponse{
+					Type: discordgo.InteractionResponseChannelMessageWithSource,
+					Data: &discordgo.InteractionResponseData{Content: message, Flags: discordgo.MessageFlagsEphemeral},
+				}}},
+			},
+		},
+		{
+			name:   "応答済みの場合はフォローアップメッセージを送信する",
+			errors: map[string]error{"InteractionRespond": errors.New("already acknowledged")},
+			want: []discordtest.Call{
+				{Method: "InteractionRespond", Args: []any{interaction, &discordgo.InteractionResponse{
+					Type: discordgo.InteractionResponseChannelMessageWithSource,
+					Data: &discordgo.InteractionResponseData{Content: message, Flags: discordgo.MessageFlagsEphemeral},
+				}}},
+				{Method: "FollowupMessageCreate", Args: []any{interaction, true, &discordgo.WebhookParams{
+					Content: message,
+					Flags:   discordgo.MessageFlagsEphemeral,
+				}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := discordtest.NewClient()
+			for method, err := range tt.errors {
+				client.Errors[method] = err
+			}
+			dispatcher := &InteractionDispatcher{PanicMessage: message}
+
+			dispatcher.handle(context.Background(), &panicListener{}, client, interaction)
+
+			if got := client.Calls(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("calls = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// orderListener は処理したインタラクションのIDを順に記録する
+type orderListener struct {
+	mu        sync.Mutex
+	ids       []string
+	deadlines map[string]time.Time
+}
+
+func (l *orderListener) InteractionType() discordgo.InteractionType {
+	return discordgo.InteractionMessageComponent
+}
+
+func (l *orderListener) InteractionID() string {
+	return ""
+}
+
+func (l *orderListener) MatchInteractionID(string) bool {
+	return true
+}
+
+func (l *orderListener) Handle(ctx context.Context, _ Client, interaction *discordgo.Interaction) error {
+	// 後から受け付けた操作が先に終わらないよう、最初の操作を遅らせる
+	if interaction.ID == "interaction-0" {
+		time.Sleep(20 * time.Millisecond)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ids = append(l.ids, interaction.ID)
+	if deadline, ok := ctx.Deadline(); ok {
+		if l.deadlines == nil {
+			l.deadlines = make(map[string]time.Time)
+		}
+		l.deadlines[interaction.ID] = deadline
+	}
+	return nil
+}
+
+// newMessageInteraction は同じ募集メッセージのボタンを押下したインタラクションを作成する
+func newMessageInteraction(id string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:      id,
+			Type:    discordgo.InteractionMessageComponent,
+			Message: &discordgo.Message{ID: "message-1"},
+			Data:    discordgo.MessageComponentInteractionData{CustomID: "recruit/join"},
+		},
+	}
+}
+
+func busyCall(interaction *discordgo.Interaction) discordtest.Call {
+	return discordtest.Call{Method: "InteractionRespond", Args: []any{interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Content: "busy", Flags: discordgo.MessageFlagsEphemeral},
+	}}}
+}
+
+func TestInteractionDispatcher_DeadlineFromReceive(t *testing.T) {
+	listener := &orderListener{}
+	pool := NewWorkerPool(1, 10)
+	defer pool.Close()
+	dispatcher := &InteractionDispatcher{
+		Listeners: []InteractionListener{listener},
+		Pool:      pool,
+		Timeout:   time.Minute,
+	}
+
+	dispatcher.dispatch(nil, newMessageInteraction("interaction-0"))
+	dispatcher.dispatch(nil, newMessageInteraction("interaction-1"))
+	pool.Wait()
+
+	// 後の操作は前の操作の完了を待つが、期限は受け付けた時点から数える
+	diff := listener.deadlines["interaction-1"].Sub(listener.deadlines["interaction-0"])
+	if diff >= 20*time.Millisecond {
+		t.Errorf("deadline difference = %v, want less than the queue wait", diff)
+	}
+}
+
+func TestInteractionDispatcher_QueueTimeout(t *testing.T) {
+	listener := &orderListener{}
+	pool := NewWorkerPool(1, 10)
+	defer pool.Close()
+	client := discordtest.NewClient()
+	dispatcher := &InteractionDispatcher{
+		Listeners:    []InteractionListener{listener},
+		Pool:         pool,
+		QueueTimeout: 5 * time.Millisecond,
+		BusyMessage:  "busy",
+	}
+
+	first := newMessageInteraction("interaction-0")
+	second := newMessageInteraction("interaction-1")
+	dispatcher.dispatch(client, first)
+	dispatcher.dispatch(client, second)
+	pool.Wait()
+
+	// 前の操作を待つ間に期限を過ぎた操作は処理せず、混雑していることを伝える
+	if want := []string{"interaction-0"}; !slices.Equal(listener.ids, want) {
+		t.Errorf("handled = %v, want %v", listener.ids, want)
+	}
+	if got, want := client.Calls(), []discordtest.Call{busyCall(second.Interaction)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestInteractionDispatcher_PoolFull(t *testing.T) {
+	listener := &orderListener{}
+	pool := NewWorkerPool(1, 1)
+	defer pool.Close()
+	client := discordtest.NewClient()
+	dispatcher := &InteractionDispatcher{
+		Listeners:   []InteractionListener{listener},
+		Pool:        pool,
+		BusyMessage: "busy",
+	}
+
+	first := newMessageInteraction("interaction-0")
+	second := newMessageInteraction("interaction-1")
+	dispatcher.dispatch(client, first)
+	dispatcher.dispatch(client, second)
+	pool.Wait()
+
+	if want := []string{"interaction-0"}; !slices.Equal(listener.ids, want) {
+		t.Errorf("handled = %v, want %v", listener.ids, want)
+	}
+	// 受け付けなかった操作への応答はイベントのゴルーチンとは別に行われる
+	want := []discordtest.Call{busyCall(second.Interaction)}
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if len(client.Calls()) > 0 {
+			break
+		}
+	}
+	if got := client.Calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestInteractionDispatcher_PoolSerializesSameMessage(t *testing.T) {
+	listener := &orderListener{}
+	pool := NewWorkerPool(4, 10)
+	dispatcher := &InteractionDispatcher{
+		Listeners: []InteractionListener{listener},
+		Pool:      pool,
+	}
+
+	var want []string
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprintf("interaction-%d", i)
+		want = append(want, id)
+		dispatcher.OnInteractionCreate(nil, &discordgo.InteractionCreate{
+			Interaction: &discordgo.Interaction{
+				ID:      id,
+				Type:    discordgo.InteractionMessageComponent,
+				Message: &discordgo.Message{ID: "message-1"},
+				Data:    discordgo.MessageComponentInteractionData{CustomID: "recruit/join"},
+			},
+		})
+	}
+	pool.Wait()
+
+	if !slices.Equal(listener.ids, want) {
+		t.Errorf("handled order = %v, want %v", listener.ids, want)
+	}
+}
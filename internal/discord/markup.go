@@ -1,11 +1,27 @@
 package discord
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 func FormatMention(userID string) string {
 	return fmt.Sprintf("<@%s>", userID)
 }
 
+func FormatRoleMention(roleID string) string {
+	return fmt.Sprintf("<@&%s>", roleID)
+}
+
+func FormatChannelMention(channelID string) string {
+	return fmt.Sprintf("<#%s>", channelID)
+}
+
+// FormatMessageLink はメッセージへ移動するためのリンクを返す
+func FormatMessageLink(guildID, channelID, messageID string) string {
+	return fmt.Sprintf("https://discord.com/channels/%s/%s/%s", guildID, channelID, messageID)
+}
+
 func FormatStrikethrough(text string) string {
 	return fmt.Sprintf("~~%s~~", text)
 }
@@ -13,3 +29,14 @@ func FormatStrikethrough(text string) string {
 func FormatBold(text string) string {
 	return fmt.Sprintf("**%s**", text)
 }
+
+// タイムスタンプの表示形式
+const (
+	TimestampShortDateTime = "f"
+	TimestampRelative      = "R"
+)
+
+// FormatTimestamp は閲覧者のタイムゾーンで表示されるタイムスタンプ記法に変換する
+func FormatTimestamp(t time.Time, style string) string {
+	return fmt.Sprintf("<t:%d:%s>", t.Unix(), style)
+}
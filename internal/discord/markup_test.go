@@ -1,6 +1,9 @@
 package discord
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestFormatMention(t *testing.T) {
 	got := FormatMention("1234567890")
@@ -10,6 +13,30 @@ func TestFormatMention(t *testing.T) {
 	}
 }
 
+func TestFormatRoleMention(t *testing.T) {
+	got := FormatRoleMention("1234567890")
+	want := "<@&1234567890>"
+	if got != want {
+		t.Errorf("FormatRoleMention(\"1234567890\") == %s, want %s", got, want)
+	}
+}
+
+func TestFormatChannelMention(t *testing.T) {
+	got := FormatChannelMention("1234567890")
+	want := "<#1234567890>"
+	if got != want {
+		t.Errorf("FormatChannelMention(\"1234567890\") == %s, want %s", got, want)
+	}
+}
+
+func TestFormatMessageLink(t *testing.T) {
+	got := FormatMessageLink("1", "2", "3")
+	want := "https://discord.com/channels/1/2/3"
+	if got != want {
+		t.Errorf("FormatMessageLink(\"1\", \"2\", \"3\") == %s, want %s", got, want)
+	}
+}
+
 func TestFormatStrikethrough(t *testing.T) {
 	got := FormatStrikethrough("1234567890")
 	want := "~~1234567890~~"
@@ -25,3 +52,12 @@ func TestFormatBold(t *testing.T) {
 		t.Errorf("FormatBold(\"1234567890\") == %s, want %s", got, want)
 	}
 }
+
+func TestFormatTimestamp(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+	got := FormatTimestamp(ts, TimestampRelative)
+	want := "<t:1700000000:R>"
+	if got != want {
+		t.Errorf("FormatTimestamp(1700000000, R) == %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,135 @@
+package discord
+
+import (
+	"errors"
+	"log"
+	"runtime/debug"
+	"sync"
+)
+
+var (
+	// ErrPoolFull は実行待ちのタスクが上限に達している場合のエラー
+	ErrPoolFull = errors.New("worker pool queue is full")
+	// ErrPoolClosed は停止したプールにタスクを追加した場合のエラー
+	ErrPoolClosed = errors.New("worker pool is closed")
+)
+
+// WorkerPool は決まった数のワーカーでタスクを実行する
+// 同じキーのタスクは受け付けた順に1つずつ実行する
+// 実行中と実行待ちのタスクの数には上限があり、上限を超えるタスクは受け付けない
+type WorkerPool struct {
+	// ready は実行できるようになった処理。要素の数は受け付けたタスクの数を超えないため、上限まで待たずに追加できる
+	ready chan func()
+
+	mu       sync.Mutex
+	queues   map[string][]func()
+	pending  int
+	capacity int
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+// NewWorkerPool はsize個のワーカーで、実行中と実行待ちを合わせてqueueSize個までのタスクを受け付けるプールを作成する
+func NewWorkerPool(size int, queueSize int) *WorkerPool {
+	size = max(size, 1)
+	capacity := max(queueSize, size)
+	pool := &WorkerPool{
+		ready:    make(chan func(), capacity),
+		queues:   make(map[string][]func()),
+		capacity: capacity,
+	}
+	for i := 0; i < size; i++ {
+		go pool.work()
+	}
+	return pool
+}
+
+// Submit はtaskを実行待ちに追加する
+// keyが空の場合は他のタスクと順序を揃えずに実行する
+// 実行待ちが上限に達している場合はErrPoolFullを、停止後はErrPoolClosedを返し、taskは実行しない
+func (pool *WorkerPool) Submit(key string, task func()) error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if pool.closed {
+		return ErrPoolClosed
+	}
+	if pool.pending >= pool.capacity {
+		return ErrPoolFull
+	}
+	pool.pending++
+	pool.wg.Add(1)
+
+	if key == "" {
+		pool.ready <- func() { pool.execute(task) }
+		return nil
+	}
+
+	queue, running := pool.queues[key]
+	pool.queues[key] = append(queue, task)
+	// 同じキーのタスクを実行中の場合は、そのワーカーが続けて実行する
+	if !running {
+		pool.ready <- func() { pool.drain(key) }
+	}
+	return nil
+}
+
+// Wait は受け付けた全てのタスクの完了を待つ
+func (pool *WorkerPool) Wait() {
+	pool.wg.Wait()
+}
+
+// Close は新しいタスクの受け付けを停止する
+// 受け付け済みのタスクは実行してからワーカーを終了する。完了を待つ場合はWaitを使用する
+func (pool *WorkerPool) Close() error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if !pool.closed {
+		pool.closed = true
+		close(pool.ready)
+	}
+	return nil
+}
+
+// work は停止されるまで実行できる処理を順に実行する
+func (pool *WorkerPool) work() {
+	for fn := range pool.ready {
+		fn()
+	}
+}
+
+// drain はkeyのタスクがなくなるまで順に実行する
+func (pool *WorkerPool) drain(key string) {
+	for {
+		pool.mu.Lock()
+		queue := pool.queues[key]
+		if len(queue) == 0 {
+			delete(pool.queues, key)
+			pool.mu.Unlock()
+			return
+		}
+		task := queue[0]
+		pool.queues[key] = queue[1:]
+		pool.mu.Unlock()
+
+		pool.execute(task)
+	}
+}
+
+// execute はtaskを実行する
+// パニックした場合も同じキーの後続のタスクを実行できるよう回復する
+func (pool *WorkerPool) execute(task func()) {
+	defer pool.wg.Done()
+	defer func() {
+		pool.mu.Lock()
+		pool.pending--
+		pool.mu.Unlock()
+	}()
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[DISCORD] panic in worker pool task: %v\n%s", r, debug.Stack())
+		}
+	}()
+	task()
+}
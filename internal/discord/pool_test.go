@@ -0,0 +1,139 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func submit(t *testing.T, pool *WorkerPool, key string, task func()) {
+	t.Helper()
+	if err := pool.Submit(key, task); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+}
+
+func TestWorkerPool_Limit(t *testing.T) {
+	const size = 2
+	pool := NewWorkerPool(size, 10)
+
+	var running, peak atomic.Int32
+	var done atomic.Int32
+	for i := 0; i < 10; i++ {
+		submit(t, pool, fmt.Sprintf("key-%d", i), func() {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+			done.Add(1)
+		})
+	}
+	pool.Wait()
+
+	if got := done.Load(); got != 10 {
+		t.Errorf("done = %d, want 10", got)
+	}
+	if got := peak.Load(); got > size {
+		t.Errorf("peak concurrency = %d, want <= %d", got, size)
+	}
+}
+
+func TestWorkerPool_SerialKey(t *testing.T) {
+	pool := NewWorkerPool(4, 20)
+
+	var mu sync.Mutex
+	var order []int
+	var running atomic.Int32
+	for i := 0; i < 20; i++ {
+		submit(t, pool, "message-1", func() {
+			if running.Add(1) > 1 {
+				t.Error("tasks with the same key ran concurrently")
+			}
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			running.Add(-1)
+		})
+	}
+	pool.Wait()
+
+	want := make([]int, 20)
+	for i := range want {
+		want[i] = i
+	}
+	if !slices.Equal(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+}
+
+func TestWorkerPool_PanicDoesNotBlockKey(t *testing.T) {
+	pool := NewWorkerPool(1, 2)
+
+	var called atomic.Bool
+	submit(t, pool, "message-1", func() { panic("handler bug") })
+	submit(t, pool, "message-1", func() { called.Store(true) })
+	pool.Wait()
+
+	// パニックしたタスクの後も同じキーのタスクが実行される
+	if !called.Load() {
+		t.Error("task after panic was not executed")
+	}
+}
+
+func TestWorkerPool_QueueFull(t *testing.T) {
+	pool := NewWorkerPool(1, 2)
+	defer pool.Close()
+
+	block := make(chan struct{})
+	var done atomic.Int32
+	for i := 0; i < 2; i++ {
+		submit(t, pool, "message-1", func() {
+			<-block
+			done.Add(1)
+		})
+	}
+
+	// 実行中と実行待ちが上限に達している場合は受け付けない
+	if err := pool.Submit("message-2", func() { t.Error("rejected task was executed") }); !errors.Is(err, ErrPoolFull) {
+		t.Errorf("Submit() error = %v, want ErrPoolFull", err)
+	}
+
+	close(block)
+	pool.Wait()
+	if got := done.Load(); got != 2 {
+		t.Errorf("done = %d, want 2", got)
+	}
+
+	// 完了すると再び受け付ける
+	submit(t, pool, "message-2", func() {})
+	pool.Wait()
+}
+
+func TestWorkerPool_Close(t *testing.T) {
+	pool := NewWorkerPool(1, 2)
+
+	var called atomic.Bool
+	submit(t, pool, "message-1", func() { called.Store(true) })
+	if err := pool.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	pool.Wait()
+
+	// 停止前に受け付けたタスクは実行する
+	if !called.Load() {
+		t.Error("task submitted before Close was not executed")
+	}
+	if err := pool.Submit("message-1", func() {}); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Submit() after Close error = %v, want ErrPoolClosed", err)
+	}
+}
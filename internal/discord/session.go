@@ -13,13 +13,15 @@ type SessionConfig interface {
 	Intent() discordgo.Intent
 	Handlers() []any
 	Slashes() []*discordgo.ApplicationCommand
+	SyncEvents() bool
 }
 
 type sessionConfig struct {
-	token    string
-	intent   discordgo.Intent
-	handlers []any
-	slashes  []*discordgo.ApplicationCommand
+	token      string
+	intent     discordgo.Intent
+	handlers   []any
+	slashes    []*discordgo.ApplicationCommand
+	syncEvents bool
 }
 
 func (config *sessionConfig) Token() string {
@@ -38,6 +40,10 @@ func (config *sessionConfig) Slashes() []*discordgo.ApplicationCommand {
 	return append(make([]*discordgo.ApplicationCommand, 0), config.slashes...)
 }
 
+func (config *sessionConfig) SyncEvents() bool {
+	return config.syncEvents
+}
+
 func (config *sessionConfig) validate() error {
 	if config.token == "" {
 		return errors.New("token is required")
@@ -81,6 +87,15 @@ func WithIntent(intent discordgo.Intent) sessionConfigOption {
 	}
 }
 
+// WithSyncEvents はイベントごとにゴルーチンを作成せず、受信した順にハンドラーを呼び出す
+// ハンドラーで時間のかかる処理を行う場合はWorkerPoolで処理すること
+func WithSyncEvents() sessionConfigOption {
+	return func(config *sessionConfig) error {
+		config.syncEvents = true
+		return nil
+	}
+}
+
 func WithMessageCreateHandler(
 	handler func(*discordgo.Session, *discordgo.MessageCreate),
 ) sessionConfigOption {
@@ -139,6 +154,7 @@ func (manager *SessionManager) Open(config SessionConfig) error {
 	if config.Intent() != 0 {
 		session.Identify.Intents = config.Intent()
 	}
+	session.SyncEvents = config.SyncEvents()
 
 	for _, handler := range config.Handlers() {
 		session.AddHandler(handler)
@@ -162,6 +178,11 @@ func (manager *SessionManager) Open(config SessionConfig) error {
 	return nil
 }
 
+// Session は接続中のセッションを返す。未接続の場合はnilを返す
+func (manager *SessionManager) Session() *discordgo.Session {
+	return manager.session
+}
+
 func (manager *SessionManager) Close() error {
 	if manager.session == nil {
 		return nil
@@ -2,7 +2,12 @@ package handler
 
 import (
 	"at-bot/internal/dice"
+	"at-bot/internal/discord"
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"strconv"
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
@@ -10,8 +15,20 @@ import (
 
 // ダイスコマンド用の固定値
 const (
-	diceCommandName = "dice"
-	diceArgName     = "個数"
+	diceCommandName       = "dice"
+	diceRollSubcommand    = "roll"
+	diceHistorySubcommand = "history"
+	diceStatsSubcommand   = "stats"
+	diceArgName           = "式"
+	diceVerifyArg         = "検証可能"
+	diceSeedArg           = "シード"
+	diceUserArg           = "ユーザー"
+	diceSecretArg         = "シークレット"
+	diceGMArg             = "ゲームマスター"
+	// diceMaxContentLength はコミットとシードを付けてもDiscordのメッセージ文字数上限に収まる内訳の最大文字数
+	diceMaxContentLength = 1800
+	// diceSeedLength は検証可能モードのシード(32バイト)の16進数での文字数
+	diceSeedLength = 64
 )
 
 type diceSlashCommand struct {
@@ -26,19 +43,70 @@ func NewDiceSlashCommand(service *dice.DiceUsecase) *diceSlashCommand {
 }
 
 func (command *diceSlashCommand) CreateCommand() *discordgo.ApplicationCommand {
-	minValue := 1.0
-	maxValue := 100.0
+	userOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionUser,
+		Name:        diceUserArg,
+		Description: "表示するユーザーを指定します。(省略時: 自分)",
+		Required:    false,
+	}
+
+	seedLength := diceSeedLength
+
 	return &discordgo.ApplicationCommand{
 		Name:        diceCommandName,
-		Description: "6面ダイスを振った結果を返します。(オプションでダイスの個数指定可)",
+		Description: "ダイスを振ります。",
 		Options: []*discordgo.ApplicationCommandOption{
 			{
-				Type:        discordgo.ApplicationCommandOptionInteger,
-				Name:        diceArgName,
-				Description: "振るダイスの個数を入力します。(省略時: 1個)",
-				Required:    false,
-				MinValue:    &minValue,
-				MaxValue:    maxValue,
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        diceRollSubcommand,
+				Description: "ダイスを振った結果を返します。(例: 2d20kh1+5, 4d6dl1, 3d10!, 1d100)",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        diceArgName,
+						Description: "振るダイスの式を入力します。(kh/kl: 採用, dh/dl: 除外, !: 爆発) (省略時: 1d6)",
+						Required:    false,
+						MaxLength:   100,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        diceVerifyArg,
+						Description: "振る前にシードのハッシュを公開し、振った後にシードを公開します。",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        diceSeedArg,
+						Description: "検証可能モードで公開されたシードを入力すると、同じ式の結果を再現します。",
+						Required:    false,
+						MinLength:   &seedLength,
+						MaxLength:   diceSeedLength,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        diceSecretArg,
+						Description: "結果を自分にのみ表示し、チャンネルには振ったことのみ表示します。",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionUser,
+						Name:        diceGMArg,
+						Description: "シークレットダイスの結果をDMで伝えるユーザーを指定します。(指定するとシークレットになります)",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        diceHistorySubcommand,
+				Description: "このサーバーで直近に振ったダイスの履歴を表示します。",
+				Options:     []*discordgo.ApplicationCommandOption{userOption},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        diceStatsSubcommand,
+				Description: "このサーバーで振ったダイスの出目の分布を期待値と比較して表示します。",
+				Options:     []*discordgo.ApplicationCommandOption{userOption},
 			},
 		},
 	}
@@ -56,26 +124,73 @@ func (command *diceSlashCommand) MatchInteractionID(interactionID string) bool {
 	return command.InteractionID() == interactionID
 }
 
-func (command *diceSlashCommand) Handle(session *discordgo.Session, interaction *discordgo.Interaction) error {
-	optionMap := command.getOptionMap(interaction)
+func (command *diceSlashCommand) Handle(ctx context.Context, session discord.Client, interaction *discordgo.Interaction) error {
+	subcommand, optionMap := command.getSubcommand(interaction)
+	switch subcommand {
+	case diceRollSubcommand:
+		return command.roll(ctx, session, interaction, optionMap)
+	case diceHistorySubcommand:
+		return command.history(ctx, session, interaction, optionMap)
+	case diceStatsSubcommand:
+		return command.stats(ctx, session, interaction, optionMap)
+	default:
+		return fmt.Errorf("unknown subcommand: %q", subcommand)
+	}
+}
+
+func (command *diceSlashCommand) roll(
+	ctx context.Context,
+	session discord.Client,
+	interaction *discordgo.Interaction,
+	optionMap map[string]*discordgo.ApplicationCommandInteractionDataOption,
+) error {
 	opt, ok := optionMap[diceArgName]
 
-	diceCount := 1
+	notation := dice.DefaultNotation
 	if ok && opt != nil {
-		diceCount = int(opt.IntValue())
+		notation = opt.StringValue()
+	}
+
+	if opt, ok := optionMap[diceSeedArg]; ok && opt != nil {
+		return command.replay(session, interaction, notation, opt.StringValue())
 	}
 
-	log.Printf("[DICE] user %s rolled %d dice", interaction.Member.User.ID, diceCount)
+	verifiable := false
+	if opt, ok := optionMap[diceVerifyArg]; ok && opt != nil {
+		verifiable = opt.BoolValue()
+	}
+	secret := false
+	if opt, ok := optionMap[diceSecretArg]; ok && opt != nil {
+		secret = opt.BoolValue()
+	}
+	// GMを指定した場合はシークレットとして振る
+	var gmID string
+	if opt, ok := optionMap[diceGMArg]; ok && opt != nil {
+		gmID = opt.UserValue(nil).ID
+		secret = true
+	}
+
+	if secret && verifiable {
+		return command.respondEphemeral(session, interaction, "❗シークレットと検証可能は同時に指定できません。")
+	}
+	if secret {
+		return command.rollSecret(ctx, session, interaction, notation, gmID)
+	}
+	if verifiable {
+		return command.rollVerifiable(ctx, session, interaction, notation)
+	}
+
+	log.Printf("[DICE] user %s rolled %q", interaction.Member.User.ID, notation)
 
-	results, err := command.service.Dice(diceCount)
+	result, err := command.service.Roll(ctx, toRoller(interaction), notation)
 	if err != nil {
-		return err
+		return command.respondDiceError(session, interaction, err)
 	}
 
 	err = session.InteractionRespond(interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Content: strings.Join(results, " "),
+			Content: createDiceResultMessage(result),
 		},
 	})
 
@@ -85,3 +200,215 @@ func (command *diceSlashCommand) Handle(session *discordgo.Session, interaction
 
 	return nil
 }
+
+func toRoller(interaction *discordgo.Interaction) dice.Roller {
+	return dice.Roller{
+		GuildID:   dice.GuildID(interaction.GuildID),
+		ChannelID: dice.ChannelID(interaction.ChannelID),
+		UserID:    dice.UserID(interaction.Member.User.ID),
+	}
+}
+
+// rollSecret はチャンネルには振ったことのみ表示し、結果は実行者にのみ表示する
+// gmIDを指定した場合はGMにも結果をDMで送信する
+func (command *diceSlashCommand) rollSecret(
+	ctx context.Context,
+	session discord.Client,
+	interaction *discordgo.Interaction,
+	notation string,
+	gmID string,
+) error {
+	log.Printf("[DICE] user %s rolled %q secretly (gm: %q)", interaction.Member.User.ID, notation, gmID)
+
+	result, err := command.service.RollSecret(ctx, toRoller(interaction), notation)
+	if err != nil {
+		return command.respondDiceError(session, interaction, err)
+	}
+
+	err = session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content:         createSecretRollAnnouncement(interaction.Member.User.ID, gmID),
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	message := createDiceResultMessage(result)
+	content := "🔒 シークレットダイスの結果\n" + message
+	if gmID != "" {
+		if err := sendDirectMessage(session, gmID, createGMRollMessage(interaction.Member.User.ID, interaction.ChannelID, message)); err != nil {
+			log.Printf("[DICE] failed to send secret roll to gm %s: %v", gmID, err)
+			content += "\n❗GMにDMを送信できませんでした。"
+		}
+	}
+
+	_, err = session.FollowupMessageCreate(interaction, true, &discordgo.WebhookParams{
+		Content: content,
+		Flags:   discordgo.MessageFlagsEphemeral,
+	})
+	return err
+}
+
+// sendDirectMessage はユーザーにDMを送信する
+func sendDirectMessage(session discord.Client, userID string, content string) error {
+	channel, err := session.UserChannelCreate(userID)
+	if err != nil {
+		return fmt.Errorf("failed to create dm channel: %w", err)
+	}
+	if _, err := session.ChannelMessageSend(channel.ID, content); err != nil {
+		return fmt.Errorf("failed to send dm: %w", err)
+	}
+	return nil
+}
+
+// createSecretRollAnnouncement はチャンネルに表示するシークレットダイスの告知を返す
+func createSecretRollAnnouncement(userID string, gmID string) string {
+	content := fmt.Sprintf("🎲 %s がシークレットダイスを振りました。", discord.FormatMention(userID))
+	if gmID != "" {
+		content += fmt.Sprintf(" (GM: %s)", discord.FormatMention(gmID))
+	}
+	return content
+}
+
+// createGMRollMessage はGMにDMで送信するシークレットダイスの結果を返す
+func createGMRollMessage(userID string, channelID string, message string) string {
+	return fmt.Sprintf(
+		"🔒 %s のシークレットダイス (%s)\n%s",
+		discord.FormatMention(userID),
+		discord.FormatChannelMention(channelID),
+		message,
+	)
+}
+
+// rollVerifiable はコミットを公開するメッセージを送信してから振り、結果とシードを続けて送信する
+func (command *diceSlashCommand) rollVerifiable(
+	ctx context.Context,
+	session discord.Client,
+	interaction *discordgo.Interaction,
+	notation string,
+) error {
+	pending, err := command.service.CommitRoll(notation)
+	if err != nil {
+		return command.respondDiceError(session, interaction, err)
+	}
+
+	log.Printf("[DICE] user %s committed %q with %s", interaction.Member.User.ID, notation, pending.Hash())
+
+	err = session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("🔒 コミット: `%s`\nこのハッシュに対応するシードで振ります。", pending.Hash()),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// コミットの公開後に振る
+	result, err := command.service.Reveal(ctx, toRoller(interaction), pending)
+	if err != nil {
+		_, _ = session.FollowupMessageCreate(interaction, true, &discordgo.WebhookParams{
+			Content: ErrorMessageContent,
+		})
+		return err
+	}
+	_, err = session.FollowupMessageCreate(interaction, true, &discordgo.WebhookParams{
+		Content: createVerifiableResultMessage(result),
+	})
+	return err
+}
+
+// replay は公開されたシードで結果を再現する
+func (command *diceSlashCommand) replay(
+	session discord.Client,
+	interaction *discordgo.Interaction,
+	notation string,
+	seed string,
+) error {
+	log.Printf("[DICE] user %s replayed %q", interaction.Member.User.ID, notation)
+
+	result, err := command.service.Replay(notation, seed)
+	if err != nil {
+		return command.respondDiceError(session, interaction, err)
+	}
+
+	return command.respondEphemeral(session, interaction, "🔁 再現結果\n"+createVerifiableResultMessage(result))
+}
+
+// respondDiceError は入力に起因するエラーを実行者に通知する
+func (command *diceSlashCommand) respondDiceError(
+	session discord.Client,
+	interaction *discordgo.Interaction,
+	err error,
+) error {
+	if errors.Is(err, dice.ErrInvalidNotation) || errors.Is(err, dice.ErrInvalidSeed) {
+		return command.respondEphemeral(session, interaction, "❗"+err.Error()+"。")
+	}
+	_ = command.respondEphemeral(session, interaction, ErrorMessageContent)
+	return err
+}
+
+// createVerifiableResultMessage は結果に検証用のコミットとシードを付けて返す
+func createVerifiableResultMessage(result *dice.VerifiableResult) string {
+	return fmt.Sprintf(
+		"%s\n🔒 コミット: `%s`\n🔑 シード: `%s`",
+		createDiceResultMessage(result.RollResult),
+		result.Hash,
+		result.Seed,
+	)
+}
+
+// createDiceResultMessage は合計と項ごとの内訳を返す
+// 除外したダイスは取り消し線、爆発で追加したダイスは💥を付けて表示する
+func createDiceResultMessage(result *dice.RollResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🎲 `%s` → **%d**", result.Expression.String(), result.Total)
+
+	for i, term := range result.Terms {
+		line := "\n" + formatDiceTerm(i, term)
+		if b.Len()+len(line) > diceMaxContentLength {
+			b.WriteString("\n…")
+			break
+		}
+		b.WriteString(line)
+	}
+
+	return b.String()
+}
+
+func formatDiceTerm(index int, term dice.TermResult) string {
+	sign := ""
+	switch {
+	case term.Term.Sign < 0:
+		sign = "-"
+	case index > 0:
+		sign = "+"
+	}
+
+	if term.Term.Dice == nil {
+		return sign + strconv.Itoa(term.Term.Constant)
+	}
+
+	values := make([]string, 0, len(term.Dice))
+	for _, d := range term.Dice {
+		v := strconv.Itoa(d.Value)
+		if d.Exploded {
+			v = "💥" + v
+		}
+		if d.Dropped {
+			v = "~~" + v + "~~"
+		}
+		values = append(values, v)
+	}
+
+	return fmt.Sprintf(
+		"%s%s: [%s] = %d",
+		sign,
+		term.Term.Dice.String(),
+		strings.Join(values, ", "),
+		term.Total,
+	)
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"at-bot/internal/dice"
+	"at-bot/internal/discord"
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ダイス履歴と統計の表示用の固定値
+const (
+	diceColor = 0xe67e22
+	// diceHistoryMaxDice は履歴の1行に表示する出目の最大個数
+	diceHistoryMaxDice = 20
+	// diceStatsMaxFaces は出目ごとの回数を表示する最大の面数。超える場合は平均と検定結果のみ表示する
+	diceStatsMaxFaces = 20
+)
+
+// targetUser はオプションで指定されたユーザー、省略時は実行者を返す
+func (command *diceSlashCommand) targetUser(
+	interaction *discordgo.Interaction,
+	optionMap map[string]*discordgo.ApplicationCommandInteractionDataOption,
+) dice.UserID {
+	if opt, ok := optionMap[diceUserArg]; ok && opt != nil {
+		return dice.UserID(opt.UserValue(nil).ID)
+	}
+	return dice.UserID(interaction.Member.User.ID)
+}
+
+func (command *diceSlashCommand) history(
+	ctx context.Context,
+	session discord.Client,
+	interaction *discordgo.Interaction,
+	optionMap map[string]*discordgo.ApplicationCommandInteractionDataOption,
+) error {
+	userID := command.targetUser(interaction, optionMap)
+	log.Printf("[DICE] user %s checked history of user %s", interaction.Member.User.ID, userID)
+
+	records, err := command.service.History(ctx, dice.GuildID(interaction.GuildID), userID)
+	if err != nil {
+		_ = command.respondEphemeral(session, interaction, ErrorMessageContent)
+		return err
+	}
+
+	return command.respondDiceEmbed(session, interaction, createDiceHistoryEmbed(userID, records))
+}
+
+func (command *diceSlashCommand) stats(
+	ctx context.Context,
+	session discord.Client,
+	interaction *discordgo.Interaction,
+	optionMap map[string]*discordgo.ApplicationCommandInteractionDataOption,
+) error {
+	userID := command.targetUser(interaction, optionMap)
+	log.Printf("[DICE] user %s checked stats of user %s", interaction.Member.User.ID, userID)
+
+	distributions, err := command.service.Stats(ctx, dice.GuildID(interaction.GuildID), userID)
+	if err != nil {
+		_ = command.respondEphemeral(session, interaction, ErrorMessageContent)
+		return err
+	}
+
+	return command.respondDiceEmbed(session, interaction, createDiceStatsEmbed(userID, distributions))
+}
+
+func (command *diceSlashCommand) respondDiceEmbed(
+	session discord.Client,
+	interaction *discordgo.Interaction,
+	embed *discordgo.MessageEmbed,
+) error {
+	return session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			// 履歴と統計の表示でメンションを飛ばさない
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+		},
+	})
+}
+
+func createDiceHistoryEmbed(userID dice.UserID, records []*dice.RollRecord) *discordgo.MessageEmbed {
+	lines := []string{discord.FormatMention(string(userID))}
+	if len(records) == 0 {
+		lines = append(lines, "ダイスを振った履歴はありません。")
+	}
+	for _, record := range records {
+		// シークレットダイスの結果は履歴に表示しない
+		if record.Secret {
+			lines = append(lines, fmt.Sprintf(
+				"%s 🔒 シークレットダイス",
+				discord.FormatTimestamp(record.CreatedAt, discord.TimestampRelative),
+			))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf(
+			"%s `%s` → **%d** %s",
+			discord.FormatTimestamp(record.CreatedAt, discord.TimestampRelative),
+			record.Expression,
+			record.Total,
+			formatRecordedDice(record.Dice),
+		))
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "🎲 ダイス履歴",
+		Description: strings.Join(lines, "\n"),
+		Color:       diceColor,
+	}
+}
+
+// formatRecordedDice は出目を振った順に表示する。多い場合は省略する
+func formatRecordedDice(recorded []dice.RecordedDie) string {
+	if len(recorded) == 0 {
+		return ""
+	}
+
+	values := make([]string, 0, min(len(recorded), diceHistoryMaxDice)+1)
+	for i, d := range recorded {
+		if i >= diceHistoryMaxDice {
+			values = append(values, "…")
+			break
+		}
+		v := strconv.Itoa(d.Value)
+		if d.Exploded {
+			v = "💥" + v
+		}
+		if d.Dropped {
+			v = "~~" + v + "~~"
+		}
+		values = append(values, v)
+	}
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+func createDiceStatsEmbed(userID dice.UserID, distributions []*dice.FaceDistribution) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       "🎲 出目の分布",
+		Description: discord.FormatMention(string(userID)),
+		Color:       diceColor,
+	}
+	if len(distributions) == 0 {
+		embed.Description += "\nダイスを振った履歴はありません。"
+		return embed
+	}
+
+	for _, d := range distributions {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("d%d (%d個)", d.Sides, d.Total()),
+			Value: formatFaceDistribution(d),
+		})
+	}
+	return embed
+}
+
+// formatFaceDistribution は出目ごとの回数と期待回数からの差、平均と検定結果を返す
+func formatFaceDistribution(d *dice.FaceDistribution) string {
+	var lines []string
+	if d.Sides <= diceStatsMaxFaces {
+		expected := d.Expected()
+		for i, count := range d.Counts {
+			lines = append(lines, fmt.Sprintf(
+				"`%*d`: %d回 (%+.0f%%)",
+				len(strconv.Itoa(d.Sides)),
+				i+1,
+				count,
+				(float64(count)-expected)/expected*100,
+			))
+		}
+	}
+
+	lines = append(lines, fmt.Sprintf("平均 %.2f (期待値 %.2f)", d.Mean(), d.ExpectedMean()))
+
+	biased, ok := d.IsBiased()
+	switch {
+	case !ok:
+		lines = append(lines, "判定するにはもっと振る必要があります")
+	case biased:
+		lines = append(lines, fmt.Sprintf("⚠️ 偏りの可能性あり (χ²=%.1f, 有意水準5%%)", d.ChiSquare()))
+	default:
+		lines = append(lines, fmt.Sprintf("✅ 偏りなし (χ²=%.1f, 有意水準5%%)", d.ChiSquare()))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"at-bot/internal/dice"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatRecordedDice(t *testing.T) {
+	tests := []struct {
+		name string
+		dice []dice.RecordedDie
+		want string
+	}{
+		{
+			name: "ダイスがない場合は空",
+			want: "",
+		},
+		{
+			name: "除外と爆発を表示",
+			dice: []dice.RecordedDie{
+				{Value: 6},
+				{Value: 2, Exploded: true},
+				{Value: 1, Dropped: true},
+			},
+			want: "[6, 💥2, ~~1~~]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatRecordedDice(tt.dice); got != tt.want {
+				t.Errorf("formatRecordedDice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatRecordedDice_Truncate(t *testing.T) {
+	recorded := make([]dice.RecordedDie, diceHistoryMaxDice+5)
+	got := formatRecordedDice(recorded)
+	if !strings.HasSuffix(got, ", …]") {
+		t.Errorf("formatRecordedDice() = %v, want truncated", got)
+	}
+}
+
+func TestCreateDiceHistoryEmbed(t *testing.T) {
+	records := []*dice.RollRecord{
+		{
+			Expression: "1d20+5",
+			Total:      22,
+			Dice:       []dice.RecordedDie{{Sides: 20, Value: 17}},
+			CreatedAt:  time.Unix(1700000000, 0),
+		},
+		{
+			Expression: "1d100",
+			Total:      3,
+			Secret:     true,
+			Dice:       []dice.RecordedDie{{Sides: 100, Value: 3}},
+			CreatedAt:  time.Unix(1700000000, 0),
+		},
+	}
+
+	got := createDiceHistoryEmbed("user-1", records).Description
+	want := "<@user-1>\n<t:1700000000:R> `1d20+5` → **22** [17]\n<t:1700000000:R> 🔒 シークレットダイス"
+	if got != want {
+		t.Errorf("Description = %q, want %q", got, want)
+	}
+}
+
+func TestFormatFaceDistribution(t *testing.T) {
+	tests := []struct {
+		name        string
+		dist        *dice.FaceDistribution
+		wantContain []string
+		wantMissing []string
+	}{
+		{
+			name:        "出目ごとの回数と期待回数からの差を表示",
+			dist:        &dice.FaceDistribution{Sides: 2, Counts: []int{3, 1}},
+			wantContain: []string{"`1`: 3回 (+50%)", "`2`: 1回 (-50%)", "もっと振る必要があります"},
+		},
+		{
+			name:        "面数が多い場合は出目ごとの回数を省略",
+			dist:        &dice.FaceDistribution{Sides: 100, Counts: make([]int, 100)},
+			wantContain: []string{"平均"},
+			wantMissing: []string{"`  1`"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatFaceDistribution(tt.dist)
+			for _, want := range tt.wantContain {
+				if !strings.Contains(got, want) {
+					t.Errorf("formatFaceDistribution() = %v, should contain %v", got, want)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(got, missing) {
+					t.Errorf("formatFaceDistribution() = %v, should not contain %v", got, missing)
+				}
+			}
+		})
+	}
+}
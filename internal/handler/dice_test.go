@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"at-bot/internal/dice"
+	"strings"
 	"testing"
 
 	"github.com/bwmarrin/discordgo"
@@ -18,29 +20,130 @@ func TestDiceSlashCommand_CreateCommand(t *testing.T) {
 		t.Errorf("CreateCommand().Description is empty")
 	}
 
-	if len(command.Options) != 1 {
-		t.Errorf("CreateCommand().Options length = %v, want 1", len(command.Options))
-		return
+	wantSubcommands := []string{diceRollSubcommand, diceHistorySubcommand, diceStatsSubcommand}
+	if len(command.Options) != len(wantSubcommands) {
+		t.Fatalf("CreateCommand().Options length = %v, want %v", len(command.Options), len(wantSubcommands))
+	}
+	for i, want := range wantSubcommands {
+		if command.Options[i].Name != want {
+			t.Errorf("CreateCommand().Options[%d].Name = %v, want %v", i, command.Options[i].Name, want)
+		}
+	}
+
+	roll := command.Options[0]
+	if len(roll.Options) != 5 {
+		t.Fatalf("roll options length = %v, want 5", len(roll.Options))
 	}
 
-	opt := command.Options[0]
+	opt := roll.Options[0]
 	if opt.Name != diceArgName {
-		t.Errorf("CreateCommand().Options[0].Name = %v, want %v", opt.Name, diceArgName)
+		t.Errorf("roll.Options[0].Name = %v, want %v", opt.Name, diceArgName)
 	}
 
-	if opt.Type != discordgo.ApplicationCommandOptionInteger {
-		t.Errorf("CreateCommand().Options[0].Type = %v, want ApplicationCommandOptionInteger", opt.Type)
+	if opt.Type != discordgo.ApplicationCommandOptionString {
+		t.Errorf("roll.Options[0].Type = %v, want ApplicationCommandOptionString", opt.Type)
 	}
 
 	if opt.Required {
-		t.Errorf("CreateCommand().Options[0].Required = true, want false")
+		t.Errorf("roll.Options[0].Required = true, want false")
+	}
+}
+
+// evaluateDice はテスト用に出目を固定してダイス式を評価する
+func evaluateDice(t *testing.T, notation string, rolls ...int) *dice.RollResult {
+	t.Helper()
+
+	expr, err := dice.Parse(notation)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	i := 0
+	return expr.Evaluate(func(sides int) int {
+		v := rolls[i]
+		i++
+		return v
+	})
+}
+
+func TestCreateDiceResultMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		notation string
+		rolls    []int
+		want     string
+	}{
+		{
+			name:     "除外したダイスと修正値を表示",
+			notation: "2d20kh1+5",
+			rolls:    []int{4, 17},
+			want:     "🎲 `2d20kh1+5` → **22**\n2d20kh1: [~~4~~, 17] = 17\n+5",
+		},
+		{
+			name:     "爆発で追加したダイスを表示",
+			notation: "1d6!-1",
+			rolls:    []int{6, 2},
+			want:     "🎲 `1d6!-1` → **7**\n1d6!: [6, 💥2] = 8\n-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := createDiceResultMessage(evaluateDice(t, tt.notation, tt.rolls...))
+			if got != tt.want {
+				t.Errorf("createDiceResultMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateDiceResultMessage_Truncate(t *testing.T) {
+	rolls := make([]int, 0, 100)
+	for range 100 {
+		rolls = append(rolls, 1000)
+	}
+	notation := strings.Repeat("10d1000+", 9) + "10d1000"
+
+	got := createDiceResultMessage(evaluateDice(t, notation, rolls...))
+	if len(got) > diceMaxContentLength+len("\n…") {
+		t.Errorf("createDiceResultMessage() length = %v, want <= %v", len(got), diceMaxContentLength)
+	}
+}
+
+func TestCreateVerifiableResultMessage(t *testing.T) {
+	result := &dice.VerifiableResult{
+		RollResult: evaluateDice(t, "1d6", 3),
+		Hash:       "hash",
+		Seed:       "seed",
 	}
 
-	if opt.MinValue == nil || *opt.MinValue != 1.0 {
-		t.Errorf("CreateCommand().Options[0].MinValue = %v, want 1.0", opt.MinValue)
+	want := "🎲 `1d6` → **3**\n1d6: [3] = 3\n🔒 コミット: `hash`\n🔑 シード: `seed`"
+	if got := createVerifiableResultMessage(result); got != want {
+		t.Errorf("createVerifiableResultMessage() = %q, want %q", got, want)
 	}
+}
+
+func TestCreateSecretRollAnnouncement(t *testing.T) {
+	tests := []struct {
+		name string
+		gmID string
+		want string
+	}{
+		{name: "GMを指定しない場合", want: "🎲 <@user-1> がシークレットダイスを振りました。"},
+		{name: "GMを指定した場合", gmID: "gm-1", want: "🎲 <@user-1> がシークレットダイスを振りました。 (GM: <@gm-1>)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := createSecretRollAnnouncement("user-1", tt.gmID); got != tt.want {
+				t.Errorf("createSecretRollAnnouncement() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
 
-	if opt.MaxValue != 100.0 {
-		t.Errorf("CreateCommand().Options[0].MaxValue = %v, want 100.0", opt.MaxValue)
+func TestCreateGMRollMessage(t *testing.T) {
+	want := "🔒 <@user-1> のシークレットダイス (<#channel-1>)\n🎲 `1d6` → **3**"
+	if got := createGMRollMessage("user-1", "channel-1", "🎲 `1d6` → **3**"); got != want {
+		t.Errorf("createGMRollMessage() = %q, want %q", got, want)
 	}
 }
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"at-bot/internal/dice"
 	"at-bot/internal/discord"
 	"at-bot/internal/recruit"
 	"context"
@@ -19,16 +20,51 @@ type interactionCustomID string
 
 // ボタンインタラクション識別子
 const (
-	interactionJoin    interactionCustomID = "recruit/join"
-	interactionDecline interactionCustomID = "recruit/decline"
-	interactionClose   interactionCustomID = "recruit/close"
-	interactionCancel  interactionCustomID = "recruit/cancel"
+	interactionJoin      interactionCustomID = "recruit/join"
+	interactionDecline   interactionCustomID = "recruit/decline"
+	interactionTentative interactionCustomID = "recruit/tentative"
+	interactionClose     interactionCustomID = "recruit/close"
+	interactionReopen    interactionCustomID = "recruit/reopen"
+	interactionCancel    interactionCustomID = "recruit/cancel"
+	interactionManage    interactionCustomID = "recruit/manage"
+	interactionCapacity  interactionCustomID = "recruit/capacity"
+	interactionKick      interactionCustomID = "recruit/kick"
+	interactionDelete    interactionCustomID = "recruit/delete"
+	interactionPick      interactionCustomID = "recruit/pick"
+	interactionTeams     interactionCustomID = "recruit/teams"
+)
+
+// モーダルインタラクション識別子
+const (
+	interactionCapacitySubmit interactionCustomID = "recruit/capacity/submit"
+	interactionOpenSubmit     interactionCustomID = "recruit/open"
+	interactionTeamsSubmit    interactionCustomID = "recruit/teams/submit"
 )
 
 // UI用文字列
 const (
-	joinLabel    = "🙋 参加"
-	declineLabel = "🙅 不参加"
+	joinLabel      = "🙋 参加"
+	declineLabel   = "🙅 不参加"
+	tentativeLabel = "🤔 未定"
+	waitlistLabel  = "⏳ キャンセル待ち"
+	startAtLabel   = "🕒 開始時刻"
+	roleLabel      = "🎯 対象ロール"
+	startedLabel   = "▶️ 開始済み"
+	expiredLabel   = "⌛ 期限切れ"
+	closedLabel    = "🔒 締め切り"
+	closeLabel     = "🔒 締め切る"
+	reopenLabel    = "🔓 再開する"
+	manageLabel    = "⚙️ 管理"
+	capacityLabel  = "👥 人数変更"
+	deleteLabel    = "🗑️ 削除"
+	pickLabel      = "🎲 抽選"
+	teamsLabel     = "🔀 チーム分け"
+)
+
+// 埋め込みの色
+const (
+	openedColor = 0xffa500
+	closedColor = 0x808080
 )
 
 // customID共通キー
@@ -37,13 +73,43 @@ const (
 	customIDKey  = "customID"
 )
 
+// 募集作成モーダルのcustomIDキー
+const (
+	capacityKey = "capacity"
+	startAtKey  = "startAt"
+	roleKey     = "role"
+	roleOnlyKey = "only"
+)
+
+// 募集作成モーダルの入力欄
+const (
+	titleInputID       = "title"
+	descriptionInputID = "description"
+)
+
 // 募集開始コマンド用の固定値
 const (
 	recruitOpenCommandName = "at"
 	recruitArgName         = "人数"
+	recruitStartAtArgName  = "開始時刻"
+	recruitTitleArgName    = "タイトル"
+	recruitContentArgName  = "内容"
+	recruitRoleArgName     = "ロール"
+	recruitRoleOnlyArgName = "ロール限定"
+	recruitDetailArgName   = "詳細入力"
 )
 
-const errorMessageContent = "❗処理中に問題が発生しました。"
+// 募集のタイトルと内容の最大文字数
+const (
+	maxTitleLength       = 100
+	maxDescriptionLength = 1000
+)
+
+// ErrorMessageContent は予期しないエラーが発生した場合に実行者へ表示するメッセージ
+const ErrorMessageContent = "❗処理中に問題が発生しました。"
+
+// BusyMessageContent は混雑により操作を処理できなかった場合に実行者へ表示するメッセージ
+const BusyMessageContent = "⌛混雑しているため処理できませんでした。時間をおいてもう一度お試しください。"
 
 func (id interactionCustomID) toString() string {
 	return string(id)
@@ -62,6 +128,8 @@ func NewOpenRecruitSlashCommand(service *recruit.RecruitUsecase) *openRecruitSla
 
 func (command *openRecruitSlashCommand) CreateCommand() *discordgo.ApplicationCommand {
 	minValue := 1.0
+	titleMaxLength := maxTitleLength
+	descriptionMaxLength := maxDescriptionLength
 	return &discordgo.ApplicationCommand{
 		Name:        recruitOpenCommandName,
 		Description: "募集を作成します。",
@@ -73,6 +141,44 @@ func (command *openRecruitSlashCommand) CreateCommand() *discordgo.ApplicationCo
 				Required:    true,
 				MinValue:    &minValue,
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        recruitStartAtArgName,
+				Description: "開始時刻を入力します。(例: 21:30、明日 20:00、10/20 21:00)",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        recruitTitleArgName,
+				Description: "募集のタイトルを入力します。(例: Apex ランク)",
+				Required:    false,
+				MaxLength:   titleMaxLength,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        recruitContentArgName,
+				Description: "募集の内容を入力します。",
+				Required:    false,
+				MaxLength:   descriptionMaxLength,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionRole,
+				Name:        recruitRoleArgName,
+				Description: "募集の対象ロールを指定します。募集開始時と空きが出た時にメンションします。",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        recruitRoleOnlyArgName,
+				Description: "対象ロールを持つメンバーのみ参加できるようにします。",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        recruitDetailArgName,
+				Description: "タイトルと内容を入力欄で入力します。長い内容や改行を含む場合に使用します。",
+				Required:    false,
+			},
 		},
 	}
 }
@@ -89,9 +195,101 @@ func (command *openRecruitSlashCommand) MatchInteractionID(interactionID string)
 	return command.InteractionID() == interactionID
 }
 
-func (command *openRecruitSlashCommand) Handle(session *discordgo.Session, interaction *discordgo.Interaction) error {
+func (command *openRecruitSlashCommand) Handle(ctx context.Context, session discord.Client, interaction *discordgo.Interaction) error {
 	log.Printf("[RECRUIT] user %s opened recruitment", interaction.Member.User.ID)
 
+	optionMap := command.getOptionMap(interaction)
+
+	// 募集人数引数の取得
+	opt, ok := optionMap[recruitArgName]
+	if !ok || opt == nil {
+		return fmt.Errorf("required option %q not found", recruitArgName)
+	}
+	params := openRecruitParams{
+		maxCapacity: int(opt.IntValue()),
+	}
+
+	// 開始時刻引数の取得(任意)
+	// 入力誤りは募集メッセージを送信する前に本人にのみ通知する
+	if opt, ok := optionMap[recruitStartAtArgName]; ok && opt != nil {
+		parsed, err := recruit.ParseStartAt(opt.StringValue(), time.Now())
+		if err != nil {
+			return command.respondEphemeral(
+				session,
+				interaction,
+				fmt.Sprintf("❗%v\n例: `21:30`、`明日 20:00`、`10/20 21:00`", err),
+			)
+		}
+		params.startAt = &parsed
+	}
+
+	// タイトルと内容の取得(任意)
+	if opt, ok := optionMap[recruitTitleArgName]; ok && opt != nil {
+		params.title = strings.TrimSpace(opt.StringValue())
+	}
+	if opt, ok := optionMap[recruitContentArgName]; ok && opt != nil {
+		params.description = strings.TrimSpace(opt.StringValue())
+	}
+
+	// 対象ロールの取得(任意)
+	if opt, ok := optionMap[recruitRoleArgName]; ok && opt != nil {
+		params.roleID = recruit.RoleID(opt.Value.(string))
+	}
+	if opt, ok := optionMap[recruitRoleOnlyArgName]; ok && opt != nil && opt.BoolValue() {
+		if params.roleID == "" {
+			return command.respondEphemeral(
+				session,
+				interaction,
+				fmt.Sprintf("❗`%s`を指定する場合は`%s`も指定してください。", recruitRoleOnlyArgName, recruitRoleArgName),
+			)
+		}
+		params.roleRestricted = true
+	}
+
+	// 詳細入力が指定された場合は入力欄を表示し、送信後に募集を作成する
+	if opt, ok := optionMap[recruitDetailArgName]; ok && opt != nil && opt.BoolValue() {
+		return respondOpenRecruitModal(session, interaction, params)
+	}
+
+	return openRecruit(ctx, session, interaction, command.service, params)
+}
+
+// openRecruitParams は募集作成時の入力値
+type openRecruitParams struct {
+	maxCapacity    int
+	startAt        *time.Time
+	title          string
+	description    string
+	roleID         recruit.RoleID
+	roleRestricted bool
+}
+
+func (params openRecruitParams) toOpenOptions() []recruit.OpenOption {
+	var opts []recruit.OpenOption
+	if params.startAt != nil {
+		opts = append(opts, recruit.WithStartAt(*params.startAt))
+	}
+	if params.title != "" {
+		opts = append(opts, recruit.WithTitle(params.title))
+	}
+	if params.description != "" {
+		opts = append(opts, recruit.WithDescription(params.description))
+	}
+	if params.roleID != "" {
+		opts = append(opts, recruit.WithRole(params.roleID, params.roleRestricted))
+	}
+	return opts
+}
+
+// openRecruit は募集メッセージを送信し、募集を作成する
+// スラッシュコマンドと募集作成モーダルの送信で共通の処理
+func openRecruit(
+	ctx context.Context,
+	session discord.Client,
+	interaction *discordgo.Interaction,
+	service *recruit.RecruitUsecase,
+	params openRecruitParams,
+) error {
 	// 反応を待つようにACKを送信
 	err := session.InteractionRespond(interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
@@ -101,16 +299,13 @@ func (command *openRecruitSlashCommand) Handle(session *discordgo.Session, inter
 		return err
 	}
 
-	// 募集人数引数の取得
-	optionMap := command.getOptionMap(interaction)
-	opt, ok := optionMap[recruitArgName]
-	if !ok || opt == nil {
-		return fmt.Errorf("required option %q not found", recruitArgName)
-	}
-	maxCapacity := int(opt.IntValue())
-
 	// 初期状態の募集メッセージを作成、送信
-	initialState := InitState(interaction.Member.User.ID, maxCapacity)
+	initialState := InitState(interaction.Member.User.ID, params.maxCapacity)
+	initialState.startAt = params.startAt
+	initialState.title = params.title
+	initialState.description = params.description
+	initialState.roleID = params.roleID
+	initialState.roleRestricted = params.roleRestricted
 	sentMessage, err := session.InteractionResponseEdit(interaction, &discordgo.WebhookEdit{
 		Embeds:     &[]*discordgo.MessageEmbed{initialState.toEmbed()},
 		Components: &[]discordgo.MessageComponent{initialState.toComponent()},
@@ -120,17 +315,15 @@ func (command *openRecruitSlashCommand) Handle(session *discordgo.Session, inter
 		return fmt.Errorf("failed to send message. channelId: %s, %w", interaction.ChannelID, err)
 	}
 
-	ctx, cancel := createContextWithTimeout()
-	defer cancel()
-
 	// 募集の作成
-	_, err = command.service.Open(
+	view, err := service.Open(
 		ctx,
 		recruit.GuildID(interaction.GuildID),
 		recruit.ChannelID(interaction.ChannelID),
 		recruit.MessageID(sentMessage.ID),
-		maxCapacity,
+		params.maxCapacity,
 		recruit.UserID(interaction.Member.User.ID),
+		params.toOpenOptions()...,
 	)
 
 	if err != nil {
@@ -139,31 +332,183 @@ func (command *openRecruitSlashCommand) Handle(session *discordgo.Session, inter
 		return err
 	}
 
+	// 対象ロールにメンションして募集開始を知らせる
+	if params.roleID != "" {
+		return replyRecruitMessage(session, view, createOpenMessage(view))
+	}
 	return nil
 }
 
+func createOpenMessage(view *recruit.RecruitView) string {
+	return fmt.Sprintf(
+		"%s 募集が開始されました。 @%d",
+		discord.FormatRoleMention(string(view.Meta.RoleID)),
+		view.RemainingSlots(),
+	)
+}
+
+// respondOpenRecruitModal はタイトルと内容の入力欄を表示する
+// 募集人数、開始時刻、対象ロールは送信時に引き継げるようcustomIDに埋め込む
+// customIDは100文字までのため、キーは短くしている
+func respondOpenRecruitModal(
+	session discord.Client,
+	interaction *discordgo.Interaction,
+	params openRecruitParams,
+) error {
+	items := map[string]string{
+		customIDKey: interactionOpenSubmit.toString(),
+		capacityKey: strconv.Itoa(params.maxCapacity),
+	}
+	if params.startAt != nil {
+		items[startAtKey] = strconv.FormatInt(params.startAt.Unix(), 10)
+	}
+	if params.roleID != "" {
+		items[roleKey] = string(params.roleID)
+	}
+	if params.roleRestricted {
+		items[roleOnlyKey] = "1"
+	}
+	customID, err := encodeCustomID(items)
+	if err != nil {
+		return err
+	}
+
+	return session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: customID,
+			Title:    "募集の作成",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:  titleInputID,
+							Label:     recruitTitleArgName,
+							Style:     discordgo.TextInputShort,
+							Value:     params.title,
+							Required:  false,
+							MaxLength: maxTitleLength,
+						},
+					},
+				},
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:  descriptionInputID,
+							Label:     recruitContentArgName,
+							Style:     discordgo.TextInputParagraph,
+							Value:     params.description,
+							Required:  false,
+							MaxLength: maxDescriptionLength,
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// openRecruitModalCommand は募集作成モーダルの送信を受けて募集を作成するコマンド
+type openRecruitModalCommand struct {
+	customIDInteractionCommand
+	service *recruit.RecruitUsecase
+}
+
+func NewOpenRecruitModalCommand(service *recruit.RecruitUsecase) *openRecruitModalCommand {
+	return &openRecruitModalCommand{
+		service: service,
+		customIDInteractionCommand: customIDInteractionCommand{
+			customID: interactionOpenSubmit.toString(),
+		},
+	}
+}
+
+func (command *openRecruitModalCommand) InteractionType() discordgo.InteractionType {
+	return discordgo.InteractionModalSubmit
+}
+
+func (command *openRecruitModalCommand) Handle(ctx context.Context, session discord.Client, interaction *discordgo.Interaction) error {
+	log.Printf("[RECRUIT] user %s opened recruitment with details", interaction.Member.User.ID)
+
+	data := interaction.ModalSubmitData()
+	params, err := decodeOpenRecruitParams(data.CustomID)
+	if err != nil {
+		return err
+	}
+	params.title = strings.TrimSpace(modalTextValue(data, titleInputID))
+	params.description = strings.TrimSpace(modalTextValue(data, descriptionInputID))
+
+	return openRecruit(ctx, session, interaction, command.service, params)
+}
+
+// decodeOpenRecruitParams は募集作成モーダルのcustomIDから募集人数と開始時刻を取り出す
+func decodeOpenRecruitParams(customID string) (openRecruitParams, error) {
+	items, err := decodeCustomID(customID)
+	if err != nil {
+		return openRecruitParams{}, err
+	}
+
+	maxCapacity, err := strconv.Atoi(items[capacityKey])
+	if err != nil {
+		return openRecruitParams{}, fmt.Errorf("invalid capacity: %w", err)
+	}
+	params := openRecruitParams{maxCapacity: maxCapacity}
+
+	if value, ok := items[startAtKey]; ok {
+		unix, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return openRecruitParams{}, fmt.Errorf("invalid start at: %w", err)
+		}
+		startAt := time.Unix(unix, 0)
+		params.startAt = &startAt
+	}
+	params.roleID = recruit.RoleID(items[roleKey])
+	params.roleRestricted = items[roleOnlyKey] == "1"
+	return params, nil
+}
+
 type recruitState struct {
-	maxCapacity  int
-	author       recruit.UserID
-	joinUsers    []recruit.UserID
-	declineUsers []recruit.UserID
+	maxCapacity    int
+	author         recruit.UserID
+	status         recruit.RecruitStatus
+	closeReason    recruit.CloseReason
+	startAt        *time.Time
+	title          string
+	description    string
+	roleID         recruit.RoleID
+	roleRestricted bool
+	joinUsers      []recruit.UserID
+	declineUsers   []recruit.UserID
+	tentativeUsers []recruit.UserID
+	waitlistUsers  []recruit.UserID
 }
 
 func InitState(authorID string, maxCapacity int) *recruitState {
 	return &recruitState{
-		maxCapacity:  maxCapacity,
-		author:       recruit.UserID(authorID),
-		joinUsers:    []recruit.UserID{recruit.UserID(authorID)},
-		declineUsers: []recruit.UserID{},
+		maxCapacity:    maxCapacity,
+		author:         recruit.UserID(authorID),
+		status:         recruit.RecruitStatusOpened,
+		joinUsers:      []recruit.UserID{recruit.UserID(authorID)},
+		declineUsers:   []recruit.UserID{},
+		tentativeUsers: []recruit.UserID{},
 	}
 }
 
 func fromRecruitView(view *recruit.RecruitView) *recruitState {
 	return &recruitState{
-		maxCapacity:  view.Meta.MaxCapacity,
-		author:       view.Meta.AuthorID,
-		joinUsers:    view.JoinedUsers,
-		declineUsers: view.DeclinedUsers,
+		maxCapacity:    view.Meta.MaxCapacity,
+		author:         view.Meta.AuthorID,
+		status:         view.Meta.Status,
+		closeReason:    view.Meta.CloseReason,
+		startAt:        view.Meta.StartAt,
+		title:          view.Meta.Title,
+		description:    view.Meta.Description,
+		roleID:         view.Meta.RoleID,
+		roleRestricted: view.Meta.RoleRestricted,
+		joinUsers:      view.JoinedUsers,
+		declineUsers:   view.DeclinedUsers,
+		tentativeUsers: view.TentativeUsers,
+		waitlistUsers:  view.WaitlistedUsers,
 	}
 }
 
@@ -175,6 +520,21 @@ func (state *recruitState) toDeclineUsersString() string {
 	return state.toUsersString(state.declineUsers)
 }
 
+func (state *recruitState) toTentativeUsersString() string {
+	return state.toUsersString(state.tentativeUsers)
+}
+
+func (state *recruitState) toWaitlistUsersString() string {
+	var b strings.Builder
+	for i, id := range state.waitlistUsers {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "%d. %s", i+1, discord.FormatMention(string(id)))
+	}
+	return b.String()
+}
+
 func (state *recruitState) toUsersString(userIds []recruit.UserID) string {
 	var b strings.Builder
 	for i, id := range userIds {
@@ -187,11 +547,45 @@ func (state *recruitState) toUsersString(userIds []recruit.UserID) string {
 	return b.String()
 }
 
+func (state *recruitState) toStartAtString() string {
+	return fmt.Sprintf(
+		"%s (%s)",
+		discord.FormatTimestamp(*state.startAt, discord.TimestampShortDateTime),
+		discord.FormatTimestamp(*state.startAt, discord.TimestampRelative),
+	)
+}
+
+func (state *recruitState) toRoleString() string {
+	role := discord.FormatRoleMention(string(state.roleID))
+	if state.roleRestricted {
+		return fmt.Sprintf("%s (ロール保持者のみ参加可)", role)
+	}
+	return role
+}
+
+// toTitleString は埋め込みのタイトルを返す
+// タイトル未指定の場合は固定の文言を表示する
+func (state *recruitState) toTitleString() string {
+	title := state.title
+	if title == "" {
+		title = "募集開始"
+	}
+	return fmt.Sprintf("📢 %s @%d", title, state.maxCapacity)
+}
+
+func (state *recruitState) toDescriptionString(author string) string {
+	description := fmt.Sprintf("%s が募集を始めました", author)
+	if state.description == "" {
+		return description
+	}
+	return fmt.Sprintf("%s\n\n%s", description, state.description)
+}
+
 func (state *recruitState) toEmbed() *discordgo.MessageEmbed {
 	author := discord.FormatMention(string(state.author))
-	return &discordgo.MessageEmbed{
-		Title:       fmt.Sprintf("📢 募集開始 @%d", state.maxCapacity),
-		Description: fmt.Sprintf("%s が募集を始めました", author),
+	embed := &discordgo.MessageEmbed{
+		Title:       state.toTitleString(),
+		Description: state.toDescriptionString(author),
 		Fields: []*discordgo.MessageEmbedField{
 			{
 				Name:   joinLabel,
@@ -203,11 +597,65 @@ func (state *recruitState) toEmbed() *discordgo.MessageEmbed {
 				Value:  state.toDeclineUsersString(),
 				Inline: true,
 			},
+			{
+				Name:   tentativeLabel,
+				Value:  state.toTentativeUsersString(),
+				Inline: true,
+			},
 		},
-		Color: 0xffa500,
+		Color: openedColor,
+	}
+
+	if len(state.waitlistUsers) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   waitlistLabel,
+			Value:  state.toWaitlistUsersString(),
+			Inline: true,
+		})
 	}
+
+	// 開始時刻と対象ロールは参加者欄より先に表示する
+	var headerFields []*discordgo.MessageEmbedField
+	if state.startAt != nil {
+		headerFields = append(headerFields, &discordgo.MessageEmbedField{
+			Name:  startAtLabel,
+			Value: state.toStartAtString(),
+		})
+	}
+	if state.roleID != "" {
+		headerFields = append(headerFields, &discordgo.MessageEmbedField{
+			Name:  roleLabel,
+			Value: state.toRoleString(),
+		})
+	}
+	embed.Fields = append(headerFields, embed.Fields...)
+
+	switch state.status {
+	case recruit.RecruitStatusStarted:
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: startedLabel}
+	case recruit.RecruitStatusClosed:
+		embed.Color = closedColor
+		if state.closeReason == recruit.CloseReasonExpired {
+			embed.Footer = &discordgo.MessageEmbedFooter{Text: expiredLabel}
+		} else {
+			embed.Footer = &discordgo.MessageEmbedFooter{Text: closedLabel}
+		}
+	}
+
+	return embed
+}
+
+// toComponents は募集メッセージに付与するコンポーネントを返す
+// 期限切れの募集にはボタンを表示しない
+func (state *recruitState) toComponents() []discordgo.MessageComponent {
+	if state.status == recruit.RecruitStatusClosed && state.closeReason == recruit.CloseReasonExpired {
+		return []discordgo.MessageComponent{}
+	}
+	return []discordgo.MessageComponent{state.toComponent()}
 }
 
+// toComponent は参加/不参加/未定と管理ボタンの行を返す
+// 作成者が締め切った募集は参加系のボタンを無効化し、再開できるよう管理ボタンのみ残す
 func (state *recruitState) toComponent() discordgo.ActionsRow {
 	joinCustomID, _ := encodeCustomID(map[string]string{
 		customIDKey: interactionJoin.toString(),
@@ -215,6 +663,13 @@ func (state *recruitState) toComponent() discordgo.ActionsRow {
 	declineCustomID, _ := encodeCustomID(map[string]string{
 		customIDKey: interactionDecline.toString(),
 	})
+	tentativeCustomID, _ := encodeCustomID(map[string]string{
+		customIDKey: interactionTentative.toString(),
+	})
+	manageCustomID, _ := encodeCustomID(map[string]string{
+		customIDKey: interactionManage.toString(),
+	})
+	closed := state.status == recruit.RecruitStatusClosed
 
 	return discordgo.ActionsRow{
 		Components: []discordgo.MessageComponent{
@@ -222,11 +677,24 @@ func (state *recruitState) toComponent() discordgo.ActionsRow {
 				Label:    joinLabel,
 				Style:    discordgo.PrimaryButton,
 				CustomID: joinCustomID,
+				Disabled: closed,
 			},
 			discordgo.Button{
 				Label:    declineLabel,
 				Style:    discordgo.SecondaryButton,
 				CustomID: declineCustomID,
+				Disabled: closed,
+			},
+			discordgo.Button{
+				Label:    tentativeLabel,
+				Style:    discordgo.SecondaryButton,
+				CustomID: tentativeCustomID,
+				Disabled: closed,
+			},
+			discordgo.Button{
+				Label:    manageLabel,
+				Style:    discordgo.SecondaryButton,
+				CustomID: manageCustomID,
 			},
 		},
 	}
@@ -316,7 +784,7 @@ func decodeCustomID(encodedStr string) (map[string]string, error) {
 }
 
 func (command *customIDInteractionCommand) editInteractionResponse(
-	session *discordgo.Session,
+	session discord.Client,
 	interaction *discordgo.Interaction,
 	message string,
 ) {
@@ -324,7 +792,7 @@ func (command *customIDInteractionCommand) editInteractionResponse(
 }
 
 func (command *customIDInteractionCommand) editInteractionResponseWithComponent(
-	session *discordgo.Session,
+	session discord.Client,
 	interaction *discordgo.Interaction,
 	message string,
 	component *[]discordgo.MessageComponent,
@@ -364,6 +832,16 @@ func NewDeclineRecruitCommand(service *recruit.RecruitUsecase) *participantActio
 	}
 }
 
+func NewTentativeRecruitCommand(service *recruit.RecruitUsecase) *participantActionCommand {
+	return &participantActionCommand{
+		service:    service,
+		actionType: recruit.ParticipantStatusTentative,
+		customIDInteractionCommand: customIDInteractionCommand{
+			customID: interactionTentative.toString(),
+		},
+	}
+}
+
 func NewCancelRecruitCommand(service *recruit.RecruitUsecase) *participantActionCommand {
 	return &participantActionCommand{
 		service:    service,
@@ -378,11 +856,11 @@ func (command *participantActionCommand) InteractionType() discordgo.Interaction
 	return discordgo.InteractionMessageComponent
 }
 
-func (command *participantActionCommand) Handle(session *discordgo.Session, interaction *discordgo.Interaction) error {
+func (command *participantActionCommand) Handle(ctx context.Context, session discord.Client, interaction *discordgo.Interaction) error {
 	log.Printf("[RECRUIT] user %s action: %v", interaction.Member.User.ID, command.actionType)
 
 	// 3秒以内にACKする。
-	// 参加/不参加: Deferredメッセージを送信して待機ACK
+	// 参加/不参加/未定: Deferredメッセージを送信して待機ACK
 	// キャンセル: Deferredメッセージは送信せずに待機ACK
 	var responseType discordgo.InteractionResponseType
 	if command.actionType == recruit.ParticipantStatusCanceled {
@@ -411,22 +889,25 @@ func (command *participantActionCommand) Handle(session *discordgo.Session, inte
 		return err
 	}
 
-	ctx, cancel := createContextWithTimeout()
-	defer cancel()
+	// 対象ロールが限定された募集の参加可否判定に使用する
+	roles := make([]recruit.RoleID, 0, len(interaction.Member.Roles))
+	for _, role := range interaction.Member.Roles {
+		roles = append(roles, recruit.RoleID(role))
+	}
 
 	// ビジネスロジック呼び出し
-	result, err := command.executeAction(ctx, channelID, messageID, actorID)
+	result, err := command.executeAction(ctx, channelID, messageID, actorID, roles)
 	if err != nil {
-		return command.handleActionError(session, interaction, err)
+		return command.handleActionError(ctx, session, interaction, err)
 	}
 
 	// 募集メッセージの編集
-	if err := command.updateRecruitMessage(session, result.CurrentView); err != nil {
+	if err := updateRecruitMessage(ctx, session, command.service, result.CurrentView); err != nil {
 		return err
 	}
 
 	// BOTのephemeralメッセージを削除
-	// 参加/不参加: 送信したDeferredメッセージを削除
+	// 参加/不参加/未定: 送信したDeferredメッセージを削除
 	// キャンセル: この処理を呼び出したキャンセルボタン付きephemeralメッセージを削除
 	_ = session.InteractionResponseDelete(interaction)
 	// 追加メッセージの送信またはエフェメラルメッセージの完了処理
@@ -452,12 +933,15 @@ func (command *participantActionCommand) executeAction(
 	channelID recruit.ChannelID,
 	messageID recruit.MessageID,
 	userID recruit.UserID,
+	roles []recruit.RoleID,
 ) (*recruit.ParticipantStatusChangeResult, error) {
 	switch command.actionType {
 	case recruit.ParticipantStatusJoined:
-		return command.service.Join(ctx, channelID, messageID, userID)
+		return command.service.Join(ctx, channelID, messageID, userID, roles)
 	case recruit.ParticipantStatusDeclined:
 		return command.service.Decline(ctx, channelID, messageID, userID)
+	case recruit.ParticipantStatusTentative:
+		return command.service.Tentative(ctx, channelID, messageID, userID)
 	case recruit.ParticipantStatusCanceled:
 		return command.service.Cancel(ctx, channelID, messageID, userID)
 	default:
@@ -465,47 +949,30 @@ func (command *participantActionCommand) executeAction(
 	}
 }
 
-func (command *participantActionCommand) handleActionError(session *discordgo.Session, interaction *discordgo.Interaction, err error) error {
+func (command *participantActionCommand) handleActionError(
+	ctx context.Context,
+	session discord.Client,
+	interaction *discordgo.Interaction,
+	err error,
+) error {
 	if errors.Is(err, recruit.ErrAuthorCannotJoin) {
-		command.sendAuthorControlPanel(session, interaction)
-		return nil
+		messageID := recruit.MessageID(interaction.Message.ID)
+		return command.sendAuthorControlPanel(ctx, session, interaction, command.service, messageID)
 	}
-	if errors.Is(err, recruit.ErrAlreadyJoined) || errors.Is(err, recruit.ErrAlreadyDeclined) {
+	if errors.Is(err, recruit.ErrAlreadyJoined) ||
+		errors.Is(err, recruit.ErrAlreadyDeclined) ||
+		errors.Is(err, recruit.ErrAlreadyTentative) {
 		command.sendParticipantControlPanel(session, interaction)
 		return nil
 	}
-	command.editInteractionResponse(session, interaction, errorMessageContent)
-	return err
-}
-
-func (command *participantActionCommand) sendAuthorControlPanel(
-	session *discordgo.Session,
-	interaction *discordgo.Interaction,
-) {
-	message := "作成者は参加/辞退できません。\n募集を削除する場合はボタンを押下してください。"
-	deleteCustomID, _ := encodeCustomID(map[string]string{
-		customIDKey:  interactionClose.toString(),
-		messageIDKey: interaction.Message.ID,
-	})
-	button := &[]discordgo.MessageComponent{
-		discordgo.ActionsRow{
-			Components: []discordgo.MessageComponent{
-				discordgo.Button{
-					Label:    "🗑️ 削除",
-					Style:    discordgo.DangerButton,
-					CustomID: deleteCustomID,
-				},
-			},
-		},
-	}
-	command.editInteractionResponseWithComponent(session, interaction, message, button)
+	return command.respondDomainError(session, interaction, err)
 }
 
 func (command *participantActionCommand) sendParticipantControlPanel(
-	session *discordgo.Session,
+	session discord.Client,
 	interaction *discordgo.Interaction,
 ) {
-	message := "既に参加済み/辞退済みです。\nキャンセルする場合はボタンを押下してください。"
+	message := "既に参加済み/辞退済み/未定で回答済みです。\nキャンセルする場合はボタンを押下してください。"
 	cancelCustomID, _ := encodeCustomID(map[string]string{
 		customIDKey:  interactionCancel.toString(),
 		messageIDKey: interaction.Message.ID,
@@ -524,19 +991,35 @@ func (command *participantActionCommand) sendParticipantControlPanel(
 	command.editInteractionResponseWithComponent(session, interaction, message, button)
 }
 
-func (command *participantActionCommand) updateRecruitMessage(session *discordgo.Session, view *recruit.RecruitView) error {
-	state := fromRecruitView(view)
-	_, err := session.ChannelMessageEditComplex(&discordgo.MessageEdit{
-		Channel:    string(view.Meta.ChannelID),
-		ID:         string(view.Meta.MessageID),
-		Embeds:     &[]*discordgo.MessageEmbed{state.toEmbed()},
-		Components: &[]discordgo.MessageComponent{state.toComponent()},
+// updateRecruitMessage は募集メッセージを最新の状態で再描画する
+// 同時に更新された場合に古い状態で上書きしないよう、viewは再描画する募集の特定にのみ使用する
+// 最新の状態を取得できない場合は、より新しい再描画を上書きしないよう再描画しない
+func updateRecruitMessage(
+	ctx context.Context,
+	session discord.Client,
+	service *recruit.RecruitUsecase,
+	view *recruit.RecruitView,
+) error {
+	var editErr error
+	err := service.WithLatestView(ctx, view.Meta.ChannelID, view.Meta.MessageID, func(view *recruit.RecruitView) {
+		state := fromRecruitView(view)
+		components := state.toComponents()
+		_, editErr = session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			Channel:    string(view.Meta.ChannelID),
+			ID:         string(view.Meta.MessageID),
+			Embeds:     &[]*discordgo.MessageEmbed{state.toEmbed()},
+			Components: &components,
+		})
 	})
-	return err
+	if err != nil {
+		log.Printf("[RECRUIT] skipped updating recruit message. messageId: %s, %v", view.Meta.MessageID, err)
+		return nil
+	}
+	return editErr
 }
 
 func (command *participantActionCommand) sendFollowUpMessage(
-	session *discordgo.Session,
+	session discord.Client,
 	result *recruit.ParticipantStatusChangeResult,
 	actorID recruit.UserID,
 ) error {
@@ -544,16 +1027,15 @@ func (command *participantActionCommand) sendFollowUpMessage(
 	switch command.actionType {
 	case recruit.ParticipantStatusJoined:
 		// 参加メッセージを全体に送信
-		return command.replyRecruitMessage(session, view, createJoinMessage(actorID, view))
-	case recruit.ParticipantStatusDeclined, recruit.ParticipantStatusCanceled:
-		// 参加済みから辞退/キャンセルに変更された場合のみ通知
+		// 満員の場合はキャンセル待ちとして登録されている
+		if result.CurrentStatus == recruit.ParticipantStatusWaitlisted {
+			return replyRecruitMessage(session, view, createWaitlistMessage(actorID, view))
+		}
+		return replyRecruitMessage(session, view, createJoinMessage(actorID, view))
+	case recruit.ParticipantStatusDeclined, recruit.ParticipantStatusCanceled, recruit.ParticipantStatusTentative:
+		// 参加済みから辞退/キャンセル/未定に変更された場合のみ通知
 		if result.PreviousStatus != nil && *result.PreviousStatus == recruit.ParticipantStatusJoined {
-			message := fmt.Sprintf(
-				"%s が参加を取り消しました。 @%d",
-				discord.FormatMention(string(actorID)),
-				view.RemainingSlots(),
-			)
-			return command.replyRecruitMessage(session, view, message)
+			return replyRecruitMessage(session, view, createLeaveMessage(actorID, view, result.PromotedUsers))
 		}
 		return nil
 	default:
@@ -561,8 +1043,8 @@ func (command *participantActionCommand) sendFollowUpMessage(
 	}
 }
 
-func (command *participantActionCommand) replyRecruitMessage(
-	session *discordgo.Session,
+func replyRecruitMessage(
+	session discord.Client,
 	view *recruit.RecruitView,
 	content string,
 ) error {
@@ -608,30 +1090,114 @@ func createJoinMessage(actorID recruit.UserID, view *recruit.RecruitView) string
 	return baseContent
 }
 
+func createWaitlistMessage(actorID recruit.UserID, view *recruit.RecruitView) string {
+	return fmt.Sprintf(
+		"%s がキャンセル待ちに登録しました。(%d番目)",
+		discord.FormatMention(string(actorID)),
+		len(view.WaitlistedUsers),
+	)
+}
+
+func createLeaveMessage(actorID recruit.UserID, view *recruit.RecruitView, promotedUsers []recruit.UserID) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s が参加を取り消しました。", discord.FormatMention(string(actorID)))
+	writePromotedUsers(&b, promotedUsers)
+	writeRemainingSlots(&b, view)
+	return b.String()
+}
+
+// writeRemainingSlots は残り枠数を追記する
+// 対象ロールが指定された募集に空きがある場合は、ロールにメンションして募集を知らせる
+func writeRemainingSlots(b *strings.Builder, view *recruit.RecruitView) {
+	remaining := view.RemainingSlots()
+	fmt.Fprintf(b, " @%d", remaining)
+	if view.Meta.RoleID != "" && remaining > 0 {
+		fmt.Fprintf(b, "\n%s", discord.FormatRoleMention(string(view.Meta.RoleID)))
+	}
+}
+
+// writePromotedUsers はキャンセル待ちから繰り上がったユーザーの通知を追記する
+func writePromotedUsers(b *strings.Builder, promotedUsers []recruit.UserID) {
+	for _, u := range promotedUsers {
+		fmt.Fprintf(b, "\n%s がキャンセル待ちから繰り上げ参加になりました。", discord.FormatMention(string(u)))
+	}
+}
+
 func ptr(s string) *string {
 	return &s
 }
 
-type closeRecruitCommand struct {
+// domainErrorMessage はユーザーにそのまま表示してよいドメインエラーのメッセージを返す
+func domainErrorMessage(err error) (string, bool) {
+	displayable := []error{
+		recruit.ErrRecruitNotFound,
+		recruit.ErrNotAuthor,
+		recruit.ErrRecruitClosed,
+		recruit.ErrRecruitNotClosed,
+		recruit.ErrInvalidCapacity,
+		recruit.ErrCapacityBelowJoined,
+		recruit.ErrCannotKickAuthor,
+		recruit.ErrParticipantNotFound,
+		recruit.ErrRoleRequired,
+		dice.ErrNoCandidates,
+		dice.ErrInvalidTeamCount,
+	}
+	for _, target := range displayable {
+		if errors.Is(err, target) {
+			return fmt.Sprintf("❗%s。", target.Error()), true
+		}
+	}
+	return "", false
+}
+
+// respondDomainError はエラーのメッセージを操作パネルに表示する
+// ユーザーに表示してよいエラーの場合はnilを返す
+func (command *customIDInteractionCommand) respondDomainError(
+	session discord.Client,
+	interaction *discordgo.Interaction,
+	err error,
+) error {
+	if message, ok := domainErrorMessage(err); ok {
+		command.editInteractionResponse(session, interaction, message)
+		return nil
+	}
+	command.editInteractionResponse(session, interaction, ErrorMessageContent)
+	return err
+}
+
+// recruitStatusCommand は作成者の操作パネルから募集を締め切る/再開するコマンド
+type recruitStatusCommand struct {
 	customIDInteractionCommand
 	service *recruit.RecruitUsecase
+	status  recruit.RecruitStatus
 }
 
-func NewCloseRecruitCommand(service *recruit.RecruitUsecase) *closeRecruitCommand {
-	return &closeRecruitCommand{
+func NewCloseRecruitCommand(service *recruit.RecruitUsecase) *recruitStatusCommand {
+	return &recruitStatusCommand{
 		service: service,
+		status:  recruit.RecruitStatusClosed,
 		customIDInteractionCommand: customIDInteractionCommand{
 			customID: interactionClose.toString(),
 		},
 	}
 }
 
-func (command *closeRecruitCommand) InteractionType() discordgo.InteractionType {
+func NewReopenRecruitCommand(service *recruit.RecruitUsecase) *recruitStatusCommand {
+	return &recruitStatusCommand{
+		service: service,
+		status:  recruit.RecruitStatusOpened,
+		customIDInteractionCommand: customIDInteractionCommand{
+			customID: interactionReopen.toString(),
+		},
+	}
+}
+
+func (command *recruitStatusCommand) InteractionType() discordgo.InteractionType {
 	return discordgo.InteractionMessageComponent
 }
 
-func (command *closeRecruitCommand) Handle(session *discordgo.Session, interaction *discordgo.Interaction) error {
-	log.Printf("[RECRUIT] user %s closed recruitment", interaction.Member.User.ID)
+func (command *recruitStatusCommand) Handle(ctx context.Context, session discord.Client, interaction *discordgo.Interaction) error {
+	log.Printf("[RECRUIT] user %s changed recruitment status: %v", interaction.Member.User.ID, command.status)
 
 	err := session.InteractionRespond(interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseDeferredMessageUpdate,
@@ -641,43 +1207,40 @@ func (command *closeRecruitCommand) Handle(session *discordgo.Session, interacti
 		return err
 	}
 
-	// 削除ボタンに埋め込まれた値をデコード
-	customID := interaction.MessageComponentData().CustomID
-	items, err := decodeCustomID(customID)
+	// 操作パネルのボタンに埋め込まれた募集メッセージIDをデコード
+	recruitMessageID, err := decodeMessageID(interaction.MessageComponentData().CustomID)
 	if err != nil {
 		return err
 	}
 
 	actorID := recruit.UserID(interaction.Member.User.ID)
 	channelID := recruit.ChannelID(interaction.ChannelID)
-	recruitMessageIDStr := items[messageIDKey]
-	recruitMessageID := recruit.MessageID(recruitMessageIDStr)
 
-	ctx, cancel := createContextWithTimeout()
-	defer cancel()
-
-	// 削除ロジック実行
-	err = command.service.Close(ctx, channelID, recruitMessageID, actorID)
+	view, err := command.executeAction(ctx, channelID, recruitMessageID, actorID)
 	if err != nil {
-		// TODO: 表示エラーメッセージの具体化
-		command.editInteractionResponse(session, interaction, errorMessageContent)
-		return err
+		return command.respondDomainError(session, interaction, err)
 	}
 
-	// 元の募集メッセージの内容を削除用に差し替え
-	_, err = session.ChannelMessageEditComplex(&discordgo.MessageEdit{
-		Channel:    interaction.ChannelID,
-		ID:         recruitMessageIDStr,
-		Content:    ptr("募集は削除されました。"),
-		Embeds:     &[]*discordgo.MessageEmbed{},
-		Components: &[]discordgo.MessageComponent{},
-	})
-	// 削除ボタンインタラクションを削除
+	// 募集メッセージを更新後の状態で再描画
+	err = updateRecruitMessage(ctx, session, command.service, view)
+	// 操作パネルを削除
 	_ = session.InteractionResponseDelete(interaction)
 
 	return err
 }
 
-func createContextWithTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), 5*time.Second)
+func (command *recruitStatusCommand) executeAction(
+	ctx context.Context,
+	channelID recruit.ChannelID,
+	messageID recruit.MessageID,
+	actorID recruit.UserID,
+) (*recruit.RecruitView, error) {
+	switch command.status {
+	case recruit.RecruitStatusClosed:
+		return command.service.Close(ctx, channelID, messageID, actorID)
+	case recruit.RecruitStatusOpened:
+		return command.service.Reopen(ctx, channelID, messageID, actorID)
+	default:
+		return nil, fmt.Errorf("invalid recruit status: %s", command.status)
+	}
 }
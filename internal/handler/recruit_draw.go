@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"at-bot/internal/dice"
+	"at-bot/internal/discord"
+	"at-bot/internal/recruit"
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const teamsInputID = "teams"
+
+// pickMemberCommand は操作パネルの抽選ボタンから参加者を1人選ぶコマンド
+type pickMemberCommand struct {
+	customIDInteractionCommand
+	recruitService *recruit.RecruitUsecase
+	diceService    *dice.DiceUsecase
+}
+
+func NewPickMemberCommand(recruitService *recruit.RecruitUsecase, diceService *dice.DiceUsecase) *pickMemberCommand {
+	return &pickMemberCommand{
+		recruitService: recruitService,
+		diceService:    diceService,
+		customIDInteractionCommand: customIDInteractionCommand{
+			customID: interactionPick.toString(),
+		},
+	}
+}
+
+func (command *pickMemberCommand) InteractionType() discordgo.InteractionType {
+	return discordgo.InteractionMessageComponent
+}
+
+func (command *pickMemberCommand) Handle(ctx context.Context, session discord.Client, interaction *discordgo.Interaction) error {
+	log.Printf("[RECRUIT] user %s picked a member", interaction.Member.User.ID)
+
+	err := session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	recruitMessageID, err := decodeMessageID(interaction.MessageComponentData().CustomID)
+	if err != nil {
+		return err
+	}
+
+	actorID := recruit.UserID(interaction.Member.User.ID)
+	channelID := recruit.ChannelID(interaction.ChannelID)
+
+	view, err := command.recruitService.GetOwned(ctx, channelID, recruitMessageID, actorID)
+	if err != nil {
+		return command.respondDomainError(session, interaction, err)
+	}
+
+	picked, err := command.diceService.Pick(len(view.JoinedUsers))
+	if err != nil {
+		return command.respondDomainError(session, interaction, err)
+	}
+	_ = session.InteractionResponseDelete(interaction)
+
+	return replyRecruitMessage(session, view, createPickMessage(view.JoinedUsers, picked))
+}
+
+// teamsModalCommand は操作パネルのチーム分けボタンからチーム数の入力モーダルを開くコマンド
+type teamsModalCommand struct {
+	customIDInteractionCommand
+}
+
+func NewTeamsModalCommand() *teamsModalCommand {
+	return &teamsModalCommand{
+		customIDInteractionCommand: customIDInteractionCommand{
+			customID: interactionTeams.toString(),
+		},
+	}
+}
+
+func (command *teamsModalCommand) InteractionType() discordgo.InteractionType {
+	return discordgo.InteractionMessageComponent
+}
+
+func (command *teamsModalCommand) Handle(ctx context.Context, session discord.Client, interaction *discordgo.Interaction) error {
+	recruitMessageID, err := decodeMessageID(interaction.MessageComponentData().CustomID)
+	if err != nil {
+		return err
+	}
+
+	// モーダルの送信時に対象の募集を特定できるよう、募集メッセージIDを引き継ぐ
+	submitCustomID, err := encodeCustomID(map[string]string{
+		customIDKey:  interactionTeamsSubmit.toString(),
+		messageIDKey: string(recruitMessageID),
+	})
+	if err != nil {
+		return err
+	}
+
+	return session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: submitCustomID,
+			Title:    "チーム分け",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    teamsInputID,
+							Label:       "チーム数",
+							Style:       discordgo.TextInputShort,
+							Placeholder: "例: 2",
+							Required:    true,
+							MinLength:   1,
+							MaxLength:   2,
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// splitTeamsCommand はチーム分けモーダルの送信を受けて参加者をチームに分けるコマンド
+type splitTeamsCommand struct {
+	customIDInteractionCommand
+	recruitService *recruit.RecruitUsecase
+	diceService    *dice.DiceUsecase
+}
+
+func NewSplitTeamsCommand(recruitService *recruit.RecruitUsecase, diceService *dice.DiceUsecase) *splitTeamsCommand {
+	return &splitTeamsCommand{
+		recruitService: recruitService,
+		diceService:    diceService,
+		customIDInteractionCommand: customIDInteractionCommand{
+			customID: interactionTeamsSubmit.toString(),
+		},
+	}
+}
+
+func (command *splitTeamsCommand) InteractionType() discordgo.InteractionType {
+	return discordgo.InteractionModalSubmit
+}
+
+func (command *splitTeamsCommand) Handle(ctx context.Context, session discord.Client, interaction *discordgo.Interaction) error {
+	log.Printf("[RECRUIT] user %s split members into teams", interaction.Member.User.ID)
+
+	// 操作パネル上のボタンから開いたモーダルのため、操作パネルを更新対象にする
+	err := session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	data := interaction.ModalSubmitData()
+	recruitMessageID, err := decodeMessageID(data.CustomID)
+	if err != nil {
+		return err
+	}
+
+	teams, err := strconv.Atoi(strings.TrimSpace(modalTextValue(data, teamsInputID)))
+	if err != nil {
+		command.editInteractionResponse(session, interaction, "❗チーム数は数値で入力してください。")
+		return nil
+	}
+
+	actorID := recruit.UserID(interaction.Member.User.ID)
+	channelID := recruit.ChannelID(interaction.ChannelID)
+
+	view, err := command.recruitService.GetOwned(ctx, channelID, recruitMessageID, actorID)
+	if err != nil {
+		return command.respondDomainError(session, interaction, err)
+	}
+
+	split, err := command.diceService.SplitTeams(len(view.JoinedUsers), teams)
+	if err != nil {
+		return command.respondDomainError(session, interaction, err)
+	}
+	_ = session.InteractionResponseDelete(interaction)
+
+	return replyRecruitMessage(session, view, createTeamsMessage(view.JoinedUsers, split))
+}
+
+func createPickMessage(users []recruit.UserID, picked int) string {
+	return fmt.Sprintf(
+		"🎲 抽選の結果、%s が選ばれました。(%d人から抽選)",
+		discord.FormatMention(string(users[picked])),
+		len(users),
+	)
+}
+
+func createTeamsMessage(users []recruit.UserID, teams [][]int) string {
+	var b strings.Builder
+	b.WriteString("🔀 チーム分けの結果")
+	for i, team := range teams {
+		mentions := make([]string, 0, len(team))
+		for _, member := range team {
+			mentions = append(mentions, discord.FormatMention(string(users[member])))
+		}
+		fmt.Fprintf(&b, "\nチーム%d: %s", i+1, strings.Join(mentions, " "))
+	}
+	return b.String()
+}
@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"at-bot/internal/recruit"
+	"testing"
+)
+
+func TestCreatePickMessage(t *testing.T) {
+	users := []recruit.UserID{"user-1", "user-2", "user-3"}
+
+	got := createPickMessage(users, 1)
+	want := "🎲 抽選の結果、<@user-2> が選ばれました。(3人から抽選)"
+	if got != want {
+		t.Errorf("createPickMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestCreateTeamsMessage(t *testing.T) {
+	users := []recruit.UserID{"user-1", "user-2", "user-3", "user-4", "user-5"}
+
+	got := createTeamsMessage(users, [][]int{{4, 0, 2}, {1, 3}})
+	want := "🔀 チーム分けの結果\nチーム1: <@user-5> <@user-1> <@user-3>\nチーム2: <@user-2> <@user-4>"
+	if got != want {
+		t.Errorf("createTeamsMessage() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"at-bot/internal/discord"
+	"at-bot/internal/recruit"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// 募集一覧コマンド用の固定値
+const (
+	recruitsCommandName      = "recruits"
+	recruitsListSubcommand   = "list"
+	recruitsChannelOnlyArg   = "このチャンネルのみ"
+	maxRecruitListEmbedField = 25
+)
+
+type listRecruitsSlashCommand struct {
+	baseSlashCommand
+	service *recruit.RecruitUsecase
+}
+
+func NewListRecruitsSlashCommand(service *recruit.RecruitUsecase) *listRecruitsSlashCommand {
+	return &listRecruitsSlashCommand{
+		service: service,
+	}
+}
+
+func (command *listRecruitsSlashCommand) CreateCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        recruitsCommandName,
+		Description: "募集を管理します。",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        recruitsListSubcommand,
+				Description: "サーバー内の募集中の募集を一覧表示します。",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        recruitsChannelOnlyArg,
+						Description: "このチャンネルの募集のみ表示します。(省略時: サーバー全体)",
+						Required:    false,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (command *listRecruitsSlashCommand) InteractionType() discordgo.InteractionType {
+	return discordgo.InteractionApplicationCommand
+}
+
+func (command *listRecruitsSlashCommand) InteractionID() string {
+	return recruitsCommandName
+}
+
+func (command *listRecruitsSlashCommand) MatchInteractionID(interactionID string) bool {
+	return command.InteractionID() == interactionID
+}
+
+func (command *listRecruitsSlashCommand) Handle(ctx context.Context, session discord.Client, interaction *discordgo.Interaction) error {
+	subcommand, optionMap := command.getSubcommand(interaction)
+	if subcommand != recruitsListSubcommand {
+		return fmt.Errorf("unknown subcommand: %q", subcommand)
+	}
+
+	log.Printf("[RECRUIT] user %s listed recruitments", interaction.Member.User.ID)
+
+	// チャンネル指定がない場合はギルド全体を対象にする
+	var channelID recruit.ChannelID
+	if opt, ok := optionMap[recruitsChannelOnlyArg]; ok && opt != nil && opt.BoolValue() {
+		channelID = recruit.ChannelID(interaction.ChannelID)
+	}
+
+	views, err := command.service.ListOpened(ctx, recruit.GuildID(interaction.GuildID), channelID)
+	if err != nil {
+		_ = command.respondEphemeral(session, interaction, ErrorMessageContent)
+		return err
+	}
+
+	if len(views) == 0 {
+		return command.respondEphemeral(session, interaction, "募集中の募集はありません。")
+	}
+
+	return session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{createRecruitListEmbed(views)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// createRecruitListEmbed は募集一覧の埋め込みを返す
+// 埋め込みのフィールド数の上限を超える分は件数のみ表示する
+func createRecruitListEmbed(views []*recruit.RecruitView) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("📋 募集一覧 (%d件)", len(views)),
+		Color: openedColor,
+	}
+
+	for i, view := range views {
+		if i >= maxRecruitListEmbedField {
+			embed.Footer = &discordgo.MessageEmbedFooter{
+				Text: fmt.Sprintf("他 %d件", len(views)-maxRecruitListEmbedField),
+			}
+			break
+		}
+		embed.Fields = append(embed.Fields, createRecruitListField(view))
+	}
+
+	return embed
+}
+
+func createRecruitListField(view *recruit.RecruitView) *discordgo.MessageEmbedField {
+	meta := view.Meta
+
+	title := meta.Title
+	if title == "" {
+		title = "募集"
+	}
+	name := fmt.Sprintf("📢 %s 残り@%d", title, view.RemainingSlots())
+	if view.IsFull() {
+		name = fmt.Sprintf("📢 %s 満員", title)
+	}
+
+	lines := []string{
+		fmt.Sprintf(
+			"%s 作成者: %s 参加: %d/%d",
+			discord.FormatChannelMention(string(meta.ChannelID)),
+			discord.FormatMention(string(meta.AuthorID)),
+			// 作成者は募集人数に含めない
+			len(view.JoinedUsers)-1,
+			meta.MaxCapacity,
+		),
+	}
+	if meta.StartAt != nil {
+		lines = append(lines, fmt.Sprintf(
+			"%s %s",
+			startAtLabel,
+			discord.FormatTimestamp(*meta.StartAt, discord.TimestampRelative),
+		))
+	}
+	lines = append(lines, fmt.Sprintf(
+		"[メッセージへ移動](%s)",
+		discord.FormatMessageLink(string(meta.GuildID), string(meta.ChannelID), string(meta.MessageID)),
+	))
+
+	return &discordgo.MessageEmbedField{
+		Name:  name,
+		Value: strings.Join(lines, "\n"),
+	}
+}
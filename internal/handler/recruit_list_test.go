@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"at-bot/internal/recruit"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListRecruitsSlashCommand_CreateCommand(t *testing.T) {
+	cmd := NewListRecruitsSlashCommand(nil)
+	command := cmd.CreateCommand()
+
+	if command.Name != recruitsCommandName {
+		t.Errorf("CreateCommand().Name = %v, want %v", command.Name, recruitsCommandName)
+	}
+
+	if len(command.Options) != 1 || command.Options[0].Name != recruitsListSubcommand {
+		t.Fatalf("CreateCommand().Options = %v, want [%v]", command.Options, recruitsListSubcommand)
+	}
+}
+
+func TestCreateRecruitListField(t *testing.T) {
+	startAt := time.Unix(1700000000, 0)
+	tests := []struct {
+		name        string
+		view        *recruit.RecruitView
+		wantName    string
+		wantContain []string
+	}{
+		{
+			name: "タイトル未指定の場合は固定の文言と残り枠を表示",
+			view: &recruit.RecruitView{
+				Meta: &recruit.RecruitState{
+					GuildID:     "guild-1",
+					ChannelID:   "channel-1",
+					MessageID:   "message-1",
+					AuthorID:    "author",
+					MaxCapacity: 3,
+				},
+				JoinedUsers: []recruit.UserID{"author", "user1"},
+			},
+			wantName: "📢 募集 残り@2",
+			wantContain: []string{
+				"<#channel-1>",
+				"参加: 1/3",
+				"https://discord.com/channels/guild-1/channel-1/message-1",
+			},
+		},
+		{
+			name: "満員の場合はタイトルと満員を表示",
+			view: &recruit.RecruitView{
+				Meta: &recruit.RecruitState{
+					GuildID:     "guild-1",
+					ChannelID:   "channel-1",
+					MessageID:   "message-1",
+					AuthorID:    "author",
+					MaxCapacity: 1,
+					Title:       "Apex",
+					StartAt:     &startAt,
+				},
+				JoinedUsers: []recruit.UserID{"author", "user1"},
+			},
+			wantName: "📢 Apex 満員",
+			wantContain: []string{
+				"参加: 1/1",
+				"<t:1700000000:R>",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := createRecruitListField(tt.view)
+			if field.Name != tt.wantName {
+				t.Errorf("Name = %v, want %v", field.Name, tt.wantName)
+			}
+			for _, want := range tt.wantContain {
+				if !strings.Contains(field.Value, want) {
+					t.Errorf("Value = %v, should contain %v", field.Value, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateRecruitListEmbed_Overflow(t *testing.T) {
+	var views []*recruit.RecruitView
+	for i := 0; i < maxRecruitListEmbedField+2; i++ {
+		views = append(views, &recruit.RecruitView{
+			Meta: &recruit.RecruitState{
+				MessageID:   recruit.MessageID(fmt.Sprint(i)),
+				MaxCapacity: 1,
+			},
+			JoinedUsers: []recruit.UserID{"author"},
+		})
+	}
+
+	embed := createRecruitListEmbed(views)
+
+	if len(embed.Fields) != maxRecruitListEmbedField {
+		t.Errorf("Fields length = %v, want %v", len(embed.Fields), maxRecruitListEmbedField)
+	}
+	if embed.Footer == nil || embed.Footer.Text != "他 2件" {
+		t.Errorf("Footer = %v, want 他 2件", embed.Footer)
+	}
+}
@@ -0,0 +1,454 @@
+package handler
+
+import (
+	"at-bot/internal/discord"
+	"at-bot/internal/recruit"
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// 募集人数変更モーダルの入力欄
+const capacityInputID = "capacity"
+
+// decodeMessageID は操作パネルのcustomIDに埋め込まれた募集メッセージIDを取り出す
+func decodeMessageID(customID string) (recruit.MessageID, error) {
+	items, err := decodeCustomID(customID)
+	if err != nil {
+		return "", err
+	}
+	messageID, ok := items[messageIDKey]
+	if !ok || messageID == "" {
+		return "", fmt.Errorf("message ID not found in custom ID: %s", customID)
+	}
+	return recruit.MessageID(messageID), nil
+}
+
+// RecruitMessageKey は操作対象の募集メッセージのIDを返す
+// 操作パネルやモーダルでは埋め込まれた募集メッセージのIDを、募集メッセージのボタンではそのメッセージのIDを使用する
+// 同じ募集への操作を受け付けた順に処理するためのキーとして使用する
+func RecruitMessageKey(interaction *discordgo.Interaction) string {
+	var customID string
+	switch interaction.Type {
+	case discordgo.InteractionMessageComponent:
+		customID = interaction.MessageComponentData().CustomID
+	case discordgo.InteractionModalSubmit:
+		customID = interaction.ModalSubmitData().CustomID
+	}
+	if customID != "" {
+		if messageID, err := decodeMessageID(customID); err == nil {
+			return string(messageID)
+		}
+	}
+	if interaction.Message != nil {
+		return interaction.Message.ID
+	}
+	return ""
+}
+
+// buildAuthorControlPanel は作成者向け操作パネルの本文とコンポーネントを返す
+// 締め切った募集は再開、抽選、チーム分け、削除のみ行える
+func buildAuthorControlPanel(view *recruit.RecruitView) (string, []discordgo.MessageComponent) {
+	messageID := string(view.Meta.MessageID)
+	encode := func(id interactionCustomID) string {
+		customID, _ := encodeCustomID(map[string]string{
+			customIDKey:  id.toString(),
+			messageIDKey: messageID,
+		})
+		return customID
+	}
+
+	deleteButton := discordgo.Button{
+		Label:    deleteLabel,
+		Style:    discordgo.DangerButton,
+		CustomID: encode(interactionDelete),
+	}
+	pickButton := discordgo.Button{
+		Label:    pickLabel,
+		Style:    discordgo.SecondaryButton,
+		CustomID: encode(interactionPick),
+	}
+	teamsButton := discordgo.Button{
+		Label:    teamsLabel,
+		Style:    discordgo.SecondaryButton,
+		CustomID: encode(interactionTeams),
+	}
+
+	if view.Meta.Status == recruit.RecruitStatusClosed {
+		message := "募集は締め切られています。\n募集の再開、参加者の抽選やチーム分け、削除を行う場合はボタンを押下してください。"
+		return message, []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{
+						Label:    reopenLabel,
+						Style:    discordgo.SuccessButton,
+						CustomID: encode(interactionReopen),
+					},
+					pickButton,
+					teamsButton,
+					deleteButton,
+				},
+			},
+		}
+	}
+
+	message := fmt.Sprintf(
+		"募集の管理 (現在の募集人数: @%d)\n人数変更、メンバーの除外、参加者の抽選やチーム分け、締め切り、削除を行えます。",
+		view.Meta.MaxCapacity,
+	)
+	minValues := 1
+	return message, []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    capacityLabel,
+					Style:    discordgo.PrimaryButton,
+					CustomID: encode(interactionCapacity),
+				},
+				discordgo.Button{
+					Label:    closeLabel,
+					Style:    discordgo.SecondaryButton,
+					CustomID: encode(interactionClose),
+				},
+				pickButton,
+				teamsButton,
+				deleteButton,
+			},
+		},
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					MenuType:    discordgo.UserSelectMenu,
+					CustomID:    encode(interactionKick),
+					Placeholder: "募集から外すメンバーを選択",
+					MinValues:   &minValues,
+					MaxValues:   1,
+				},
+			},
+		},
+	}
+}
+
+// sendAuthorControlPanel は作成者向けの操作パネルをDeferredメッセージに表示する
+func (command *customIDInteractionCommand) sendAuthorControlPanel(
+	ctx context.Context,
+	session discord.Client,
+	interaction *discordgo.Interaction,
+	service *recruit.RecruitUsecase,
+	messageID recruit.MessageID,
+) error {
+	channelID := recruit.ChannelID(interaction.ChannelID)
+	view, err := service.Get(ctx, channelID, messageID)
+	if err != nil {
+		return command.respondDomainError(session, interaction, err)
+	}
+
+	if view.Meta.AuthorID != recruit.UserID(interaction.Member.User.ID) {
+		return command.respondDomainError(session, interaction, recruit.ErrNotAuthor)
+	}
+
+	message, components := buildAuthorControlPanel(view)
+	command.editInteractionResponseWithComponent(session, interaction, message, &components)
+	return nil
+}
+
+// manageRecruitCommand は募集メッセージの管理ボタンから作成者向け操作パネルを開くコマンド
+type manageRecruitCommand struct {
+	customIDInteractionCommand
+	service *recruit.RecruitUsecase
+}
+
+func NewManageRecruitCommand(service *recruit.RecruitUsecase) *manageRecruitCommand {
+	return &manageRecruitCommand{
+		service: service,
+		customIDInteractionCommand: customIDInteractionCommand{
+			customID: interactionManage.toString(),
+		},
+	}
+}
+
+func (command *manageRecruitCommand) InteractionType() discordgo.InteractionType {
+	return discordgo.InteractionMessageComponent
+}
+
+func (command *manageRecruitCommand) Handle(ctx context.Context, session discord.Client, interaction *discordgo.Interaction) error {
+	log.Printf("[RECRUIT] user %s opened control panel", interaction.Member.User.ID)
+
+	err := session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	messageID := recruit.MessageID(interaction.Message.ID)
+	return command.sendAuthorControlPanel(ctx, session, interaction, command.service, messageID)
+}
+
+// capacityModalCommand は操作パネルの人数変更ボタンから入力モーダルを開くコマンド
+type capacityModalCommand struct {
+	customIDInteractionCommand
+}
+
+func NewCapacityModalCommand() *capacityModalCommand {
+	return &capacityModalCommand{
+		customIDInteractionCommand: customIDInteractionCommand{
+			customID: interactionCapacity.toString(),
+		},
+	}
+}
+
+func (command *capacityModalCommand) InteractionType() discordgo.InteractionType {
+	return discordgo.InteractionMessageComponent
+}
+
+func (command *capacityModalCommand) Handle(ctx context.Context, session discord.Client, interaction *discordgo.Interaction) error {
+	recruitMessageID, err := decodeMessageID(interaction.MessageComponentData().CustomID)
+	if err != nil {
+		return err
+	}
+
+	// モーダルの送信時に対象の募集を特定できるよう、募集メッセージIDを引き継ぐ
+	submitCustomID, err := encodeCustomID(map[string]string{
+		customIDKey:  interactionCapacitySubmit.toString(),
+		messageIDKey: string(recruitMessageID),
+	})
+	if err != nil {
+		return err
+	}
+
+	// モーダルはDeferredを挟まずに最初の応答として返す必要がある
+	return session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: submitCustomID,
+			Title:    "募集人数の変更",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    capacityInputID,
+							Label:       "募集人数(作成者を除く)",
+							Style:       discordgo.TextInputShort,
+							Placeholder: "例: 4",
+							Required:    true,
+							MinLength:   1,
+							MaxLength:   3,
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// changeCapacityCommand は人数変更モーダルの送信を受けて募集人数を変更するコマンド
+type changeCapacityCommand struct {
+	customIDInteractionCommand
+	service *recruit.RecruitUsecase
+}
+
+func NewChangeCapacityCommand(service *recruit.RecruitUsecase) *changeCapacityCommand {
+	return &changeCapacityCommand{
+		service: service,
+		customIDInteractionCommand: customIDInteractionCommand{
+			customID: interactionCapacitySubmit.toString(),
+		},
+	}
+}
+
+func (command *changeCapacityCommand) InteractionType() discordgo.InteractionType {
+	return discordgo.InteractionModalSubmit
+}
+
+func (command *changeCapacityCommand) Handle(ctx context.Context, session discord.Client, interaction *discordgo.Interaction) error {
+	log.Printf("[RECRUIT] user %s changed recruitment capacity", interaction.Member.User.ID)
+
+	// 操作パネル上のボタンから開いたモーダルのため、操作パネルを更新対象にする
+	err := session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	data := interaction.ModalSubmitData()
+	recruitMessageID, err := decodeMessageID(data.CustomID)
+	if err != nil {
+		return err
+	}
+
+	maxCapacity, err := strconv.Atoi(strings.TrimSpace(modalTextValue(data, capacityInputID)))
+	if err != nil {
+		command.editInteractionResponse(session, interaction, "❗募集人数は数値で入力してください。")
+		return nil
+	}
+
+	actorID := recruit.UserID(interaction.Member.User.ID)
+	channelID := recruit.ChannelID(interaction.ChannelID)
+
+	result, err := command.service.ChangeCapacity(ctx, channelID, recruitMessageID, actorID, maxCapacity)
+	if err != nil {
+		return command.respondDomainError(session, interaction, err)
+	}
+
+	if err := updateRecruitMessage(ctx, session, command.service, result.CurrentView); err != nil {
+		return err
+	}
+	_ = session.InteractionResponseDelete(interaction)
+
+	return replyRecruitMessage(session, result.CurrentView, createCapacityChangeMessage(result))
+}
+
+// modalTextValue はモーダルの送信内容から指定した入力欄の値を取り出す
+func modalTextValue(data discordgo.ModalSubmitInteractionData, inputID string) string {
+	for _, component := range data.Components {
+		row, ok := component.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, c := range row.Components {
+			if input, ok := c.(*discordgo.TextInput); ok && input.CustomID == inputID {
+				return input.Value
+			}
+		}
+	}
+	return ""
+}
+
+// kickParticipantCommand は操作パネルのユーザー選択から参加者を募集から外すコマンド
+type kickParticipantCommand struct {
+	customIDInteractionCommand
+	service *recruit.RecruitUsecase
+}
+
+func NewKickParticipantCommand(service *recruit.RecruitUsecase) *kickParticipantCommand {
+	return &kickParticipantCommand{
+		service: service,
+		customIDInteractionCommand: customIDInteractionCommand{
+			customID: interactionKick.toString(),
+		},
+	}
+}
+
+func (command *kickParticipantCommand) InteractionType() discordgo.InteractionType {
+	return discordgo.InteractionMessageComponent
+}
+
+func (command *kickParticipantCommand) Handle(ctx context.Context, session discord.Client, interaction *discordgo.Interaction) error {
+	log.Printf("[RECRUIT] user %s kicked participant", interaction.Member.User.ID)
+
+	err := session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	data := interaction.MessageComponentData()
+	recruitMessageID, err := decodeMessageID(data.CustomID)
+	if err != nil {
+		return err
+	}
+	if len(data.Values) == 0 {
+		return fmt.Errorf("no user selected")
+	}
+
+	actorID := recruit.UserID(interaction.Member.User.ID)
+	targetID := recruit.UserID(data.Values[0])
+	channelID := recruit.ChannelID(interaction.ChannelID)
+
+	result, err := command.service.Kick(ctx, channelID, recruitMessageID, actorID, targetID)
+	if err != nil {
+		return command.respondDomainError(session, interaction, err)
+	}
+
+	if err := updateRecruitMessage(ctx, session, command.service, result.CurrentView); err != nil {
+		return err
+	}
+	_ = session.InteractionResponseDelete(interaction)
+
+	// 参加者を外した場合のみ通知
+	if *result.PreviousStatus != recruit.ParticipantStatusJoined {
+		return nil
+	}
+	return replyRecruitMessage(session, result.CurrentView, createKickMessage(targetID, result))
+}
+
+// deleteRecruitCommand は操作パネルから募集と募集メッセージを削除するコマンド
+type deleteRecruitCommand struct {
+	customIDInteractionCommand
+	service *recruit.RecruitUsecase
+}
+
+func NewDeleteRecruitCommand(service *recruit.RecruitUsecase) *deleteRecruitCommand {
+	return &deleteRecruitCommand{
+		service: service,
+		customIDInteractionCommand: customIDInteractionCommand{
+			customID: interactionDelete.toString(),
+		},
+	}
+}
+
+func (command *deleteRecruitCommand) InteractionType() discordgo.InteractionType {
+	return discordgo.InteractionMessageComponent
+}
+
+func (command *deleteRecruitCommand) Handle(ctx context.Context, session discord.Client, interaction *discordgo.Interaction) error {
+	log.Printf("[RECRUIT] user %s deleted recruitment", interaction.Member.User.ID)
+
+	err := session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	recruitMessageID, err := decodeMessageID(interaction.MessageComponentData().CustomID)
+	if err != nil {
+		return err
+	}
+
+	actorID := recruit.UserID(interaction.Member.User.ID)
+	channelID := recruit.ChannelID(interaction.ChannelID)
+
+	err = command.service.Delete(ctx, channelID, recruitMessageID, actorID)
+	if err != nil {
+		return command.respondDomainError(session, interaction, err)
+	}
+
+	// 募集メッセージと操作パネルを削除
+	err = session.ChannelMessageDelete(interaction.ChannelID, string(recruitMessageID))
+	_ = session.InteractionResponseDelete(interaction)
+
+	return err
+}
+
+func createCapacityChangeMessage(result *recruit.CapacityChangeResult) string {
+	view := result.CurrentView
+	var b strings.Builder
+	fmt.Fprintf(&b, "募集人数が%d人に変更されました。", view.Meta.MaxCapacity)
+	writePromotedUsers(&b, result.PromotedUsers)
+	writeRemainingSlots(&b, view)
+	return b.String()
+}
+
+func createKickMessage(targetID recruit.UserID, result *recruit.ParticipantStatusChangeResult) string {
+	view := result.CurrentView
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s が募集から外されました。", discord.FormatMention(string(targetID)))
+	writePromotedUsers(&b, result.PromotedUsers)
+	writeRemainingSlots(&b, view)
+	return b.String()
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"at-bot/internal/recruit"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestDecodeMessageID(t *testing.T) {
+	tests := []struct {
+		name     string
+		customID string
+		want     recruit.MessageID
+		wantErr  bool
+	}{
+		{
+			name:     "メッセージIDが埋め込まれている場合",
+			customID: "8:customID14:recruit/delete9:messageID3:123",
+			want:     "123",
+		},
+		{
+			name:     "メッセージIDが埋め込まれていない場合",
+			customID: "8:customID14:recruit/manage",
+			wantErr:  true,
+		},
+		{
+			name:     "不正な形式の場合",
+			customID: "invalid",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeMessageID(tt.customID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeMessageID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("decodeMessageID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecruitMessageKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		interaction *discordgo.Interaction
+		want        string
+	}{
+		{
+			name: "募集メッセージのボタン",
+			interaction: &discordgo.Interaction{
+				Type:    discordgo.InteractionMessageComponent,
+				Message: &discordgo.Message{ID: "123"},
+				Data:    discordgo.MessageComponentInteractionData{CustomID: "recruit/join"},
+			},
+			want: "123",
+		},
+		{
+			name: "操作パネルのボタン",
+			interaction: &discordgo.Interaction{
+				Type:    discordgo.InteractionMessageComponent,
+				Message: &discordgo.Message{ID: "panel"},
+				Data:    discordgo.MessageComponentInteractionData{CustomID: "8:customID14:recruit/delete9:messageID3:123"},
+			},
+			want: "123",
+		},
+		{
+			name: "モーダルの送信",
+			interaction: &discordgo.Interaction{
+				Type: discordgo.InteractionModalSubmit,
+				Data: discordgo.ModalSubmitInteractionData{CustomID: "8:customID16:recruit/capacity9:messageID3:123"},
+			},
+			want: "123",
+		},
+		{
+			name: "スラッシュコマンド",
+			interaction: &discordgo.Interaction{
+				Type: discordgo.InteractionApplicationCommand,
+				Data: discordgo.ApplicationCommandInteractionData{Name: "recruit"},
+			},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RecruitMessageKey(tt.interaction); got != tt.want {
+				t.Errorf("RecruitMessageKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildAuthorControlPanel(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     recruit.RecruitStatus
+		wantRows   int
+		wantCustom []interactionCustomID
+	}{
+		{
+			name:       "募集中は人数変更、締め切り、抽選、チーム分け、削除、メンバー除外を表示",
+			status:     recruit.RecruitStatusOpened,
+			wantRows:   2,
+			wantCustom: []interactionCustomID{interactionCapacity, interactionClose, interactionPick, interactionTeams, interactionDelete},
+		},
+		{
+			name:       "締め切り済みは再開、抽選、チーム分け、削除のみ表示",
+			status:     recruit.RecruitStatusClosed,
+			wantRows:   1,
+			wantCustom: []interactionCustomID{interactionReopen, interactionPick, interactionTeams, interactionDelete},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := &recruit.RecruitView{
+				Meta: &recruit.RecruitState{
+					MessageID:   "message-1",
+					MaxCapacity: 3,
+					Status:      tt.status,
+				},
+			}
+
+			_, components := buildAuthorControlPanel(view)
+			if len(components) != tt.wantRows {
+				t.Fatalf("rows = %v, want %v", len(components), tt.wantRows)
+			}
+
+			buttons := components[0].(discordgo.ActionsRow).Components
+			if len(buttons) != len(tt.wantCustom) {
+				t.Fatalf("buttons = %v, want %v", len(buttons), len(tt.wantCustom))
+			}
+			for i, want := range tt.wantCustom {
+				items, err := decodeCustomID(buttons[i].(discordgo.Button).CustomID)
+				if err != nil {
+					t.Fatalf("decodeCustomID() error = %v", err)
+				}
+				if items[customIDKey] != want.toString() {
+					t.Errorf("buttons[%d] customID = %v, want %v", i, items[customIDKey], want)
+				}
+				if items[messageIDKey] != "message-1" {
+					t.Errorf("buttons[%d] messageID = %v, want message-1", i, items[messageIDKey])
+				}
+			}
+		})
+	}
+}
+
+func TestModalTextValue(t *testing.T) {
+	data := discordgo.ModalSubmitInteractionData{
+		Components: []discordgo.MessageComponent{
+			&discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					&discordgo.TextInput{CustomID: capacityInputID, Value: "4"},
+				},
+			},
+		},
+	}
+
+	if got := modalTextValue(data, capacityInputID); got != "4" {
+		t.Errorf("modalTextValue() = %v, want 4", got)
+	}
+	if got := modalTextValue(data, "unknown"); got != "" {
+		t.Errorf("modalTextValue() = %v, want empty", got)
+	}
+}
+
+func TestCreateCapacityChangeMessage(t *testing.T) {
+	result := &recruit.CapacityChangeResult{
+		CurrentView: &recruit.RecruitView{
+			Meta:        &recruit.RecruitState{MaxCapacity: 3},
+			JoinedUsers: []recruit.UserID{"author", "user1", "user2"},
+		},
+		PromotedUsers: []recruit.UserID{"user2"},
+	}
+
+	want := "募集人数が3人に変更されました。\n<@user2> がキャンセル待ちから繰り上げ参加になりました。 @1"
+	if got := createCapacityChangeMessage(result); got != want {
+		t.Errorf("createCapacityChangeMessage() = %v, want %v", got, want)
+	}
+}
+
+func TestCreateKickMessage(t *testing.T) {
+	result := &recruit.ParticipantStatusChangeResult{
+		CurrentView: &recruit.RecruitView{
+			Meta:        &recruit.RecruitState{MaxCapacity: 2},
+			JoinedUsers: []recruit.UserID{"author"},
+		},
+	}
+
+	want := "<@user1> が募集から外されました。 @2"
+	if got := createKickMessage("user1", result); got != want {
+		t.Errorf("createKickMessage() = %v, want %v", got, want)
+	}
+}
@@ -1,10 +1,22 @@
 package handler
 
 import (
+	"at-bot/internal/db/inmemory"
+	"at-bot/internal/discord"
+	"at-bot/internal/discord/discordtest"
 	"at-bot/internal/recruit"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -212,8 +224,8 @@ func TestRecruitState_ToEmbed(t *testing.T) {
 		t.Errorf("Description should contain mention of author")
 	}
 
-	if len(embed.Fields) != 2 {
-		t.Errorf("Fields length = %v, want 2", len(embed.Fields))
+	if len(embed.Fields) != 3 {
+		t.Fatalf("Fields length = %v, want 3", len(embed.Fields))
 	}
 
 	if embed.Fields[0].Name != joinLabel {
@@ -224,11 +236,293 @@ func TestRecruitState_ToEmbed(t *testing.T) {
 		t.Errorf("Fields[1].Name = %v, want %v", embed.Fields[1].Name, declineLabel)
 	}
 
+	if embed.Fields[2].Name != tentativeLabel {
+		t.Errorf("Fields[2].Name = %v, want %v", embed.Fields[2].Name, tentativeLabel)
+	}
+
 	if embed.Color != 0xffa500 {
 		t.Errorf("Color = %v, want 0xffa500", embed.Color)
 	}
 }
 
+func TestRecruitState_ToEmbed_WithTitle(t *testing.T) {
+	state := &recruitState{
+		maxCapacity: 3,
+		author:      "author-id",
+		title:       "Apex ランク",
+		description: "ゴールド帯\nVC必須",
+		joinUsers:   []recruit.UserID{"author-id"},
+	}
+
+	embed := state.toEmbed()
+
+	if embed.Title != "📢 Apex ランク @3" {
+		t.Errorf("Title = %v, want '📢 Apex ランク @3'", embed.Title)
+	}
+
+	want := "<@author-id> が募集を始めました\n\nゴールド帯\nVC必須"
+	if embed.Description != want {
+		t.Errorf("Description = %v, want %v", embed.Description, want)
+	}
+}
+
+func TestRecruitState_ToEmbed_WithRole(t *testing.T) {
+	startAt := time.Unix(1700000000, 0)
+	state := &recruitState{
+		maxCapacity:    3,
+		author:         "author-id",
+		startAt:        &startAt,
+		roleID:         "role-1",
+		roleRestricted: true,
+		joinUsers:      []recruit.UserID{"author-id"},
+	}
+
+	embed := state.toEmbed()
+
+	if len(embed.Fields) != 5 {
+		t.Fatalf("Fields length = %v, want 5", len(embed.Fields))
+	}
+	// 開始時刻、対象ロールの順で参加者欄より先に表示する
+	if embed.Fields[0].Name != startAtLabel {
+		t.Errorf("Fields[0].Name = %v, want %v", embed.Fields[0].Name, startAtLabel)
+	}
+	if embed.Fields[1].Name != roleLabel {
+		t.Errorf("Fields[1].Name = %v, want %v", embed.Fields[1].Name, roleLabel)
+	}
+	if embed.Fields[1].Value != "<@&role-1> (ロール保持者のみ参加可)" {
+		t.Errorf("Fields[1].Value = %v, want '<@&role-1> (ロール保持者のみ参加可)'", embed.Fields[1].Value)
+	}
+}
+
+func TestCreateLeaveMessage_WithRole(t *testing.T) {
+	tests := []struct {
+		name        string
+		joinedUsers []recruit.UserID
+		want        string
+	}{
+		{
+			name:        "空きがある場合は対象ロールにメンションする",
+			joinedUsers: []recruit.UserID{"author"},
+			want:        "<@user1> が参加を取り消しました。 @1\n<@&role-1>",
+		},
+		{
+			name:        "繰り上げで埋まった場合はメンションしない",
+			joinedUsers: []recruit.UserID{"author", "user2"},
+			want:        "<@user1> が参加を取り消しました。 @0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := &recruit.RecruitView{
+				Meta:        &recruit.RecruitState{MaxCapacity: 1, RoleID: "role-1"},
+				JoinedUsers: tt.joinedUsers,
+			}
+			if got := createLeaveMessage("user1", view, nil); got != tt.want {
+				t.Errorf("createLeaveMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateOpenMessage(t *testing.T) {
+	view := &recruit.RecruitView{
+		Meta:        &recruit.RecruitState{MaxCapacity: 4, RoleID: "role-1"},
+		JoinedUsers: []recruit.UserID{"author"},
+	}
+
+	want := "<@&role-1> 募集が開始されました。 @4"
+	if got := createOpenMessage(view); got != want {
+		t.Errorf("createOpenMessage() = %v, want %v", got, want)
+	}
+}
+
+func TestDecodeOpenRecruitParams(t *testing.T) {
+	startAt := time.Unix(1700000000, 0)
+	tests := []struct {
+		name   string
+		params openRecruitParams
+	}{
+		{
+			name:   "開始時刻なし",
+			params: openRecruitParams{maxCapacity: 4},
+		},
+		{
+			name:   "開始時刻あり",
+			params: openRecruitParams{maxCapacity: 10, startAt: &startAt},
+		},
+		{
+			name: "全項目あり",
+			params: openRecruitParams{
+				maxCapacity:    100,
+				startAt:        &startAt,
+				roleID:         "1234567890123456789",
+				roleRestricted: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := map[string]string{
+				customIDKey: interactionOpenSubmit.toString(),
+				capacityKey: fmt.Sprint(tt.params.maxCapacity),
+			}
+			if tt.params.startAt != nil {
+				items[startAtKey] = fmt.Sprint(tt.params.startAt.Unix())
+			}
+			if tt.params.roleID != "" {
+				items[roleKey] = string(tt.params.roleID)
+			}
+			if tt.params.roleRestricted {
+				items[roleOnlyKey] = "1"
+			}
+			// customIDの上限100文字に収まること
+			customID, err := encodeCustomID(items)
+			if err != nil {
+				t.Fatalf("encodeCustomID() error = %v", err)
+			}
+
+			got, err := decodeOpenRecruitParams(customID)
+			if err != nil {
+				t.Fatalf("decodeOpenRecruitParams() error = %v", err)
+			}
+			if got.maxCapacity != tt.params.maxCapacity {
+				t.Errorf("maxCapacity = %v, want %v", got.maxCapacity, tt.params.maxCapacity)
+			}
+			if (got.startAt == nil) != (tt.params.startAt == nil) ||
+				(got.startAt != nil && !got.startAt.Equal(*tt.params.startAt)) {
+				t.Errorf("startAt = %v, want %v", got.startAt, tt.params.startAt)
+			}
+			if got.roleID != tt.params.roleID || got.roleRestricted != tt.params.roleRestricted {
+				t.Errorf("role = %v/%v, want %v/%v", got.roleID, got.roleRestricted, tt.params.roleID, tt.params.roleRestricted)
+			}
+		})
+	}
+}
+
+func TestRecruitState_ToEmbed_WithStartAt(t *testing.T) {
+	startAt := time.Unix(1700000000, 0)
+	state := &recruitState{
+		maxCapacity:  5,
+		author:       "author-id",
+		status:       recruit.RecruitStatusStarted,
+		startAt:      &startAt,
+		joinUsers:    []recruit.UserID{"author-id"},
+		declineUsers: []recruit.UserID{},
+	}
+
+	embed := state.toEmbed()
+
+	if len(embed.Fields) != 4 {
+		t.Fatalf("Fields length = %v, want 4", len(embed.Fields))
+	}
+
+	if embed.Fields[0].Name != startAtLabel {
+		t.Errorf("Fields[0].Name = %v, want %v", embed.Fields[0].Name, startAtLabel)
+	}
+
+	if !strings.Contains(embed.Fields[0].Value, "<t:1700000000:f>") {
+		t.Errorf("Fields[0].Value = %v, should contain start timestamp", embed.Fields[0].Value)
+	}
+
+	if embed.Footer == nil || embed.Footer.Text != startedLabel {
+		t.Errorf("Footer = %v, want %v", embed.Footer, startedLabel)
+	}
+}
+
+func TestRecruitState_ToEmbed_Expired(t *testing.T) {
+	state := &recruitState{
+		maxCapacity:  5,
+		author:       "author-id",
+		status:       recruit.RecruitStatusClosed,
+		closeReason:  recruit.CloseReasonExpired,
+		joinUsers:    []recruit.UserID{"author-id"},
+		declineUsers: []recruit.UserID{},
+	}
+
+	embed := state.toEmbed()
+
+	if embed.Footer == nil || embed.Footer.Text != expiredLabel {
+		t.Errorf("Footer = %v, want %v", embed.Footer, expiredLabel)
+	}
+
+	if embed.Color != closedColor {
+		t.Errorf("Color = %v, want %v", embed.Color, closedColor)
+	}
+
+	if len(state.toComponents()) != 0 {
+		t.Errorf("toComponents() length = %v, want 0", len(state.toComponents()))
+	}
+}
+
+func TestRecruitState_ToEmbed_Waitlist(t *testing.T) {
+	state := &recruitState{
+		maxCapacity:   1,
+		author:        "author-id",
+		joinUsers:     []recruit.UserID{"author-id", "user1"},
+		declineUsers:  []recruit.UserID{},
+		waitlistUsers: []recruit.UserID{"user2", "user3"},
+	}
+
+	embed := state.toEmbed()
+
+	if len(embed.Fields) != 4 {
+		t.Fatalf("Fields length = %v, want 4", len(embed.Fields))
+	}
+
+	if embed.Fields[3].Name != waitlistLabel {
+		t.Errorf("Fields[3].Name = %v, want %v", embed.Fields[3].Name, waitlistLabel)
+	}
+
+	want := "1. <@user2>\n2. <@user3>"
+	if embed.Fields[3].Value != want {
+		t.Errorf("Fields[3].Value = %v, want %v", embed.Fields[3].Value, want)
+	}
+}
+
+func TestRecruitState_ToEmbed_Closed(t *testing.T) {
+	state := &recruitState{
+		maxCapacity:  5,
+		author:       "author-id",
+		status:       recruit.RecruitStatusClosed,
+		closeReason:  recruit.CloseReasonManual,
+		joinUsers:    []recruit.UserID{"author-id"},
+		declineUsers: []recruit.UserID{},
+	}
+
+	embed := state.toEmbed()
+
+	if embed.Footer == nil || embed.Footer.Text != closedLabel {
+		t.Errorf("Footer = %v, want %v", embed.Footer, closedLabel)
+	}
+
+	// 作成者が再開できるようボタンは残す
+	if len(state.toComponents()) != 1 {
+		t.Errorf("toComponents() length = %v, want 1", len(state.toComponents()))
+	}
+}
+
+func TestDomainErrorMessage(t *testing.T) {
+	got, ok := domainErrorMessage(fmt.Errorf("wrapped: %w", recruit.ErrRecruitClosed))
+	if !ok {
+		t.Fatal("domainErrorMessage() ok = false, want true")
+	}
+	if got != "❗募集は締め切られています。" {
+		t.Errorf("domainErrorMessage() = %v, want ❗募集は締め切られています。", got)
+	}
+
+	// 削除された募集の操作では見つからないことを表示する
+	got, ok = domainErrorMessage(fmt.Errorf("recruit: %w", recruit.ErrRecruitNotFound))
+	if !ok || got != "❗募集が見つかりません。" {
+		t.Errorf("domainErrorMessage() = (%v, %v), want ❗募集が見つかりません。", got, ok)
+	}
+
+	if _, ok := domainErrorMessage(errors.New("database error")); ok {
+		t.Error("domainErrorMessage() ok = true for unknown error, want false")
+	}
+}
+
 func TestRecruitState_ToComponent(t *testing.T) {
 	state := &recruitState{
 		maxCapacity: 5,
@@ -237,8 +531,28 @@ func TestRecruitState_ToComponent(t *testing.T) {
 
 	component := state.toComponent()
 
-	if len(component.Components) != 2 {
-		t.Errorf("Components length = %v, want 2", len(component.Components))
+	if len(component.Components) != 4 {
+		t.Errorf("Components length = %v, want 4", len(component.Components))
+	}
+}
+
+func TestRecruitState_ToComponent_Closed(t *testing.T) {
+	state := &recruitState{
+		maxCapacity: 5,
+		author:      "author-id",
+		status:      recruit.RecruitStatusClosed,
+		closeReason: recruit.CloseReasonManual,
+	}
+
+	component := state.toComponent()
+
+	// 参加系のボタンは無効化し、管理ボタンのみ押下できる
+	for _, c := range component.Components {
+		button := c.(discordgo.Button)
+		wantDisabled := button.Label != manageLabel
+		if button.Disabled != wantDisabled {
+			t.Errorf("%s Disabled = %v, want %v", button.Label, button.Disabled, wantDisabled)
+		}
 	}
 }
 
@@ -282,8 +596,9 @@ func TestFromRecruitView(t *testing.T) {
 			MaxCapacity: 5,
 			AuthorID:    "author-id",
 		},
-		JoinedUsers:   []recruit.UserID{"author-id", "user1"},
-		DeclinedUsers: []recruit.UserID{"user2"},
+		JoinedUsers:    []recruit.UserID{"author-id", "user1"},
+		DeclinedUsers:  []recruit.UserID{"user2"},
+		TentativeUsers: []recruit.UserID{"user3"},
 	}
 
 	state := fromRecruitView(view)
@@ -303,6 +618,10 @@ func TestFromRecruitView(t *testing.T) {
 	if len(state.declineUsers) != 1 {
 		t.Errorf("declineUsers length = %v, want 1", len(state.declineUsers))
 	}
+
+	if len(state.tentativeUsers) != 1 {
+		t.Errorf("tentativeUsers length = %v, want 1", len(state.tentativeUsers))
+	}
 }
 
 func TestInitState(t *testing.T) {
@@ -341,8 +660,8 @@ func TestOpenRecruitSlashCommand_CreateCommand(t *testing.T) {
 		t.Errorf("CreateCommand().Description is empty")
 	}
 
-	if len(command.Options) != 1 {
-		t.Errorf("CreateCommand().Options length = %v, want 1", len(command.Options))
+	if len(command.Options) != 7 {
+		t.Errorf("CreateCommand().Options length = %v, want 7", len(command.Options))
 		return
 	}
 
@@ -362,6 +681,37 @@ func TestOpenRecruitSlashCommand_CreateCommand(t *testing.T) {
 	if opt.MinValue == nil || *opt.MinValue != 1.0 {
 		t.Errorf("CreateCommand().Options[0].MinValue = %v, want 1.0", opt.MinValue)
 	}
+
+	startAtOpt := command.Options[1]
+	if startAtOpt.Name != recruitStartAtArgName {
+		t.Errorf("CreateCommand().Options[1].Name = %v, want %v", startAtOpt.Name, recruitStartAtArgName)
+	}
+
+	if startAtOpt.Type != discordgo.ApplicationCommandOptionString {
+		t.Errorf("CreateCommand().Options[1].Type = %v, want ApplicationCommandOptionString", startAtOpt.Type)
+	}
+
+	if startAtOpt.Required {
+		t.Errorf("CreateCommand().Options[1].Required = true, want false")
+	}
+
+	// タイトル以降は任意項目
+	wantNames := []string{
+		recruitTitleArgName,
+		recruitContentArgName,
+		recruitRoleArgName,
+		recruitRoleOnlyArgName,
+		recruitDetailArgName,
+	}
+	for i, name := range wantNames {
+		opt := command.Options[i+2]
+		if opt.Name != name {
+			t.Errorf("CreateCommand().Options[%d].Name = %v, want %v", i+2, opt.Name, name)
+		}
+		if opt.Required {
+			t.Errorf("CreateCommand().Options[%d].Required = true, want false", i+2)
+		}
+	}
 }
 
 func TestCreateJoinMessage(t *testing.T) {
@@ -477,3 +827,569 @@ func TestBaseInteractionCommand_MatchCustomID(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateWaitlistMessage(t *testing.T) {
+	view := &recruit.RecruitView{
+		Meta:            &recruit.RecruitState{MaxCapacity: 1},
+		JoinedUsers:     []recruit.UserID{"author", "user-1"},
+		WaitlistedUsers: []recruit.UserID{"user-2", "user-3"},
+	}
+
+	got := createWaitlistMessage("user-3", view)
+	want := "<@user-3> がキャンセル待ちに登録しました。(2番目)"
+	if got != want {
+		t.Errorf("createWaitlistMessage() = %v, want %v", got, want)
+	}
+}
+
+func TestCreateLeaveMessage(t *testing.T) {
+	tests := []struct {
+		name          string
+		joinedUsers   []recruit.UserID
+		promotedUsers []recruit.UserID
+		want          string
+	}{
+		{
+			name:        "繰り上げなし",
+			joinedUsers: []recruit.UserID{"author"},
+			want:        "<@user-1> が参加を取り消しました。 @1",
+		},
+		{
+			name:          "キャンセル待ちから繰り上げ",
+			joinedUsers:   []recruit.UserID{"author", "user-2"},
+			promotedUsers: []recruit.UserID{"user-2"},
+			want:          "<@user-1> が参加を取り消しました。\n<@user-2> がキャンセル待ちから繰り上げ参加になりました。 @0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := &recruit.RecruitView{
+				Meta:        &recruit.RecruitState{MaxCapacity: 1},
+				JoinedUsers: tt.joinedUsers,
+			}
+
+			got := createLeaveMessage("user-1", view, tt.promotedUsers)
+			if got != tt.want {
+				t.Errorf("createLeaveMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// messageEditRecorder はDiscordへのメッセージ編集のリクエストを受け付けた順に記録する
+type messageEditRecorder struct {
+	mu    sync.Mutex
+	edits []string
+}
+
+func (r *messageEditRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	// 通信の遅延で編集の順序が入れ替わるよう遅延させる
+	time.Sleep(time.Duration(rand.IntN(3)) * time.Millisecond)
+	if req.Method == http.MethodPatch {
+		var edit struct {
+			Embeds []*discordgo.MessageEmbed `json:"embeds"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&edit); err != nil {
+			return nil, err
+		}
+		var text strings.Builder
+		for _, embed := range edit.Embeds {
+			text.WriteString(embed.Description)
+			for _, field := range embed.Fields {
+				text.WriteString(field.Value)
+			}
+		}
+		r.mu.Lock()
+		r.edits = append(r.edits, text.String())
+		r.mu.Unlock()
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(`{"id":"message-1","channel_id":"channel-1"}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestUpdateRecruitMessage_ConcurrentJoin(t *testing.T) {
+	const users = 50
+
+	store := inmemory.NewStore()
+	service := recruit.NewRecruitUsecase(
+		inmemory.NewRecruitRepository(store),
+		inmemory.NewParticipantRepository(store),
+		inmemory.NewTxManager(store),
+	)
+	ctx := context.Background()
+	if _, err := service.Open(ctx, "guild-1", "channel-1", "message-1", users, "author-1"); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	recorder := &messageEditRecorder{}
+	session, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	session.Client = &http.Client{Transport: recorder}
+
+	var wg sync.WaitGroup
+	for i := 0; i < users; i++ {
+		wg.Add(1)
+		go func(userID recruit.UserID) {
+			defer wg.Done()
+			result, err := service.Join(ctx, "channel-1", "message-1", userID, nil)
+			if err != nil {
+				t.Errorf("Join(%s) error = %v", userID, err)
+				return
+			}
+			// 参加の確定から再描画までに他の処理が入り、後から参加したユーザーが先に再描画する場合を再現する
+			time.Sleep(time.Duration(rand.IntN(3)) * time.Millisecond)
+			if err := updateRecruitMessage(ctx, session, service, result.CurrentView); err != nil {
+				t.Errorf("updateRecruitMessage() error = %v", err)
+			}
+		}(recruit.UserID(fmt.Sprintf("user-%d", i)))
+	}
+	wg.Wait()
+
+	if len(recorder.edits) != users {
+		t.Fatalf("edits = %d, want %d", len(recorder.edits), users)
+	}
+	// 最後の編集は全員の参加を反映している
+	last := recorder.edits[len(recorder.edits)-1]
+	for i := 0; i < users; i++ {
+		if mention := fmt.Sprintf("<@user-%d>", i); !strings.Contains(last, mention) {
+			t.Errorf("last edit does not contain %s", mention)
+		}
+	}
+}
+
+// newTestRecruitService は author-1 が channel-1 に募集(message-1)を開いた状態のサービスを作成する
+func newTestRecruitService(t *testing.T, capacity int) *recruit.RecruitUsecase {
+	t.Helper()
+	store := inmemory.NewStore()
+	service := recruit.NewRecruitUsecase(
+		inmemory.NewRecruitRepository(store),
+		inmemory.NewParticipantRepository(store),
+		inmemory.NewTxManager(store),
+	)
+	if _, err := service.Open(context.Background(), "guild-1", "channel-1", "message-1", capacity, "author-1"); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	return service
+}
+
+// newButtonInteraction はmessageIDのメッセージのボタンをuserIDが押下したインタラクションを作成する
+func newButtonInteraction(userID, messageID, customID string) *discordgo.Interaction {
+	return &discordgo.Interaction{
+		ID:        "interaction-1",
+		Type:      discordgo.InteractionMessageComponent,
+		GuildID:   "guild-1",
+		ChannelID: "channel-1",
+		Member:    &discordgo.Member{User: &discordgo.User{ID: userID}},
+		Message:   &discordgo.Message{ID: messageID},
+		Data:      discordgo.MessageComponentInteractionData{CustomID: customID},
+	}
+}
+
+// panelCustomID は操作パネルやキャンセルボタンのcustomIDを作成する
+func panelCustomID(t *testing.T, id interactionCustomID) string {
+	t.Helper()
+	customID, err := encodeCustomID(map[string]string{
+		customIDKey:  id.toString(),
+		messageIDKey: "message-1",
+	})
+	if err != nil {
+		t.Fatalf("encodeCustomID() error = %v", err)
+	}
+	return customID
+}
+
+func respondCall(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse) discordtest.Call {
+	return discordtest.Call{Method: "InteractionRespond", Args: []any{interaction, resp}}
+}
+
+func responseEditCall(interaction *discordgo.Interaction, content string, components []discordgo.MessageComponent) discordtest.Call {
+	return discordtest.Call{Method: "InteractionResponseEdit", Args: []any{interaction, &discordgo.WebhookEdit{
+		Content:    &content,
+		Components: &components,
+	}}}
+}
+
+func responseDeleteCall(interaction *discordgo.Interaction) discordtest.Call {
+	return discordtest.Call{Method: "InteractionResponseDelete", Args: []any{interaction}}
+}
+
+// recruitMessageEditCall は募集メッセージを現在の状態で再描画する呼び出しを返す
+func recruitMessageEditCall(t *testing.T, service *recruit.RecruitUsecase) discordtest.Call {
+	t.Helper()
+	view, err := service.Get(context.Background(), "channel-1", "message-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	state := fromRecruitView(view)
+	components := state.toComponents()
+	return discordtest.Call{Method: "ChannelMessageEditComplex", Args: []any{&discordgo.MessageEdit{
+		Channel:    "channel-1",
+		ID:         "message-1",
+		Embeds:     &[]*discordgo.MessageEmbed{state.toEmbed()},
+		Components: &components,
+	}}}
+}
+
+// replyCall は募集メッセージへ返信する呼び出しを返す
+func replyCall(content string) discordtest.Call {
+	return discordtest.Call{Method: "ChannelMessageSendComplex", Args: []any{"channel-1", &discordgo.MessageSend{
+		Content:   content,
+		Reference: &discordgo.MessageReference{MessageID: "message-1"},
+	}}}
+}
+
+// assertCalls はDiscord APIの呼び出しの順序と内容を検証する
+func assertCalls(t *testing.T, got, want []discordtest.Call) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("calls = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("calls[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParticipantActionCommand_Handle(t *testing.T) {
+	deferredEphemeral := func(responseType discordgo.InteractionResponseType) *discordgo.InteractionResponse {
+		return &discordgo.InteractionResponse{
+			Type: responseType,
+			Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+		}
+	}
+	closedMessage, _ := domainErrorMessage(recruit.ErrRecruitClosed)
+
+	tests := []struct {
+		name     string
+		capacity int
+		// setup は操作の前に行う募集の操作
+		setup       func(ctx context.Context, service *recruit.RecruitUsecase) error
+		command     func(service *recruit.RecruitUsecase) discord.InteractionListener
+		interaction func(t *testing.T) *discordgo.Interaction
+		want        func(t *testing.T, service *recruit.RecruitUsecase, interaction *discordgo.Interaction) []discordtest.Call
+	}{
+		{
+			name:     "参加すると募集メッセージを更新して参加を通知する",
+			capacity: 2,
+			command: func(service *recruit.RecruitUsecase) discord.InteractionListener {
+				return NewJoinRecruitCommand(service)
+			},
+			interaction: func(*testing.T) *discordgo.Interaction {
+				return newButtonInteraction("user-1", "message-1", interactionJoin.toString())
+			},
+			want: func(t *testing.T, service *recruit.RecruitUsecase, interaction *discordgo.Interaction) []discordtest.Call {
+				return []discordtest.Call{
+					respondCall(interaction, deferredEphemeral(discordgo.InteractionResponseDeferredChannelMessageWithSource)),
+					recruitMessageEditCall(t, service),
+					responseDeleteCall(interaction),
+					replyCall("<@user-1> が参加しました。 @1"),
+				}
+			},
+		},
+		{
+			name:     "満員の募集に参加するとキャンセル待ちを通知する",
+			capacity: 1,
+			setup: func(ctx context.Context, service *recruit.RecruitUsecase) error {
+				// 作成者とuser-2で満員になる
+				_, err := service.Join(ctx, "channel-1", "message-1", "user-2", nil)
+				return err
+			},
+			command: func(service *recruit.RecruitUsecase) discord.InteractionListener {
+				return NewJoinRecruitCommand(service)
+			},
+			interaction: func(*testing.T) *discordgo.Interaction {
+				return newButtonInteraction("user-1", "message-1", interactionJoin.toString())
+			},
+			want: func(t *testing.T, service *recruit.RecruitUsecase, interaction *discordgo.Interaction) []discordtest.Call {
+				return []discordtest.Call{
+					respondCall(interaction, deferredEphemeral(discordgo.InteractionResponseDeferredChannelMessageWithSource)),
+					recruitMessageEditCall(t, service),
+					responseDeleteCall(interaction),
+					replyCall("<@user-1> がキャンセル待ちに登録しました。(1番目)"),
+				}
+			},
+		},
+		{
+			name:     "参加済みの場合はキャンセルボタンを表示する",
+			capacity: 2,
+			setup: func(ctx context.Context, service *recruit.RecruitUsecase) error {
+				_, err := service.Join(ctx, "channel-1", "message-1", "user-1", nil)
+				return err
+			},
+			command: func(service *recruit.RecruitUsecase) discord.InteractionListener {
+				return NewJoinRecruitCommand(service)
+			},
+			interaction: func(*testing.T) *discordgo.Interaction {
+				return newButtonInteraction("user-1", "message-1", interactionJoin.toString())
+			},
+			want: func(t *testing.T, service *recruit.RecruitUsecase, interaction *discordgo.Interaction) []discordtest.Call {
+				return []discordtest.Call{
+					respondCall(interaction, deferredEphemeral(discordgo.InteractionResponseDeferredChannelMessageWithSource)),
+					responseEditCall(interaction,
+						"既に参加済み/辞退済み/未定で回答済みです。\nキャンセルする場合はボタンを押下してください。",
+						[]discordgo.MessageComponent{
+							discordgo.ActionsRow{
+								Components: []discordgo.MessageComponent{
+									discordgo.Button{
+										Label:    "❌ キャンセル",
+										Style:    discordgo.DangerButton,
+										CustomID: panelCustomID(t, interactionCancel),
+									},
+								},
+							},
+						},
+					),
+				}
+			},
+		},
+		{
+			name:     "作成者が参加しようとすると操作パネルを表示する",
+			capacity: 2,
+			command: func(service *recruit.RecruitUsecase) discord.InteractionListener {
+				return NewJoinRecruitCommand(service)
+			},
+			interaction: func(*testing.T) *discordgo.Interaction {
+				return newButtonInteraction("author-1", "message-1", interactionJoin.toString())
+			},
+			want: func(t *testing.T, service *recruit.RecruitUsecase, interaction *discordgo.Interaction) []discordtest.Call {
+				view, err := service.Get(context.Background(), "channel-1", "message-1")
+				if err != nil {
+					t.Fatalf("Get() error = %v", err)
+				}
+				message, components := buildAuthorControlPanel(view)
+				return []discordtest.Call{
+					respondCall(interaction, deferredEphemeral(discordgo.InteractionResponseDeferredChannelMessageWithSource)),
+					responseEditCall(interaction, message, components),
+				}
+			},
+		},
+		{
+			name:     "締め切られた募集には参加できない",
+			capacity: 2,
+			setup: func(ctx context.Context, service *recruit.RecruitUsecase) error {
+				_, err := service.Close(ctx, "channel-1", "message-1", "author-1")
+				return err
+			},
+			command: func(service *recruit.RecruitUsecase) discord.InteractionListener {
+				return NewJoinRecruitCommand(service)
+			},
+			interaction: func(*testing.T) *discordgo.Interaction {
+				return newButtonInteraction("user-1", "message-1", interactionJoin.toString())
+			},
+			want: func(t *testing.T, service *recruit.RecruitUsecase, interaction *discordgo.Interaction) []discordtest.Call {
+				return []discordtest.Call{
+					respondCall(interaction, deferredEphemeral(discordgo.InteractionResponseDeferredChannelMessageWithSource)),
+					responseEditCall(interaction, closedMessage, []discordgo.MessageComponent{}),
+				}
+			},
+		},
+		{
+			name:     "未回答から辞退した場合は通知しない",
+			capacity: 2,
+			command: func(service *recruit.RecruitUsecase) discord.InteractionListener {
+				return NewDeclineRecruitCommand(service)
+			},
+			interaction: func(*testing.T) *discordgo.Interaction {
+				return newButtonInteraction("user-1", "message-1", interactionDecline.toString())
+			},
+			want: func(t *testing.T, service *recruit.RecruitUsecase, interaction *discordgo.Interaction) []discordtest.Call {
+				return []discordtest.Call{
+					respondCall(interaction, deferredEphemeral(discordgo.InteractionResponseDeferredChannelMessageWithSource)),
+					recruitMessageEditCall(t, service),
+					responseDeleteCall(interaction),
+				}
+			},
+		},
+		{
+			name:     "参加から辞退すると取り消しと繰り上げを通知する",
+			capacity: 1,
+			setup: func(ctx context.Context, service *recruit.RecruitUsecase) error {
+				// 作成者とuser-1で満員になり、user-2とuser-3はキャンセル待ちになる
+				for _, userID := range []recruit.UserID{"user-1", "user-2", "user-3"} {
+					if _, err := service.Join(ctx, "channel-1", "message-1", userID, nil); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			command: func(service *recruit.RecruitUsecase) discord.InteractionListener {
+				return NewDeclineRecruitCommand(service)
+			},
+			interaction: func(*testing.T) *discordgo.Interaction {
+				return newButtonInteraction("user-1", "message-1", interactionDecline.toString())
+			},
+			want: func(t *testing.T, service *recruit.RecruitUsecase, interaction *discordgo.Interaction) []discordtest.Call {
+				return []discordtest.Call{
+					respondCall(interaction, deferredEphemeral(discordgo.InteractionResponseDeferredChannelMessageWithSource)),
+					recruitMessageEditCall(t, service),
+					responseDeleteCall(interaction),
+					replyCall("<@user-1> が参加を取り消しました。\n<@user-2> がキャンセル待ちから繰り上げ参加になりました。 @0"),
+				}
+			},
+		},
+		{
+			name:     "キャンセルボタンで参加を取り消す",
+			capacity: 2,
+			setup: func(ctx context.Context, service *recruit.RecruitUsecase) error {
+				_, err := service.Join(ctx, "channel-1", "message-1", "user-1", nil)
+				return err
+			},
+			command: func(service *recruit.RecruitUsecase) discord.InteractionListener {
+				return NewCancelRecruitCommand(service)
+			},
+			interaction: func(t *testing.T) *discordgo.Interaction {
+				// キャンセルボタンは参加ボタンへの応答のエフェメラルメッセージにある
+				return newButtonInteraction("user-1", "ephemeral-1", panelCustomID(t, interactionCancel))
+			},
+			want: func(t *testing.T, service *recruit.RecruitUsecase, interaction *discordgo.Interaction) []discordtest.Call {
+				return []discordtest.Call{
+					respondCall(interaction, deferredEphemeral(discordgo.InteractionResponseDeferredMessageUpdate)),
+					recruitMessageEditCall(t, service),
+					responseDeleteCall(interaction),
+					replyCall("<@user-1> が参加を取り消しました。 @2"),
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			service := newTestRecruitService(t, tt.capacity)
+			if tt.setup != nil {
+				if err := tt.setup(ctx, service); err != nil {
+					t.Fatalf("setup error = %v", err)
+				}
+			}
+			client := discordtest.NewClient()
+			interaction := tt.interaction(t)
+
+			if err := tt.command(service).Handle(ctx, client, interaction); err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+
+			assertCalls(t, client.Calls(), tt.want(t, service, interaction))
+		})
+	}
+}
+
+func TestParticipantActionCommand_Handle_RespondFailed(t *testing.T) {
+	ctx := context.Background()
+	service := newTestRecruitService(t, 2)
+	client := discordtest.NewClient()
+	respondErr := errors.New("unknown interaction")
+	client.Errors["InteractionRespond"] = respondErr
+	interaction := newButtonInteraction("user-1", "message-1", interactionJoin.toString())
+
+	err := NewJoinRecruitCommand(service).Handle(ctx, client, interaction)
+	if !errors.Is(err, respondErr) {
+		t.Fatalf("Handle() error = %v, want %v", err, respondErr)
+	}
+
+	// 3秒以内に応答できなかった操作は反映しない
+	if calls := client.Calls(); len(calls) != 1 {
+		t.Errorf("calls = %v, want only InteractionRespond", calls)
+	}
+	view, err := service.Get(ctx, "channel-1", "message-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(view.JoinedUsers) != 1 {
+		t.Errorf("joined users = %v, want only author", view.JoinedUsers)
+	}
+}
+
+func TestRecruitStatusCommand_Handle(t *testing.T) {
+	deferredUpdate := &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate}
+	notAuthorMessage, _ := domainErrorMessage(recruit.ErrNotAuthor)
+
+	tests := []struct {
+		name       string
+		userID     string
+		command    func(service *recruit.RecruitUsecase) discord.InteractionListener
+		customID   interactionCustomID
+		wantStatus recruit.RecruitStatus
+		want       func(t *testing.T, service *recruit.RecruitUsecase, interaction *discordgo.Interaction) []discordtest.Call
+	}{
+		{
+			name:   "作成者が締め切ると募集メッセージを更新して操作パネルを削除する",
+			userID: "author-1",
+			command: func(service *recruit.RecruitUsecase) discord.InteractionListener {
+				return NewCloseRecruitCommand(service)
+			},
+			customID:   interactionClose,
+			wantStatus: recruit.RecruitStatusClosed,
+			want: func(t *testing.T, service *recruit.RecruitUsecase, interaction *discordgo.Interaction) []discordtest.Call {
+				return []discordtest.Call{
+					respondCall(interaction, deferredUpdate),
+					recruitMessageEditCall(t, service),
+					responseDeleteCall(interaction),
+				}
+			},
+		},
+		{
+			name:   "作成者以外は締め切れない",
+			userID: "user-1",
+			command: func(service *recruit.RecruitUsecase) discord.InteractionListener {
+				return NewCloseRecruitCommand(service)
+			},
+			customID:   interactionClose,
+			wantStatus: recruit.RecruitStatusOpened,
+			want: func(t *testing.T, service *recruit.RecruitUsecase, interaction *discordgo.Interaction) []discordtest.Call {
+				return []discordtest.Call{
+					respondCall(interaction, deferredUpdate),
+					responseEditCall(interaction, notAuthorMessage, []discordgo.MessageComponent{}),
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			service := newTestRecruitService(t, 2)
+			client := discordtest.NewClient()
+			// 操作パネルは作成者向けのエフェメラルメッセージにある
+			interaction := newButtonInteraction(tt.userID, "panel-1", panelCustomID(t, tt.customID))
+
+			if err := tt.command(service).Handle(ctx, client, interaction); err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+
+			assertCalls(t, client.Calls(), tt.want(t, service, interaction))
+			view, err := service.Get(ctx, "channel-1", "message-1")
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if view.Meta.Status != tt.wantStatus {
+				t.Errorf("status = %v, want %v", view.Meta.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestUpdateRecruitMessage_LatestViewUnavailable(t *testing.T) {
+	service := newTestRecruitService(t, 2)
+	client := discordtest.NewClient()
+	// 変更時のViewは存在しない募集を指す
+	stale := &recruit.RecruitView{
+		Meta: &recruit.RecruitState{ChannelID: "channel-1", MessageID: "message-2", MaxCapacity: 2},
+	}
+
+	if err := updateRecruitMessage(context.Background(), client, service, stale); err != nil {
+		t.Fatalf("updateRecruitMessage() error = %v", err)
+	}
+
+	// 変更時の古い状態で再描画しない
+	if calls := client.Calls(); len(calls) != 0 {
+		t.Errorf("calls = %v, want none", calls)
+	}
+}
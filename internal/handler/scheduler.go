@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"at-bot/internal/discord"
+	"at-bot/internal/recruit"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+type recruitScheduler struct {
+	service      *recruit.RecruitUsecase
+	remindBefore time.Duration
+	ttl          time.Duration
+}
+
+// NewRecruitScheduler はリマインドと期限切れ処理を行うスケジューラを作成する
+// ttlが0の場合は期限切れ処理を行わない
+func NewRecruitScheduler(
+	service *recruit.RecruitUsecase,
+	remindBefore time.Duration,
+	ttl time.Duration,
+) *recruitScheduler {
+	return &recruitScheduler{
+		service:      service,
+		remindBefore: remindBefore,
+		ttl:          ttl,
+	}
+}
+
+// Run はctxがキャンセルされるまでinterval毎に開始時刻や有効期限の到来した募集を処理する
+func (scheduler *recruitScheduler) Run(ctx context.Context, session discord.Client, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		scheduler.tick(ctx, session, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (scheduler *recruitScheduler) tick(ctx context.Context, session discord.Client, now time.Time) {
+	// 停止時に処理の途中で中断しないよう、ctxのキャンセルは引き継がない
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	// 開始前リマインド
+	reminded, err := scheduler.service.Remind(ctx, now, scheduler.remindBefore)
+	if err != nil {
+		log.Printf("[SCHEDULE] failed to remind recruits: %v", err)
+	}
+	for _, view := range reminded {
+		if err := replyRecruitMessage(session, view, createRemindMessage(view)); err != nil {
+			log.Printf("[SCHEDULE] failed to send reminder. messageId: %s, %v", view.Meta.MessageID, err)
+		}
+	}
+
+	// 開始時刻を過ぎた募集を開始済みに更新
+	started, err := scheduler.service.Start(ctx, now)
+	if err != nil {
+		log.Printf("[SCHEDULE] failed to start recruits: %v", err)
+	}
+	for _, view := range started {
+		if err := updateRecruitMessage(ctx, session, scheduler.service, view); err != nil {
+			log.Printf("[SCHEDULE] failed to update recruit message. messageId: %s, %v", view.Meta.MessageID, err)
+		}
+	}
+
+	if scheduler.ttl <= 0 {
+		return
+	}
+
+	// 有効期限を過ぎた募集を締め切り、ボタンを取り除く
+	expired, err := scheduler.service.Expire(ctx, now, scheduler.ttl)
+	if err != nil {
+		log.Printf("[SCHEDULE] failed to expire recruits: %v", err)
+	}
+	for _, view := range expired {
+		log.Printf("[SCHEDULE] recruit %d expired", view.Meta.ID)
+		if err := updateRecruitMessage(ctx, session, scheduler.service, view); err != nil {
+			log.Printf("[SCHEDULE] failed to update recruit message. messageId: %s, %v", view.Meta.MessageID, err)
+		}
+	}
+}
+
+func createRemindMessage(view *recruit.RecruitView) string {
+	mentions := make([]string, 0, len(view.JoinedUsers))
+	for _, u := range view.JoinedUsers {
+		mentions = append(mentions, discord.FormatMention(string(u)))
+	}
+
+	return fmt.Sprintf(
+		"⏰ まもなく開始します。(%s)\n%s",
+		discord.FormatTimestamp(*view.Meta.StartAt, discord.TimestampRelative),
+		strings.Join(mentions, " "),
+	)
+}
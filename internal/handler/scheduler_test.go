@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"at-bot/internal/recruit"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateRemindMessage(t *testing.T) {
+	startAt := time.Unix(1700000000, 0)
+	view := &recruit.RecruitView{
+		Meta: &recruit.RecruitState{
+			MaxCapacity: 5,
+			StartAt:     &startAt,
+		},
+		JoinedUsers: []recruit.UserID{"author", "user-1"},
+	}
+
+	got := createRemindMessage(view)
+
+	for _, want := range []string{"<t:1700000000:R>", "<@author> <@user-1>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("createRemindMessage() = %v, should contain %v", got, want)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package handler
 
-import "github.com/bwmarrin/discordgo"
+import (
+	"at-bot/internal/discord"
+
+	"github.com/bwmarrin/discordgo"
+)
 
 type baseSlashCommand struct{}
 
@@ -12,3 +16,35 @@ func (b *baseSlashCommand) getOptionMap(interaction *discordgo.Interaction) map[
 	}
 	return optionMap
 }
+
+// getSubcommand は実行されたサブコマンドの名前と、そのオプションを返す
+func (b *baseSlashCommand) getSubcommand(
+	interaction *discordgo.Interaction,
+) (string, map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	options := interaction.ApplicationCommandData().Options
+	if len(options) == 0 || options[0].Type != discordgo.ApplicationCommandOptionSubCommand {
+		return "", map[string]*discordgo.ApplicationCommandInteractionDataOption{}
+	}
+
+	subcommand := options[0]
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(subcommand.Options))
+	for _, opt := range subcommand.Options {
+		optionMap[opt.Name] = opt
+	}
+	return subcommand.Name, optionMap
+}
+
+// respondEphemeral は実行したユーザーにのみ見えるメッセージで応答する
+func (b *baseSlashCommand) respondEphemeral(
+	session discord.Client,
+	interaction *discordgo.Interaction,
+	content string,
+) error {
+	return session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
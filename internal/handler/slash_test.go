@@ -83,3 +83,66 @@ func TestBaseSlashCommand_GetOptionMap(t *testing.T) {
 		})
 	}
 }
+
+func TestBaseSlashCommand_GetSubcommand(t *testing.T) {
+	base := &baseSlashCommand{}
+
+	tests := []struct {
+		name        string
+		options     []*discordgo.ApplicationCommandInteractionDataOption
+		wantName    string
+		wantOptions []string
+	}{
+		{
+			name: "サブコマンドとそのオプションを取得",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{
+					Name: "list",
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Options: []*discordgo.ApplicationCommandInteractionDataOption{
+						{Name: "option1", Type: discordgo.ApplicationCommandOptionBoolean, Value: true},
+					},
+				},
+			},
+			wantName:    "list",
+			wantOptions: []string{"option1"},
+		},
+		{
+			name: "サブコマンドではない場合は空",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "option1", Type: discordgo.ApplicationCommandOptionString, Value: "value1"},
+			},
+			wantName: "",
+		},
+		{
+			name:     "オプションがnil",
+			options:  nil,
+			wantName: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interaction := &discordgo.Interaction{
+				Type: discordgo.InteractionApplicationCommand,
+				Data: discordgo.ApplicationCommandInteractionData{
+					Options: tt.options,
+				},
+			}
+
+			name, optionMap := base.getSubcommand(interaction)
+
+			if name != tt.wantName {
+				t.Errorf("getSubcommand() name = %v, want %v", name, tt.wantName)
+			}
+			if len(optionMap) != len(tt.wantOptions) {
+				t.Errorf("getSubcommand() options length = %v, want %v", len(optionMap), len(tt.wantOptions))
+			}
+			for _, key := range tt.wantOptions {
+				if _, ok := optionMap[key]; !ok {
+					t.Errorf("getSubcommand() missing key %v", key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"at-bot/internal/discord"
+	"at-bot/internal/recruit"
+	"at-bot/internal/stats"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// 統計コマンド用の固定値
+const (
+	statsCommandName     = "stats"
+	statsUserSubcommand  = "user"
+	statsGuildSubcommand = "guild"
+	statsUserArgName     = "ユーザー"
+	statsColor           = 0x5865f2
+	statsEmptyValue      = "なし"
+)
+
+type statsSlashCommand struct {
+	baseSlashCommand
+	service *stats.StatsUsecase
+}
+
+func NewStatsSlashCommand(service *stats.StatsUsecase) *statsSlashCommand {
+	return &statsSlashCommand{
+		service: service,
+	}
+}
+
+func (command *statsSlashCommand) CreateCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        statsCommandName,
+		Description: "募集への参加履歴の統計を表示します。",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        statsUserSubcommand,
+				Description: "ユーザーの募集作成数、参加/辞退/キャンセル数とよく一緒に参加するメンバーを表示します。",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionUser,
+						Name:        statsUserArgName,
+						Description: "統計を表示するユーザーを指定します。(省略時: 自分)",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        statsGuildSubcommand,
+				Description: "サーバー全体の募集数と参加回数の多いメンバーを表示します。",
+			},
+		},
+	}
+}
+
+func (command *statsSlashCommand) InteractionType() discordgo.InteractionType {
+	return discordgo.InteractionApplicationCommand
+}
+
+func (command *statsSlashCommand) InteractionID() string {
+	return statsCommandName
+}
+
+func (command *statsSlashCommand) MatchInteractionID(interactionID string) bool {
+	return command.InteractionID() == interactionID
+}
+
+func (command *statsSlashCommand) Handle(ctx context.Context, session discord.Client, interaction *discordgo.Interaction) error {
+	subcommand, optionMap := command.getSubcommand(interaction)
+	guildID := recruit.GuildID(interaction.GuildID)
+
+	var embed *discordgo.MessageEmbed
+	switch subcommand {
+	case statsUserSubcommand:
+		userID := recruit.UserID(interaction.Member.User.ID)
+		if opt, ok := optionMap[statsUserArgName]; ok && opt != nil {
+			userID = recruit.UserID(opt.UserValue(nil).ID)
+		}
+
+		log.Printf("[STATS] user %s checked stats of user %s", interaction.Member.User.ID, userID)
+
+		userStats, err := command.service.UserStats(ctx, guildID, userID)
+		if err != nil {
+			_ = command.respondEphemeral(session, interaction, ErrorMessageContent)
+			return err
+		}
+		embed = createUserStatsEmbed(userStats)
+	case statsGuildSubcommand:
+		log.Printf("[STATS] user %s checked stats of guild %s", interaction.Member.User.ID, guildID)
+
+		guildStats, err := command.service.GuildStats(ctx, guildID)
+		if err != nil {
+			_ = command.respondEphemeral(session, interaction, ErrorMessageContent)
+			return err
+		}
+		embed = createGuildStatsEmbed(guildStats)
+	default:
+		return fmt.Errorf("unknown subcommand: %q", subcommand)
+	}
+
+	return session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			// 統計の表示でメンションを飛ばさない
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+		},
+	})
+}
+
+func createUserStatsEmbed(s *stats.UserStats) *discordgo.MessageEmbed {
+	coParticipants := make([]string, 0, len(s.CoParticipants))
+	for i, c := range s.CoParticipants {
+		coParticipants = append(coParticipants, fmt.Sprintf(
+			"%d. %s %d回",
+			i+1,
+			discord.FormatMention(string(c.UserID)),
+			c.Count,
+		))
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "📊 参加履歴",
+		Description: discord.FormatMention(string(s.UserID)),
+		Color:       statsColor,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "募集作成", Value: fmt.Sprintf("%d回", s.Opened), Inline: true},
+			{Name: "参加", Value: fmt.Sprintf("%d回", s.Joined), Inline: true},
+			{Name: "辞退", Value: fmt.Sprintf("%d回", s.Declined), Inline: true},
+			{Name: "キャンセル", Value: fmt.Sprintf("%d回", s.Canceled), Inline: true},
+			{Name: "キャンセル率", Value: formatCancelRate(s.CancelRate()), Inline: true},
+			{Name: "よく一緒に参加するメンバー", Value: joinOrEmpty(coParticipants)},
+		},
+	}
+}
+
+func createGuildStatsEmbed(s *stats.GuildStats) *discordgo.MessageEmbed {
+	members := make([]string, 0, len(s.TopMembers))
+	for i, m := range s.TopMembers {
+		members = append(members, fmt.Sprintf(
+			"%d. %s 参加%d回 キャンセル率%s",
+			i+1,
+			discord.FormatMention(string(m.UserID)),
+			m.Joined,
+			formatCancelRate(m.CancelRate()),
+		))
+	}
+
+	pairs := make([]string, 0, len(s.TopPairs))
+	for i, p := range s.TopPairs {
+		pairs = append(pairs, fmt.Sprintf(
+			"%d. %s & %s %d回",
+			i+1,
+			discord.FormatMention(string(p.UserA)),
+			discord.FormatMention(string(p.UserB)),
+			p.Count,
+		))
+	}
+
+	return &discordgo.MessageEmbed{
+		Title: "📊 サーバーの参加履歴",
+		Color: statsColor,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "募集", Value: fmt.Sprintf("%d件", s.Recruits), Inline: true},
+			{Name: "参加", Value: fmt.Sprintf("%d回", s.Joined), Inline: true},
+			{Name: "辞退", Value: fmt.Sprintf("%d回", s.Declined), Inline: true},
+			{Name: "キャンセル", Value: fmt.Sprintf("%d回", s.Canceled), Inline: true},
+			{Name: "参加回数の多いメンバー", Value: joinOrEmpty(members)},
+			{Name: "よく一緒に参加する組", Value: joinOrEmpty(pairs)},
+		},
+	}
+}
+
+// formatCancelRate はキャンセル率を百分率で返す。算出できない場合は「-」を返す
+func formatCancelRate(rate float64, ok bool) string {
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", rate*100)
+}
+
+func joinOrEmpty(lines []string) string {
+	if len(lines) == 0 {
+		return statsEmptyValue
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"at-bot/internal/stats"
+	"testing"
+)
+
+func TestStatsSlashCommand_CreateCommand(t *testing.T) {
+	cmd := NewStatsSlashCommand(nil)
+	command := cmd.CreateCommand()
+
+	if command.Name != statsCommandName {
+		t.Errorf("CreateCommand().Name = %v, want %v", command.Name, statsCommandName)
+	}
+
+	if len(command.Options) != 2 {
+		t.Fatalf("CreateCommand().Options length = %v, want 2", len(command.Options))
+	}
+}
+
+func TestFormatCancelRate(t *testing.T) {
+	tests := []struct {
+		name string
+		rate float64
+		ok   bool
+		want string
+	}{
+		{name: "算出できない場合", ok: false, want: "-"},
+		{name: "0%の場合", rate: 0, ok: true, want: "0%"},
+		{name: "端数は四捨五入", rate: 1.0 / 3.0, ok: true, want: "33%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatCancelRate(tt.rate, tt.ok); got != tt.want {
+				t.Errorf("formatCancelRate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateUserStatsEmbed(t *testing.T) {
+	embed := createUserStatsEmbed(&stats.UserStats{
+		UserID:   "user-1",
+		Opened:   1,
+		Joined:   3,
+		Canceled: 1,
+		CoParticipants: []stats.CoParticipant{
+			{UserID: "user-2", Count: 2},
+		},
+	})
+
+	values := map[string]string{}
+	for _, f := range embed.Fields {
+		values[f.Name] = f.Value
+	}
+
+	want := map[string]string{
+		"募集作成":   "1回",
+		"参加":     "3回",
+		"キャンセル率": "25%",
+		"よく一緒に参加するメンバー": "1. <@user-2> 2回",
+	}
+	for name, value := range want {
+		if values[name] != value {
+			t.Errorf("field %s = %v, want %v", name, values[name], value)
+		}
+	}
+}
+
+func TestCreateGuildStatsEmbed_Empty(t *testing.T) {
+	embed := createGuildStatsEmbed(&stats.GuildStats{})
+
+	for _, f := range embed.Fields[4:] {
+		if f.Value != statsEmptyValue {
+			t.Errorf("field %s = %v, want %v", f.Name, f.Value, statsEmptyValue)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package handler
 
 import (
 	"at-bot/internal/buildinfo"
+	"at-bot/internal/discord"
+	"context"
 	"fmt"
 	"log"
 
@@ -37,7 +39,7 @@ func (command *versionSlashCommand) MatchInteractionID(interactionID string) boo
 	return command.InteractionID() == interactionID
 }
 
-func (command *versionSlashCommand) Handle(session *discordgo.Session, interaction *discordgo.Interaction) error {
+func (command *versionSlashCommand) Handle(ctx context.Context, session discord.Client, interaction *discordgo.Interaction) error {
 	log.Printf(
 		"[VERSION] user %s checked version %s (%s)",
 		interaction.Member.User.ID,
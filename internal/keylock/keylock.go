@@ -0,0 +1,63 @@
+package keylock
+
+import (
+	"context"
+	"sync"
+)
+
+type entry struct {
+	// sem は容量1のチャネルで、送信できた場合にロックを取得したものとする
+	sem  chan struct{}
+	refs int
+}
+
+// Mutex はキーごとに排他制御を行う
+// ロックを待機中または保持中のキーのみ保持するため、キーの種類が増えてもメモリを消費し続けない
+type Mutex struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+func New() *Mutex {
+	return &Mutex{
+		entries: make(map[string]*entry),
+	}
+}
+
+// Lock はkeyのロックを取得し、解放する関数を返す
+// ロックを取得する前にctxがキャンセルされた場合はエラーを返す
+func (m *Mutex) Lock(ctx context.Context, key string) (unlock func(), err error) {
+	m.mu.Lock()
+	e, ok := m.entries[key]
+	if !ok {
+		e = &entry{sem: make(chan struct{}, 1)}
+		m.entries[key] = e
+	}
+	e.refs++
+	m.mu.Unlock()
+
+	select {
+	case e.sem <- struct{}{}:
+	case <-ctx.Done():
+		m.release(key, e)
+		return nil, ctx.Err()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-e.sem
+			m.release(key, e)
+		})
+	}, nil
+}
+
+func (m *Mutex) release(key string, e *entry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e.refs--
+	if e.refs == 0 {
+		delete(m.entries, key)
+	}
+}
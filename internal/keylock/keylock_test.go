@@ -0,0 +1,91 @@
+package keylock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMutex_Lock(t *testing.T) {
+	m := New()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	// キーごとのカウンタはそのキーのロック中のみ更新する(排他できていなければ-raceで検出される)
+	counts := map[string]*int{"a": new(int), "b": new(int)}
+	for i := 0; i < 50; i++ {
+		for _, key := range []string{"a", "b"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				unlock, err := m.Lock(ctx, key)
+				if err != nil {
+					t.Errorf("Lock() error = %v", err)
+					return
+				}
+				defer unlock()
+
+				*counts[key]++
+			}()
+		}
+	}
+	wg.Wait()
+
+	if *counts["a"] != 50 || *counts["b"] != 50 {
+		t.Errorf("counts = a:%d b:%d, want 50 each", *counts["a"], *counts["b"])
+	}
+	// 使用中のキーがなくなったら保持しない
+	if len(m.entries) != 0 {
+		t.Errorf("entries = %d, want 0", len(m.entries))
+	}
+}
+
+func TestMutex_LockIndependentKeys(t *testing.T) {
+	m := New()
+	ctx := context.Background()
+
+	unlock, err := m.Lock(ctx, "a")
+	if err != nil {
+		t.Fatalf("Lock(a) error = %v", err)
+	}
+	defer unlock()
+
+	// 別のキーは待たずに取得できる
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	unlockB, err := m.Lock(ctx, "b")
+	if err != nil {
+		t.Fatalf("Lock(b) error = %v", err)
+	}
+	unlockB()
+}
+
+func TestMutex_LockCanceled(t *testing.T) {
+	m := New()
+
+	unlock, err := m.Lock(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := m.Lock(ctx, "a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Lock() while locked error = %v, want DeadlineExceeded", err)
+	}
+
+	// 解放後は取得でき、二重に解放しても影響しない
+	unlock()
+	unlock()
+	unlock, err = m.Lock(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("Lock() after unlock error = %v", err)
+	}
+	unlock()
+
+	if len(m.entries) != 0 {
+		t.Errorf("entries = %d, want 0", len(m.entries))
+	}
+}
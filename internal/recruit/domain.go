@@ -10,11 +10,24 @@ type GuildID string
 type ChannelID string
 type MessageID string
 type UserID string
+type RoleID string
 type RecruitStatus string
 
 const (
-	RecruitStatusOpened RecruitStatus = "opened"
-	RecruitStatusClosed RecruitStatus = "closed"
+	RecruitStatusOpened  RecruitStatus = "opened"
+	RecruitStatusStarted RecruitStatus = "started"
+	RecruitStatusClosed  RecruitStatus = "closed"
+)
+
+// CloseReason は募集が締め切られた理由
+type CloseReason string
+
+const (
+	CloseReasonNone    CloseReason = ""
+	CloseReasonManual  CloseReason = "manual"
+	CloseReasonExpired CloseReason = "expired"
+	// CloseReasonDeleted は作成者が削除した募集。統計のため記録はアーカイブとして残す
+	CloseReasonDeleted CloseReason = "deleted"
 )
 
 type ParticipantStatus string
@@ -23,6 +36,12 @@ const (
 	ParticipantStatusJoined   ParticipantStatus = "joined"
 	ParticipantStatusDeclined ParticipantStatus = "declined"
 	ParticipantStatusCanceled ParticipantStatus = "canceled"
+	// ParticipantStatusWaitlisted は満員時に参加したキャンセル待ちの状態
+	ParticipantStatusWaitlisted ParticipantStatus = "waitlisted"
+	// ParticipantStatusTentative は参加未定の状態。募集枠にはカウントしない
+	ParticipantStatusTentative ParticipantStatus = "tentative"
+	// ParticipantStatusKicked は作成者に外された状態。キャンセルの統計には含めない
+	ParticipantStatusKicked ParticipantStatus = "kicked"
 )
 
 type RecruitState struct {
@@ -33,8 +52,103 @@ type RecruitState struct {
 	AuthorID    UserID
 	MaxCapacity int
 	Status      RecruitStatus
-	CreatedAt   time.Time
-	UpdatedAt   *time.Time
+	StartAt     *time.Time
+	RemindedAt  *time.Time
+	CloseReason CloseReason
+	ReopenedAt  *time.Time
+	// Title と Description は任意項目。未指定の場合は空文字
+	Title       string
+	Description string
+	// RoleID は募集の対象ロール。RoleRestricted の場合は対象ロールを持つメンバーのみ参加できる
+	RoleID         RoleID
+	RoleRestricted bool
+	CreatedAt      time.Time
+	UpdatedAt      *time.Time
+}
+
+// ShouldRemind は開始前リマインドを送るべき状態かを判定する
+func (s *RecruitState) ShouldRemind(now time.Time, remindBefore time.Duration) bool {
+	if s.Status != RecruitStatusOpened || s.StartAt == nil || s.RemindedAt != nil {
+		return false
+	}
+	return !now.Before(s.StartAt.Add(-remindBefore))
+}
+
+// ShouldStart は開始時刻を過ぎて開始済みにすべき状態かを判定する
+func (s *RecruitState) ShouldStart(now time.Time) bool {
+	if s.Status != RecruitStatusOpened || s.StartAt == nil {
+		return false
+	}
+	return !now.Before(*s.StartAt)
+}
+
+// ExpiresAt は募集の有効期限を返す
+// 作成日時、開始時刻、再開日時のうち最も遅いものを起点とする
+func (s *RecruitState) ExpiresAt(ttl time.Duration) time.Time {
+	base := s.CreatedAt
+	for _, t := range []*time.Time{s.StartAt, s.ReopenedAt} {
+		if t != nil && t.After(base) {
+			base = *t
+		}
+	}
+	return base.Add(ttl)
+}
+
+// IsExpired は有効期限を過ぎても締め切られていない状態かを判定する
+func (s *RecruitState) IsExpired(now time.Time, ttl time.Duration) bool {
+	if s.Status == RecruitStatusClosed {
+		return false
+	}
+	return !now.Before(s.ExpiresAt(ttl))
+}
+
+// Expire は期限切れとして募集を締め切る
+func (s *RecruitState) Expire() {
+	s.Status = RecruitStatusClosed
+	s.CloseReason = CloseReasonExpired
+}
+
+// Close は作成者の操作として募集を締め切る
+func (s *RecruitState) Close() error {
+	if s.Status == RecruitStatusClosed {
+		return ErrRecruitClosed
+	}
+	s.Status = RecruitStatusClosed
+	s.CloseReason = CloseReasonManual
+	return nil
+}
+
+// Reopen は締め切った募集を再開する
+func (s *RecruitState) Reopen(now time.Time) error {
+	if s.CloseReason == CloseReasonDeleted {
+		return ErrRecruitNotFound
+	}
+	if s.Status != RecruitStatusClosed {
+		return ErrRecruitNotClosed
+	}
+	s.Status = RecruitStatusOpened
+	s.CloseReason = CloseReasonNone
+	s.ReopenedAt = &now
+	return nil
+}
+
+// Archive は作成者が削除した募集としてアーカイブする
+func (s *RecruitState) Archive() {
+	s.Status = RecruitStatusClosed
+	s.CloseReason = CloseReasonDeleted
+}
+
+// CanJoin は指定したロールを持つメンバーが参加できるかを判定する
+func (s *RecruitState) CanJoin(roles []RoleID) bool {
+	if !s.RoleRestricted || s.RoleID == "" {
+		return true
+	}
+	for _, role := range roles {
+		if role == s.RoleID {
+			return true
+		}
+	}
+	return false
 }
 
 type Participant struct {
@@ -45,12 +159,25 @@ type Participant struct {
 	UpdatedAt *time.Time
 }
 
+// StatusChangedAt は参加状態が最後に変更された日時を返す
+func (p *Participant) StatusChangedAt() time.Time {
+	if p.UpdatedAt != nil {
+		return *p.UpdatedAt
+	}
+	return p.CreatedAt
+}
+
 type RecruitView struct {
 	Meta          *RecruitState
 	JoinedUsers   []UserID
 	DeclinedUsers []UserID
+	// WaitlistedUsers はキャンセル待ちに登録した順に並ぶ
+	WaitlistedUsers []UserID
+	TentativeUsers  []UserID
 }
 
+// RemainingSlots は残り枠数を返す
+// 未定のユーザーは参加者に含めないため枠を消費しない
 func (v *RecruitView) RemainingSlots() int {
 	remaining := v.Meta.MaxCapacity - len(v.JoinedUsers) + 1
 	if remaining < 0 {
@@ -74,11 +201,33 @@ func (v *RecruitView) ExtraCount() int {
 var (
 	ErrAlreadyJoined    = errors.New("既に参加済みです")
 	ErrAlreadyDeclined  = errors.New("既に辞退済みです")
+	ErrAlreadyTentative = errors.New("既に未定で回答済みです")
 	ErrAuthorCannotJoin = errors.New("作成者は参加/辞退できません")
 	ErrRecruitNotFound  = errors.New("募集が見つかりません")
+	ErrNotAuthor        = errors.New("作成者以外は操作できません")
+	ErrRecruitClosed    = errors.New("募集は締め切られています")
+	ErrRecruitNotClosed = errors.New("募集は締め切られていません")
+	ErrInvalidStartAt   = errors.New("開始時刻の形式が正しくありません")
+	ErrStartAtInPast    = errors.New("開始時刻が過去の日時です")
+	ErrInvalidCapacity  = errors.New("募集人数は1以上で指定してください")
+	// ErrCapacityBelowJoined は作成者を除く参加者数より少ない募集人数への変更を表す
+	ErrCapacityBelowJoined = errors.New("参加者数より少ない人数には変更できません")
+	ErrCannotKickAuthor    = errors.New("作成者は募集から外せません")
+	ErrParticipantNotFound = errors.New("指定したユーザーは募集に参加していません")
+	ErrRoleRequired        = errors.New("対象のロールを持つメンバーのみ参加できます")
 )
 
 type ParticipantStatusChangeResult struct {
 	CurrentView    *RecruitView
 	PreviousStatus *ParticipantStatus
+	// CurrentStatus は実際に反映された状態。満員時の参加はキャンセル待ちになる
+	CurrentStatus ParticipantStatus
+	// PromotedUsers はこの変更によりキャンセル待ちから繰り上がったユーザー
+	PromotedUsers []UserID
+}
+
+type CapacityChangeResult struct {
+	CurrentView *RecruitView
+	// PromotedUsers は募集人数の増加によりキャンセル待ちから繰り上がったユーザー
+	PromotedUsers []UserID
 }
@@ -1,7 +1,9 @@
 package recruit
 
 import (
+	"errors"
 	"testing"
+	"time"
 )
 
 func TestRecruitView_RemainingSlots(t *testing.T) {
@@ -64,6 +66,23 @@ func TestRecruitView_RemainingSlots(t *testing.T) {
 	}
 }
 
+func TestRecruitView_RemainingSlots_IgnoresTentative(t *testing.T) {
+	view := &RecruitView{
+		Meta: &RecruitState{
+			MaxCapacity: 2,
+		},
+		JoinedUsers:    []UserID{"author", "user1"},
+		TentativeUsers: []UserID{"user2", "user3"},
+	}
+
+	if got := view.RemainingSlots(); got != 1 {
+		t.Errorf("RemainingSlots() = %v, want 1", got)
+	}
+	if view.IsFull() {
+		t.Errorf("IsFull() = true, want false")
+	}
+}
+
 func TestRecruitView_IsFull(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -183,3 +202,132 @@ func TestRecruitView_ExtraCount(t *testing.T) {
 		})
 	}
 }
+
+func TestRecruitState_IsExpired(t *testing.T) {
+	now := time.Date(2025, 1, 2, 20, 0, 0, 0, time.UTC)
+	ttl := 24 * time.Hour
+	startAt := now.Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		state RecruitState
+		want  bool
+	}{
+		{
+			name:  "作成から期限を過ぎた募集中の募集は期限切れ",
+			state: RecruitState{Status: RecruitStatusOpened, CreatedAt: now.Add(-25 * time.Hour)},
+			want:  true,
+		},
+		{
+			name:  "作成から期限ちょうどは期限切れ",
+			state: RecruitState{Status: RecruitStatusOpened, CreatedAt: now.Add(-ttl)},
+			want:  true,
+		},
+		{
+			name:  "期限内は対象外",
+			state: RecruitState{Status: RecruitStatusOpened, CreatedAt: now.Add(-time.Hour)},
+			want:  false,
+		},
+		{
+			name:  "開始済みでも期限を過ぎていれば期限切れ",
+			state: RecruitState{Status: RecruitStatusStarted, CreatedAt: now.Add(-25 * time.Hour)},
+			want:  true,
+		},
+		{
+			name:  "開始時刻がある場合は開始時刻を起点にする",
+			state: RecruitState{Status: RecruitStatusOpened, CreatedAt: now.Add(-48 * time.Hour), StartAt: &startAt},
+			want:  false,
+		},
+		{
+			name:  "再開した場合は再開日時を起点にする",
+			state: RecruitState{Status: RecruitStatusOpened, CreatedAt: now.Add(-48 * time.Hour), ReopenedAt: &startAt},
+			want:  false,
+		},
+		{
+			name:  "締め切り済みは対象外",
+			state: RecruitState{Status: RecruitStatusClosed, CreatedAt: now.Add(-25 * time.Hour)},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.state.IsExpired(now, ttl); got != tt.want {
+				t.Errorf("IsExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecruitState_CanJoin(t *testing.T) {
+	tests := []struct {
+		name  string
+		state RecruitState
+		roles []RoleID
+		want  bool
+	}{
+		{
+			name:  "ロール未指定の場合は誰でも参加できる",
+			state: RecruitState{},
+			want:  true,
+		},
+		{
+			name:  "ロールを指定しても限定しない場合は誰でも参加できる",
+			state: RecruitState{RoleID: "role-1"},
+			want:  true,
+		},
+		{
+			name:  "限定ロールを持つ場合は参加できる",
+			state: RecruitState{RoleID: "role-1", RoleRestricted: true},
+			roles: []RoleID{"role-1"},
+			want:  true,
+		},
+		{
+			name:  "限定ロールを持たない場合は参加できない",
+			state: RecruitState{RoleID: "role-1", RoleRestricted: true},
+			roles: []RoleID{"role-2"},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.state.CanJoin(tt.roles); got != tt.want {
+				t.Errorf("CanJoin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecruitState_Reopen(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		state   RecruitState
+		wantErr error
+	}{
+		{
+			name:  "締め切り済みの募集は再開できる",
+			state: RecruitState{Status: RecruitStatusClosed, CloseReason: CloseReasonManual},
+		},
+		{
+			name:    "募集中の募集は再開できない",
+			state:   RecruitState{Status: RecruitStatusOpened},
+			wantErr: ErrRecruitNotClosed,
+		},
+		{
+			name:    "削除済みの募集は再開できない",
+			state:   RecruitState{Status: RecruitStatusClosed, CloseReason: CloseReasonDeleted},
+			wantErr: ErrRecruitNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.state.Reopen(now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Reopen() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
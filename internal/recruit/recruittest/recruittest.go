@@ -0,0 +1,514 @@
+// Package recruittest はrecruitのリポジトリ実装が満たすべき振る舞いを検証する共通のテストスイートを提供する
+package recruittest
+
+import (
+	"at-bot/internal/recruit"
+	"at-bot/internal/uow"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// Backend はテスト対象のリポジトリ実装の組
+type Backend struct {
+	Recruits     recruit.RecruitRepository
+	Participants recruit.ParticipantRepository
+	UnitOfWork   uow.UnitOfWork
+}
+
+// Run は空のDBに接続したBackendに対して共通のテストを実行する
+// newBackendはサブテストごとに呼び出され、他のサブテストのデータが見えないBackendを返すこと
+func Run(t *testing.T, newBackend func(t *testing.T) Backend) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, b Backend)
+	}{
+		{name: "募集を作成して取得できる", fn: testCreateAndGet},
+		{name: "存在しない募集の取得はエラー", fn: testGetNotFound},
+		{name: "削除された募集はメッセージから取得できない", fn: testGetByMessageArchived},
+		{name: "募集を更新できる", fn: testUpdate},
+		{name: "存在しない募集の更新はエラー", fn: testUpdateNotFound},
+		{name: "募集を削除すると参加者も削除される", fn: testDelete},
+		{name: "開始時刻を過ぎた募集中の募集を返す", fn: testListScheduled},
+		{name: "期限切れの締め切られていない募集を返す", fn: testListExpired},
+		{name: "ギルド内の締め切られていない募集を返す", fn: testListOpened},
+		{name: "参加者を登録して状態を更新できる", fn: testUpsertParticipant},
+		{name: "参加者は初回登録順に並ぶ", fn: testListParticipants},
+		{name: "参加者を全て削除できる", fn: testDeleteAllParticipants},
+		{name: "UnitOfWorkは正常終了でコミットする", fn: testUnitOfWorkCommit},
+		{name: "UnitOfWorkはエラーでロールバックする", fn: testUnitOfWorkRollback},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newBackend(t))
+		})
+	}
+}
+
+// baseTime はDBごとの時刻の精度の違いを受けないよう秒単位に丸めた基準時刻
+var baseTime = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newState(messageID recruit.MessageID) *recruit.RecruitState {
+	return &recruit.RecruitState{
+		GuildID:     "guild-1",
+		ChannelID:   "channel-1",
+		MessageID:   messageID,
+		AuthorID:    "author-1",
+		MaxCapacity: 3,
+		Status:      recruit.RecruitStatusOpened,
+		CreatedAt:   baseTime,
+	}
+}
+
+func create(t *testing.T, b Backend, state *recruit.RecruitState) recruit.RecruitID {
+	t.Helper()
+
+	id, err := b.Recruits.Create(context.Background(), state)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	state.ID = id
+	return id
+}
+
+func messageIDs(states []*recruit.RecruitState) []recruit.MessageID {
+	ids := make([]recruit.MessageID, 0, len(states))
+	for _, s := range states {
+		ids = append(ids, s.MessageID)
+	}
+	return ids
+}
+
+func equalMessageIDs(got []*recruit.RecruitState, want ...recruit.MessageID) bool {
+	ids := messageIDs(got)
+	if len(ids) != len(want) {
+		return false
+	}
+	for i := range ids {
+		if ids[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalTime(got *time.Time, want *time.Time) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+	return got.Equal(*want)
+}
+
+func testCreateAndGet(t *testing.T, b Backend) {
+	ctx := context.Background()
+	startAt := baseTime.Add(time.Hour)
+	state := newState("message-1")
+	state.StartAt = &startAt
+	state.Title = "タイトル"
+	state.Description = "内容"
+	state.RoleID = "role-1"
+	state.RoleRestricted = true
+	id := create(t, b, state)
+
+	got, err := b.Recruits.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.ID != id || got.GuildID != state.GuildID || got.ChannelID != state.ChannelID ||
+		got.MessageID != state.MessageID || got.AuthorID != state.AuthorID ||
+		got.MaxCapacity != state.MaxCapacity || got.Status != state.Status {
+		t.Errorf("Get() = %+v, want %+v", got, state)
+	}
+	if got.Title != state.Title || got.Description != state.Description ||
+		got.RoleID != state.RoleID || got.RoleRestricted != state.RoleRestricted {
+		t.Errorf("Get() = %+v, want %+v", got, state)
+	}
+	if !got.CreatedAt.Equal(baseTime) || !equalTime(got.StartAt, &startAt) {
+		t.Errorf("Get() times = (%v, %v), want (%v, %v)", got.CreatedAt, got.StartAt, baseTime, startAt)
+	}
+	if got.RemindedAt != nil || got.ReopenedAt != nil || got.UpdatedAt != nil || got.CloseReason != recruit.CloseReasonNone {
+		t.Errorf("Get() = %+v, want unset optional fields", got)
+	}
+
+	byMessage, err := b.Recruits.GetByMessage(ctx, state.ChannelID, state.MessageID)
+	if err != nil {
+		t.Fatalf("GetByMessage() error = %v", err)
+	}
+	if byMessage.ID != id {
+		t.Errorf("GetByMessage().ID = %v, want %v", byMessage.ID, id)
+	}
+}
+
+func testGetNotFound(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	if _, err := b.Recruits.Get(ctx, 999); err == nil {
+		t.Error("Get() error = nil, want error")
+	}
+	if _, err := b.Recruits.GetByMessage(ctx, "channel-1", "missing"); !errors.Is(err, recruit.ErrRecruitNotFound) {
+		t.Errorf("GetByMessage() error = %v, want ErrRecruitNotFound", err)
+	}
+}
+
+func testGetByMessageArchived(t *testing.T, b Backend) {
+	ctx := context.Background()
+	state := newState("message-1")
+	create(t, b, state)
+
+	state.Archive()
+	if err := b.Recruits.Update(ctx, state); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if _, err := b.Recruits.GetByMessage(ctx, "channel-1", "message-1"); !errors.Is(err, recruit.ErrRecruitNotFound) {
+		t.Errorf("GetByMessage() of archived recruit error = %v, want ErrRecruitNotFound", err)
+	}
+	// 統計のためIDでは取得できる
+	if _, err := b.Recruits.Get(ctx, state.ID); err != nil {
+		t.Errorf("Get() of archived recruit error = %v", err)
+	}
+}
+
+func testUpdate(t *testing.T, b Backend) {
+	ctx := context.Background()
+	state := newState("message-1")
+	id := create(t, b, state)
+
+	remindedAt := baseTime.Add(time.Minute)
+	reopenedAt := baseTime.Add(2 * time.Minute)
+	state.MaxCapacity = 5
+	state.Status = recruit.RecruitStatusClosed
+	state.CloseReason = recruit.CloseReasonManual
+	state.RemindedAt = &remindedAt
+	state.ReopenedAt = &reopenedAt
+	state.Title = "変更後"
+	if err := b.Recruits.Update(ctx, state); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	got, err := b.Recruits.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.MaxCapacity != 5 || got.Status != recruit.RecruitStatusClosed ||
+		got.CloseReason != recruit.CloseReasonManual || got.Title != "変更後" {
+		t.Errorf("Get() = %+v, want updated state", got)
+	}
+	if !equalTime(got.RemindedAt, &remindedAt) || !equalTime(got.ReopenedAt, &reopenedAt) {
+		t.Errorf("Get() times = (%v, %v), want (%v, %v)", got.RemindedAt, got.ReopenedAt, remindedAt, reopenedAt)
+	}
+	if got.UpdatedAt == nil {
+		t.Error("Get().UpdatedAt = nil, want set")
+	}
+}
+
+func testUpdateNotFound(t *testing.T, b Backend) {
+	state := newState("message-1")
+	state.ID = 999
+
+	if err := b.Recruits.Update(context.Background(), state); err == nil {
+		t.Error("Update() error = nil, want error")
+	}
+}
+
+func testDelete(t *testing.T, b Backend) {
+	ctx := context.Background()
+	id := create(t, b, newState("message-1"))
+	if err := b.Participants.Upsert(ctx, id, "user-1", recruit.ParticipantStatusJoined); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+
+	if err := b.Recruits.Delete(ctx, id); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := b.Recruits.Get(ctx, id); err == nil {
+		t.Error("Get() after Delete() error = nil, want error")
+	}
+	participants, err := b.Participants.List(ctx, id)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(participants) != 0 {
+		t.Errorf("List() after Delete() = %v, want empty", participants)
+	}
+
+	if err := b.Recruits.Delete(ctx, id); err == nil {
+		t.Error("Delete() of deleted recruit error = nil, want error")
+	}
+}
+
+func testListScheduled(t *testing.T, b Backend) {
+	ctx := context.Background()
+	at := func(d time.Duration) *time.Time {
+		tm := baseTime.Add(d)
+		return &tm
+	}
+
+	later := newState("later")
+	later.StartAt = at(2 * time.Hour)
+	create(t, b, later)
+
+	soon := newState("soon")
+	soon.StartAt = at(30 * time.Minute)
+	create(t, b, soon)
+
+	past := newState("past")
+	past.StartAt = at(-time.Hour)
+	create(t, b, past)
+
+	create(t, b, newState("unscheduled"))
+
+	closed := newState("closed")
+	closed.StartAt = at(-time.Hour)
+	closed.Status = recruit.RecruitStatusClosed
+	create(t, b, closed)
+
+	got, err := b.Recruits.ListScheduled(ctx, baseTime.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ListScheduled() error = %v", err)
+	}
+	if !equalMessageIDs(got, "past", "soon") {
+		t.Errorf("ListScheduled() = %v, want [past soon]", messageIDs(got))
+	}
+}
+
+func testListExpired(t *testing.T, b Backend) {
+	ctx := context.Background()
+	at := func(d time.Duration) *time.Time {
+		tm := baseTime.Add(d)
+		return &tm
+	}
+
+	old := newState("old")
+	old.CreatedAt = baseTime.Add(-48 * time.Hour)
+	create(t, b, old)
+
+	// 作成日時は古いが開始時刻が新しい
+	startLater := newState("start-later")
+	startLater.CreatedAt = baseTime.Add(-48 * time.Hour)
+	startLater.StartAt = at(time.Hour)
+	create(t, b, startLater)
+
+	// 作成日時は古いが再開日時が新しい
+	reopened := newState("reopened")
+	reopened.CreatedAt = baseTime.Add(-48 * time.Hour)
+	create(t, b, reopened)
+	reopened.ReopenedAt = at(-time.Hour)
+	if err := b.Recruits.Update(ctx, reopened); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	closed := newState("closed")
+	closed.CreatedAt = baseTime.Add(-72 * time.Hour)
+	closed.Status = recruit.RecruitStatusClosed
+	create(t, b, closed)
+
+	started := newState("started")
+	started.CreatedAt = baseTime.Add(-36 * time.Hour)
+	started.StartAt = at(-30 * time.Hour)
+	started.Status = recruit.RecruitStatusStarted
+	create(t, b, started)
+
+	create(t, b, newState("fresh"))
+
+	got, err := b.Recruits.ListExpired(ctx, baseTime.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("ListExpired() error = %v", err)
+	}
+	if !equalMessageIDs(got, "old", "started") {
+		t.Errorf("ListExpired() = %v, want [old started]", messageIDs(got))
+	}
+}
+
+func testListOpened(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	first := newState("first")
+	first.CreatedAt = baseTime.Add(-time.Hour)
+	create(t, b, first)
+
+	otherChannel := newState("other-channel")
+	otherChannel.ChannelID = "channel-2"
+	create(t, b, otherChannel)
+
+	started := newState("started")
+	started.Status = recruit.RecruitStatusStarted
+	started.CreatedAt = baseTime.Add(time.Hour)
+	create(t, b, started)
+
+	closed := newState("closed")
+	closed.Status = recruit.RecruitStatusClosed
+	create(t, b, closed)
+
+	otherGuild := newState("other-guild")
+	otherGuild.GuildID = "guild-2"
+	create(t, b, otherGuild)
+
+	got, err := b.Recruits.ListOpened(ctx, "guild-1", "")
+	if err != nil {
+		t.Fatalf("ListOpened() error = %v", err)
+	}
+	if !equalMessageIDs(got, "first", "other-channel", "started") {
+		t.Errorf("ListOpened() = %v, want [first other-channel started]", messageIDs(got))
+	}
+
+	got, err = b.Recruits.ListOpened(ctx, "guild-1", "channel-2")
+	if err != nil {
+		t.Fatalf("ListOpened() error = %v", err)
+	}
+	if !equalMessageIDs(got, "other-channel") {
+		t.Errorf("ListOpened(channel-2) = %v, want [other-channel]", messageIDs(got))
+	}
+}
+
+func testUpsertParticipant(t *testing.T, b Backend) {
+	ctx := context.Background()
+	id := create(t, b, newState("message-1"))
+
+	got, err := b.Participants.FindByRecruitAndUser(ctx, id, "user-1")
+	if err != nil {
+		t.Fatalf("FindByRecruitAndUser() error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("FindByRecruitAndUser() = %+v, want nil", got)
+	}
+
+	if err := b.Participants.Upsert(ctx, id, "user-1", recruit.ParticipantStatusJoined); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	joined, err := b.Participants.FindByRecruitAndUser(ctx, id, "user-1")
+	if err != nil {
+		t.Fatalf("FindByRecruitAndUser() error = %v", err)
+	}
+	if joined == nil || joined.Status != recruit.ParticipantStatusJoined || joined.RecruitID != id {
+		t.Fatalf("FindByRecruitAndUser() = %+v, want joined", joined)
+	}
+
+	if err := b.Participants.Upsert(ctx, id, "user-1", recruit.ParticipantStatusCanceled); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	canceled, err := b.Participants.FindByRecruitAndUser(ctx, id, "user-1")
+	if err != nil {
+		t.Fatalf("FindByRecruitAndUser() error = %v", err)
+	}
+	if canceled.Status != recruit.ParticipantStatusCanceled {
+		t.Errorf("Status = %v, want %v", canceled.Status, recruit.ParticipantStatusCanceled)
+	}
+	// 状態を更新しても初回登録日時は変わらない
+	if !canceled.CreatedAt.Equal(joined.CreatedAt) || canceled.UpdatedAt == nil {
+		t.Errorf("CreatedAt = %v, UpdatedAt = %v, want CreatedAt %v and UpdatedAt set",
+			canceled.CreatedAt, canceled.UpdatedAt, joined.CreatedAt)
+	}
+}
+
+func testListParticipants(t *testing.T, b Backend) {
+	ctx := context.Background()
+	id := create(t, b, newState("message-1"))
+	otherID := create(t, b, newState("message-2"))
+
+	for _, userID := range []recruit.UserID{"user-2", "user-1", "user-3"} {
+		if err := b.Participants.Upsert(ctx, id, userID, recruit.ParticipantStatusJoined); err != nil {
+			t.Fatalf("Upsert() error = %v", err)
+		}
+		// 登録日時の精度が低いDBでも順序が決まるよう間隔を空ける
+		time.Sleep(time.Millisecond)
+	}
+	if err := b.Participants.Upsert(ctx, otherID, "user-4", recruit.ParticipantStatusJoined); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	// 状態を更新しても順序は変わらない
+	if err := b.Participants.Upsert(ctx, id, "user-2", recruit.ParticipantStatusDeclined); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+
+	got, err := b.Participants.List(ctx, id)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	want := []recruit.UserID{"user-2", "user-1", "user-3"}
+	if len(got) != len(want) {
+		t.Fatalf("List() = %+v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].UserID != want[i] {
+			t.Errorf("List()[%d].UserID = %v, want %v", i, got[i].UserID, want[i])
+		}
+	}
+	if got[0].Status != recruit.ParticipantStatusDeclined {
+		t.Errorf("List()[0].Status = %v, want %v", got[0].Status, recruit.ParticipantStatusDeclined)
+	}
+}
+
+func testDeleteAllParticipants(t *testing.T, b Backend) {
+	ctx := context.Background()
+	id := create(t, b, newState("message-1"))
+	for _, userID := range []recruit.UserID{"user-1", "user-2"} {
+		if err := b.Participants.Upsert(ctx, id, userID, recruit.ParticipantStatusJoined); err != nil {
+			t.Fatalf("Upsert() error = %v", err)
+		}
+	}
+
+	if err := b.Participants.DeleteAll(ctx, id); err != nil {
+		t.Fatalf("DeleteAll() error = %v", err)
+	}
+	got, err := b.Participants.List(ctx, id)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("List() after DeleteAll() = %+v, want empty", got)
+	}
+
+	if err := b.Participants.DeleteAll(ctx, id); err == nil {
+		t.Error("DeleteAll() without participants error = nil, want error")
+	}
+}
+
+func testUnitOfWorkCommit(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	var id recruit.RecruitID
+	err := b.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		var err error
+		id, err = b.Recruits.Create(ctx, newState("message-1"))
+		if err != nil {
+			return err
+		}
+		return b.Participants.Upsert(ctx, id, "user-1", recruit.ParticipantStatusJoined)
+	})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	if _, err := b.Recruits.Get(ctx, id); err != nil {
+		t.Errorf("Get() after commit error = %v", err)
+	}
+	participants, err := b.Participants.List(ctx, id)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(participants) != 1 {
+		t.Errorf("List() after commit = %+v, want 1 participant", participants)
+	}
+}
+
+func testUnitOfWorkRollback(t *testing.T, b Backend) {
+	ctx := context.Background()
+	testErr := errors.New("test error")
+
+	err := b.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		if _, err := b.Recruits.Create(ctx, newState("message-1")); err != nil {
+			return err
+		}
+		return testErr
+	})
+	if !errors.Is(err, testErr) {
+		t.Fatalf("Do() error = %v, want %v", err, testErr)
+	}
+
+	if _, err := b.Recruits.GetByMessage(ctx, "channel-1", "message-1"); err == nil {
+		t.Error("GetByMessage() after rollback error = nil, want error")
+	}
+}
@@ -2,17 +2,29 @@ package recruit
 
 import (
 	"context"
+	"time"
 )
 
 type RecruitRepository interface {
 	Get(ctx context.Context, id RecruitID) (*RecruitState, error)
+	// GetByMessage はメッセージの募集を返す
+	// 存在しない場合と作成者が削除した募集の場合はErrRecruitNotFoundを返す
 	GetByMessage(ctx context.Context, channelID ChannelID, messageID MessageID) (*RecruitState, error)
 	Create(ctx context.Context, recruit *RecruitState) (RecruitID, error)
 	Update(ctx context.Context, recruit *RecruitState) error
 	Delete(ctx context.Context, id RecruitID) error
+	// ListScheduled は開始時刻がuntil以前に設定された募集中の募集を返す
+	ListScheduled(ctx context.Context, until time.Time) ([]*RecruitState, error)
+	// ListExpired は作成日時、開始時刻、再開日時のうち最も遅いものがbefore以前の締め切られていない募集を返す
+	ListExpired(ctx context.Context, before time.Time) ([]*RecruitState, error)
+	// ListOpened はギルド内の締め切られていない募集を作成日時順に返す
+	// channelIDが空の場合はギルド内の全チャンネルを対象にする
+	ListOpened(ctx context.Context, guildID GuildID, channelID ChannelID) ([]*RecruitState, error)
 }
 
 type ParticipantRepository interface {
+	// Upsert は参加状態を登録する
+	// 参加状態が変わる場合は、統計のため変更前の状態とともに履歴に記録する
 	Upsert(ctx context.Context, recruitID RecruitID, userID UserID, status ParticipantStatus) error
 	FindByRecruitAndUser(ctx context.Context, recruitID RecruitID, userID UserID) (*Participant, error)
 	List(ctx context.Context, recruitID RecruitID) ([]Participant, error)
@@ -0,0 +1,100 @@
+package recruit
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 全角で入力された区切り文字を半角に揃える
+var startAtReplacer = strings.NewReplacer("：", ":", "／", "/", "　", " ")
+
+// ParseStartAt は "21:30" や "明日 20:00"、"10/20 21:00" 形式の開始時刻をnow基準で解釈する
+// 時刻のみ指定された場合は直近の未来の時刻として扱う
+func ParseStartAt(input string, now time.Time) (time.Time, error) {
+	fields := strings.Fields(startAtReplacer.Replace(input))
+
+	var datePart, clockPart string
+	switch len(fields) {
+	case 1:
+		clockPart = fields[0]
+	case 2:
+		datePart, clockPart = fields[0], fields[1]
+	default:
+		return time.Time{}, ErrInvalidStartAt
+	}
+
+	hour, minute, err := parseClock(clockPart)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	year, month, day := now.Date()
+	switch datePart {
+	case "", "今日":
+	case "明日":
+		day += 1
+	case "明後日":
+		day += 2
+	default:
+		year, month, day, err = parseDate(datePart, year)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	startAt := time.Date(year, month, day, hour, minute, 0, 0, now.Location())
+	if datePart == "" && startAt.Before(now) {
+		startAt = startAt.AddDate(0, 0, 1)
+	}
+	if startAt.Before(now) {
+		return time.Time{}, ErrStartAtInPast
+	}
+	return startAt, nil
+}
+
+func parseClock(s string) (int, int, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return 0, 0, ErrInvalidStartAt
+	}
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, 0, ErrInvalidStartAt
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || len(parts[1]) != 2 || minute < 0 || minute > 59 {
+		return 0, 0, ErrInvalidStartAt
+	}
+	return hour, minute, nil
+}
+
+func parseDate(s string, currentYear int) (int, time.Month, int, error) {
+	parts := strings.FieldsFunc(s, func(r rune) bool { return r == '/' || r == '-' })
+
+	nums := make([]int, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, 0, 0, ErrInvalidStartAt
+		}
+		nums = append(nums, n)
+	}
+
+	year := currentYear
+	switch len(nums) {
+	case 2:
+	case 3:
+		year, nums = nums[0], nums[1:]
+	default:
+		return 0, 0, 0, ErrInvalidStartAt
+	}
+
+	month, day := time.Month(nums[0]), nums[1]
+	// 2/30のような存在しない日付は正規化されるため、値が変わっていないかで検証する
+	normalized := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if normalized.Month() != month || normalized.Day() != day {
+		return 0, 0, 0, ErrInvalidStartAt
+	}
+	return year, month, day, nil
+}
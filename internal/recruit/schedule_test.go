@@ -0,0 +1,197 @@
+package recruit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseStartAt(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2025, 10, 16, 18, 0, 0, 0, jst)
+
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Time
+		wantErr error
+	}{
+		{
+			name:  "時刻のみ指定した場合は当日",
+			input: "21:30",
+			want:  time.Date(2025, 10, 16, 21, 30, 0, 0, jst),
+		},
+		{
+			name:  "過ぎた時刻のみ指定した場合は翌日",
+			input: "9:05",
+			want:  time.Date(2025, 10, 17, 9, 5, 0, 0, jst),
+		},
+		{
+			name:  "明日を指定",
+			input: "明日 20:00",
+			want:  time.Date(2025, 10, 17, 20, 0, 0, 0, jst),
+		},
+		{
+			name:  "明後日を指定",
+			input: "明後日 20:00",
+			want:  time.Date(2025, 10, 18, 20, 0, 0, 0, jst),
+		},
+		{
+			name:    "全角数字は未対応",
+			input:   "明日　２０：００",
+			wantErr: ErrInvalidStartAt,
+		},
+		{
+			name:  "全角のコロンとスペース",
+			input: "明日　20：00",
+			want:  time.Date(2025, 10, 17, 20, 0, 0, 0, jst),
+		},
+		{
+			name:  "月日を指定",
+			input: "10/20 21:00",
+			want:  time.Date(2025, 10, 20, 21, 0, 0, 0, jst),
+		},
+		{
+			name:  "年月日を指定",
+			input: "2026-01-02 09:00",
+			want:  time.Date(2026, 1, 2, 9, 0, 0, 0, jst),
+		},
+		{
+			name:    "今日の過ぎた時刻はエラー",
+			input:   "今日 10:00",
+			wantErr: ErrStartAtInPast,
+		},
+		{
+			name:    "過去の日付はエラー",
+			input:   "10/1 21:00",
+			wantErr: ErrStartAtInPast,
+		},
+		{
+			name:    "存在しない日付はエラー",
+			input:   "2/30 21:00",
+			wantErr: ErrInvalidStartAt,
+		},
+		{
+			name:    "範囲外の時刻はエラー",
+			input:   "24:00",
+			wantErr: ErrInvalidStartAt,
+		},
+		{
+			name:    "分が1桁の場合はエラー",
+			input:   "21:5",
+			wantErr: ErrInvalidStartAt,
+		},
+		{
+			name:    "不正な文字列はエラー",
+			input:   "そのうち",
+			wantErr: ErrInvalidStartAt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStartAt(tt.input, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseStartAt(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !got.Equal(tt.want) {
+				t.Errorf("ParseStartAt(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecruitState_ShouldRemind(t *testing.T) {
+	now := time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC)
+	remindBefore := 10 * time.Minute
+
+	inFive := now.Add(5 * time.Minute)
+	inTen := now.Add(10 * time.Minute)
+	inHour := now.Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		state RecruitState
+		want  bool
+	}{
+		{
+			name:  "開始時刻の10分前を過ぎていればリマインド対象",
+			state: RecruitState{Status: RecruitStatusOpened, StartAt: &inFive},
+			want:  true,
+		},
+		{
+			name:  "ちょうど10分前ならリマインド対象",
+			state: RecruitState{Status: RecruitStatusOpened, StartAt: &inTen},
+			want:  true,
+		},
+		{
+			name:  "10分前より前ならリマインド対象外",
+			state: RecruitState{Status: RecruitStatusOpened, StartAt: &inHour},
+			want:  false,
+		},
+		{
+			name:  "リマインド済みなら対象外",
+			state: RecruitState{Status: RecruitStatusOpened, StartAt: &inFive, RemindedAt: &now},
+			want:  false,
+		},
+		{
+			name:  "開始時刻未設定なら対象外",
+			state: RecruitState{Status: RecruitStatusOpened},
+			want:  false,
+		},
+		{
+			name:  "募集中でなければ対象外",
+			state: RecruitState{Status: RecruitStatusClosed, StartAt: &inFive},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.state.ShouldRemind(now, remindBefore); got != tt.want {
+				t.Errorf("ShouldRemind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecruitState_ShouldStart(t *testing.T) {
+	now := time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	tests := []struct {
+		name  string
+		state RecruitState
+		want  bool
+	}{
+		{
+			name:  "開始時刻を過ぎていれば開始対象",
+			state: RecruitState{Status: RecruitStatusOpened, StartAt: &past},
+			want:  true,
+		},
+		{
+			name:  "開始時刻ちょうどなら開始対象",
+			state: RecruitState{Status: RecruitStatusOpened, StartAt: &now},
+			want:  true,
+		},
+		{
+			name:  "開始時刻前なら対象外",
+			state: RecruitState{Status: RecruitStatusOpened, StartAt: &future},
+			want:  false,
+		},
+		{
+			name:  "開始済みなら対象外",
+			state: RecruitState{Status: RecruitStatusStarted, StartAt: &past},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.state.ShouldStart(now); got != tt.want {
+				t.Errorf("ShouldStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package recruit
 
 import (
+	"at-bot/internal/keylock"
 	"at-bot/internal/uow"
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 )
 
@@ -11,6 +14,11 @@ type RecruitUsecase struct {
 	recruitRepos     RecruitRepository
 	participantRepos ParticipantRepository
 	uow              uow.UnitOfWork
+	// locks は同じ募集への変更を1つずつ行うための募集メッセージごとのロック
+	locks *keylock.Mutex
+	// viewLocks は同じ募集の最新の状態を使用する処理を1つずつ行うための募集メッセージごとのロック
+	// 変更とは別のロックのため、メッセージの再描画を待たずに次の変更を行える
+	viewLocks *keylock.Mutex
 }
 
 func NewRecruitUsecase(
@@ -22,6 +30,60 @@ func NewRecruitUsecase(
 		recruitRepos:     recruitRepos,
 		participantRepos: participantRepos,
 		uow:              uow,
+		locks:            keylock.New(),
+		viewLocks:        keylock.New(),
+	}
+}
+
+// lockKey は募集メッセージごとのロックのキーを返す
+func lockKey(channelID ChannelID, messageID MessageID) string {
+	return string(channelID) + "/" + string(messageID)
+}
+
+// doLocked は募集メッセージごとのロックを取得してからトランザクションを開始する
+// 同時に参加した場合に、双方が満員でないと判定して定員を超えることを防ぐ
+// トランザクションの中でロックを待つとデータベースのロックと待ち合って停止するため、必ずトランザクションの外で取得する
+func (uc *RecruitUsecase) doLocked(
+	ctx context.Context,
+	channelID ChannelID,
+	messageID MessageID,
+	fn func(ctx context.Context) error,
+) error {
+	unlock, err := uc.locks.Lock(ctx, lockKey(channelID, messageID))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return uc.uow.Do(ctx, fn)
+}
+
+// OpenOption は募集作成時の任意項目を設定する
+type OpenOption func(*RecruitState)
+
+func WithStartAt(startAt time.Time) OpenOption {
+	return func(state *RecruitState) {
+		state.StartAt = &startAt
+	}
+}
+
+func WithTitle(title string) OpenOption {
+	return func(state *RecruitState) {
+		state.Title = title
+	}
+}
+
+func WithDescription(description string) OpenOption {
+	return func(state *RecruitState) {
+		state.Description = description
+	}
+}
+
+// WithRole は対象ロールを設定する。restrictedの場合は対象ロールを持つメンバーのみ参加できる
+func WithRole(roleID RoleID, restricted bool) OpenOption {
+	return func(state *RecruitState) {
+		state.RoleID = roleID
+		state.RoleRestricted = restricted
 	}
 }
 
@@ -32,6 +94,7 @@ func (uc *RecruitUsecase) Open(
 	messageID MessageID,
 	maxCapacity int,
 	authorID UserID,
+	opts ...OpenOption,
 ) (*RecruitView, error) {
 	var view *RecruitView
 	err := uc.uow.Do(ctx, func(ctx context.Context) error {
@@ -44,6 +107,9 @@ func (uc *RecruitUsecase) Open(
 			CreatedAt:   time.Now(),
 			Status:      RecruitStatusOpened,
 		}
+		for _, opt := range opts {
+			opt(state)
+		}
 
 		id, err := uc.recruitRepos.Create(ctx, state)
 		if err != nil {
@@ -65,13 +131,15 @@ func (uc *RecruitUsecase) Open(
 	return view, err
 }
 
+// Join は募集に参加する。actorRolesは対象ロールが限定されている募集の参加可否の判定に使用する
 func (uc *RecruitUsecase) Join(
 	ctx context.Context,
 	channelID ChannelID,
 	messageID MessageID,
 	actorID UserID,
+	actorRoles []RoleID,
 ) (*ParticipantStatusChangeResult, error) {
-	return uc.updateParticipantStatus(ctx, channelID, messageID, actorID, ParticipantStatusJoined)
+	return uc.updateParticipantStatus(ctx, channelID, messageID, actorID, actorRoles, ParticipantStatusJoined)
 }
 
 func (uc *RecruitUsecase) Decline(
@@ -80,7 +148,16 @@ func (uc *RecruitUsecase) Decline(
 	messageID MessageID,
 	actorID UserID,
 ) (*ParticipantStatusChangeResult, error) {
-	return uc.updateParticipantStatus(ctx, channelID, messageID, actorID, ParticipantStatusDeclined)
+	return uc.updateParticipantStatus(ctx, channelID, messageID, actorID, nil, ParticipantStatusDeclined)
+}
+
+func (uc *RecruitUsecase) Tentative(
+	ctx context.Context,
+	channelID ChannelID,
+	messageID MessageID,
+	actorID UserID,
+) (*ParticipantStatusChangeResult, error) {
+	return uc.updateParticipantStatus(ctx, channelID, messageID, actorID, nil, ParticipantStatusTentative)
 }
 
 func (uc *RecruitUsecase) updateParticipantStatus(
@@ -88,10 +165,11 @@ func (uc *RecruitUsecase) updateParticipantStatus(
 	channelID ChannelID,
 	messageID MessageID,
 	actorID UserID,
+	actorRoles []RoleID,
 	status ParticipantStatus,
 ) (*ParticipantStatusChangeResult, error) {
 	var result *ParticipantStatusChangeResult
-	err := uc.uow.Do(ctx, func(ctx context.Context) error {
+	err := uc.doLocked(ctx, channelID, messageID, func(ctx context.Context) error {
 		state, err := uc.recruitRepos.GetByMessage(ctx, channelID, messageID)
 		if err != nil {
 			return err
@@ -102,6 +180,17 @@ func (uc *RecruitUsecase) updateParticipantStatus(
 			return ErrAuthorCannotJoin
 		}
 
+		// 締め切られた募集の参加状態は変更不可
+		if state.Status == RecruitStatusClosed {
+			return ErrRecruitClosed
+		}
+
+		// 対象ロールが限定されている場合はロールを持つメンバーのみ参加可能
+		// 辞退やキャンセルはロールに関わらず行える
+		if status == ParticipantStatusJoined && !state.CanJoin(actorRoles) {
+			return ErrRoleRequired
+		}
+
 		participant, err := uc.participantRepos.FindByRecruitAndUser(ctx, state.ID, actorID)
 		if err != nil {
 			return err
@@ -114,12 +203,27 @@ func (uc *RecruitUsecase) updateParticipantStatus(
 		}
 
 		// すでに同状態で登録済みの場合は独自エラー
-		if participant != nil && participant.Status == status {
-			switch status {
-			case ParticipantStatusJoined:
+		// キャンセル待ち中の参加も参加済みとして扱う
+		if participant != nil {
+			switch {
+			case status == ParticipantStatusJoined &&
+				(participant.Status == ParticipantStatusJoined || participant.Status == ParticipantStatusWaitlisted):
 				return ErrAlreadyJoined
-			case ParticipantStatusDeclined:
+			case status == ParticipantStatusDeclined && participant.Status == ParticipantStatusDeclined:
 				return ErrAlreadyDeclined
+			case status == ParticipantStatusTentative && participant.Status == ParticipantStatusTentative:
+				return ErrAlreadyTentative
+			}
+		}
+
+		// 満員の場合はキャンセル待ちとして登録
+		if status == ParticipantStatusJoined {
+			view, err := uc.buildRecruitView(ctx, state)
+			if err != nil {
+				return err
+			}
+			if view.IsFull() {
+				status = ParticipantStatusWaitlisted
 			}
 		}
 
@@ -129,6 +233,15 @@ func (uc *RecruitUsecase) updateParticipantStatus(
 			return err
 		}
 
+		// 参加者が抜けた場合はキャンセル待ちを繰り上げる
+		var promotedUsers []UserID
+		if previousStatus != nil && *previousStatus == ParticipantStatusJoined {
+			promotedUsers, err = uc.promoteWaitlisted(ctx, state)
+			if err != nil {
+				return err
+			}
+		}
+
 		// 更新後のViewを構築
 		view, err := uc.buildRecruitView(ctx, state)
 		if err != nil {
@@ -138,12 +251,34 @@ func (uc *RecruitUsecase) updateParticipantStatus(
 		result = &ParticipantStatusChangeResult{
 			CurrentView:    view,
 			PreviousStatus: previousStatus,
+			CurrentStatus:  status,
+			PromotedUsers:  promotedUsers,
 		}
 		return nil
 	})
 	return result, err
 }
 
+// promoteWaitlisted は空き枠の分だけキャンセル待ちを登録順に参加へ繰り上げる
+func (uc *RecruitUsecase) promoteWaitlisted(
+	ctx context.Context,
+	state *RecruitState,
+) ([]UserID, error) {
+	view, err := uc.buildRecruitView(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+
+	count := min(view.RemainingSlots(), len(view.WaitlistedUsers))
+	promoted := view.WaitlistedUsers[:count]
+	for _, userID := range promoted {
+		if err := uc.participantRepos.Upsert(ctx, state.ID, userID, ParticipantStatusJoined); err != nil {
+			return nil, err
+		}
+	}
+	return promoted, nil
+}
+
 func (uc *RecruitUsecase) buildRecruitView(
 	ctx context.Context,
 	state *RecruitState,
@@ -153,20 +288,36 @@ func (uc *RecruitUsecase) buildRecruitView(
 		return nil, err
 	}
 
-	var joinedUsers, declinedUsers []UserID
+	var joinedUsers, declinedUsers, tentativeUsers []UserID
+	var waitlisted []Participant
 	for _, p := range participants {
 		switch p.Status {
 		case ParticipantStatusJoined:
 			joinedUsers = append(joinedUsers, p.UserID)
 		case ParticipantStatusDeclined:
 			declinedUsers = append(declinedUsers, p.UserID)
+		case ParticipantStatusWaitlisted:
+			waitlisted = append(waitlisted, p)
+		case ParticipantStatusTentative:
+			tentativeUsers = append(tentativeUsers, p.UserID)
 		}
 	}
 
+	// キャンセル待ちは初回参加日時ではなく、キャンセル待ちになった順に並べる
+	sort.SliceStable(waitlisted, func(i, j int) bool {
+		return waitlisted[i].StatusChangedAt().Before(waitlisted[j].StatusChangedAt())
+	})
+	var waitlistedUsers []UserID
+	for _, p := range waitlisted {
+		waitlistedUsers = append(waitlistedUsers, p.UserID)
+	}
+
 	return &RecruitView{
-		Meta:          state,
-		JoinedUsers:   joinedUsers,
-		DeclinedUsers: declinedUsers,
+		Meta:            state,
+		JoinedUsers:     joinedUsers,
+		DeclinedUsers:   declinedUsers,
+		WaitlistedUsers: waitlistedUsers,
+		TentativeUsers:  tentativeUsers,
 	}, nil
 }
 
@@ -176,30 +327,409 @@ func (uc *RecruitUsecase) Cancel(
 	messageID MessageID,
 	actorID UserID,
 ) (*ParticipantStatusChangeResult, error) {
-	return uc.updateParticipantStatus(ctx, channelID, messageID, actorID, ParticipantStatusCanceled)
+	return uc.updateParticipantStatus(ctx, channelID, messageID, actorID, nil, ParticipantStatusCanceled)
 }
 
+// Close は募集を締め切る。参加者の履歴は保持する
 func (uc *RecruitUsecase) Close(
 	ctx context.Context,
 	channelID ChannelID,
 	messageID MessageID,
 	actorID UserID,
+) (*RecruitView, error) {
+	return uc.changeRecruitStatus(ctx, channelID, messageID, actorID, func(state *RecruitState) error {
+		return state.Close()
+	})
+}
+
+// Reopen は締め切った募集を再開する
+func (uc *RecruitUsecase) Reopen(
+	ctx context.Context,
+	channelID ChannelID,
+	messageID MessageID,
+	actorID UserID,
+) (*RecruitView, error) {
+	return uc.changeRecruitStatus(ctx, channelID, messageID, actorID, func(state *RecruitState) error {
+		return state.Reopen(time.Now())
+	})
+}
+
+// changeRecruitStatus は作成者による募集の状態変更を行い、更新後のViewを返す
+func (uc *RecruitUsecase) changeRecruitStatus(
+	ctx context.Context,
+	channelID ChannelID,
+	messageID MessageID,
+	actorID UserID,
+	change func(state *RecruitState) error,
+) (*RecruitView, error) {
+	var view *RecruitView
+	err := uc.doLocked(ctx, channelID, messageID, func(ctx context.Context) error {
+		state, err := uc.getOwnedRecruit(ctx, channelID, messageID, actorID)
+		if err != nil {
+			return err
+		}
+
+		if err := change(state); err != nil {
+			return err
+		}
+
+		if err := uc.recruitRepos.Update(ctx, state); err != nil {
+			return err
+		}
+
+		view, err = uc.buildRecruitView(ctx, state)
+		return err
+	})
+	return view, err
+}
+
+// getOwnedRecruit は募集を取得し、操作者が作成者であることを確認する
+func (uc *RecruitUsecase) getOwnedRecruit(
+	ctx context.Context,
+	channelID ChannelID,
+	messageID MessageID,
+	actorID UserID,
+) (*RecruitState, error) {
+	state, err := uc.recruitRepos.GetByMessage(ctx, channelID, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if state.AuthorID != actorID {
+		return nil, ErrNotAuthor
+	}
+	return state, nil
+}
+
+// ChangeCapacity は募集人数を変更する
+// 増やした場合は空いた枠の分だけキャンセル待ちを繰り上げる
+func (uc *RecruitUsecase) ChangeCapacity(
+	ctx context.Context,
+	channelID ChannelID,
+	messageID MessageID,
+	actorID UserID,
+	maxCapacity int,
+) (*CapacityChangeResult, error) {
+	if maxCapacity < 1 {
+		return nil, ErrInvalidCapacity
+	}
+
+	var result *CapacityChangeResult
+	err := uc.doLocked(ctx, channelID, messageID, func(ctx context.Context) error {
+		state, err := uc.getOwnedRecruit(ctx, channelID, messageID, actorID)
+		if err != nil {
+			return err
+		}
+
+		if state.Status == RecruitStatusClosed {
+			return ErrRecruitClosed
+		}
+
+		// 参加済みのユーザーを外すことになる変更は不可
+		view, err := uc.buildRecruitView(ctx, state)
+		if err != nil {
+			return err
+		}
+		if maxCapacity < len(view.JoinedUsers)-1 {
+			return ErrCapacityBelowJoined
+		}
+
+		state.MaxCapacity = maxCapacity
+		if err := uc.recruitRepos.Update(ctx, state); err != nil {
+			return err
+		}
+
+		promotedUsers, err := uc.promoteWaitlisted(ctx, state)
+		if err != nil {
+			return err
+		}
+
+		view, err = uc.buildRecruitView(ctx, state)
+		if err != nil {
+			return err
+		}
+
+		result = &CapacityChangeResult{
+			CurrentView:   view,
+			PromotedUsers: promotedUsers,
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Kick は作成者の操作として指定したユーザーを募集から外す
+// 参加者を外した場合はキャンセル待ちを繰り上げる
+func (uc *RecruitUsecase) Kick(
+	ctx context.Context,
+	channelID ChannelID,
+	messageID MessageID,
+	actorID UserID,
+	targetID UserID,
+) (*ParticipantStatusChangeResult, error) {
+	var result *ParticipantStatusChangeResult
+	err := uc.doLocked(ctx, channelID, messageID, func(ctx context.Context) error {
+		state, err := uc.getOwnedRecruit(ctx, channelID, messageID, actorID)
+		if err != nil {
+			return err
+		}
+
+		if state.Status == RecruitStatusClosed {
+			return ErrRecruitClosed
+		}
+
+		if state.AuthorID == targetID {
+			return ErrCannotKickAuthor
+		}
+
+		participant, err := uc.participantRepos.FindByRecruitAndUser(ctx, state.ID, targetID)
+		if err != nil {
+			return err
+		}
+
+		// 辞退済み/キャンセル済み/外し済みのユーザーは外す対象にならない
+		if participant == nil ||
+			participant.Status == ParticipantStatusDeclined ||
+			participant.Status == ParticipantStatusCanceled ||
+			participant.Status == ParticipantStatusKicked {
+			return ErrParticipantNotFound
+		}
+		previousStatus := participant.Status
+
+		err = uc.participantRepos.Upsert(ctx, state.ID, targetID, ParticipantStatusKicked)
+		if err != nil {
+			return err
+		}
+
+		var promotedUsers []UserID
+		if previousStatus == ParticipantStatusJoined {
+			promotedUsers, err = uc.promoteWaitlisted(ctx, state)
+			if err != nil {
+				return err
+			}
+		}
+
+		view, err := uc.buildRecruitView(ctx, state)
+		if err != nil {
+			return err
+		}
+
+		result = &ParticipantStatusChangeResult{
+			CurrentView:    view,
+			PreviousStatus: &previousStatus,
+			CurrentStatus:  ParticipantStatusKicked,
+			PromotedUsers:  promotedUsers,
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Delete は作成者の操作として募集を削除する
+// 参加履歴の統計に使用するため、募集と参加者の記録は物理削除せずアーカイブする
+func (uc *RecruitUsecase) Delete(
+	ctx context.Context,
+	channelID ChannelID,
+	messageID MessageID,
+	actorID UserID,
 ) error {
-	return uc.uow.Do(ctx, func(ctx context.Context) error {
+	return uc.doLocked(ctx, channelID, messageID, func(ctx context.Context) error {
+		state, err := uc.getOwnedRecruit(ctx, channelID, messageID, actorID)
+		if err != nil {
+			return err
+		}
+
+		state.Archive()
+		return uc.recruitRepos.Update(ctx, state)
+	})
+}
+
+// Get は募集の現在のViewを返す
+func (uc *RecruitUsecase) Get(
+	ctx context.Context,
+	channelID ChannelID,
+	messageID MessageID,
+) (*RecruitView, error) {
+	var view *RecruitView
+	err := uc.uow.Do(ctx, func(ctx context.Context) error {
 		state, err := uc.recruitRepos.GetByMessage(ctx, channelID, messageID)
 		if err != nil {
 			return err
 		}
 
-		if state.AuthorID != actorID {
-			return fmt.Errorf("作成者以外は募集を削除することはできません。")
+		view, err = uc.buildRecruitView(ctx, state)
+		return err
+	})
+	return view, err
+}
+
+// WithLatestView は募集の最新のViewでfnを呼び出す
+// 同じ募集に対しては1つずつ呼び出すため、先に取得した古い状態でメッセージを上書きすることを防げる
+// 最新のViewを取得できない場合はfnを呼び出さずにエラーを返す
+func (uc *RecruitUsecase) WithLatestView(
+	ctx context.Context,
+	channelID ChannelID,
+	messageID MessageID,
+	fn func(view *RecruitView),
+) error {
+	unlock, err := uc.viewLocks.Lock(ctx, lockKey(channelID, messageID))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	view, err := uc.Get(ctx, channelID, messageID)
+	if err != nil {
+		return err
+	}
+	fn(view)
+	return nil
+}
+
+// GetOwned は作成者の操作として募集の現在のViewを返す
+func (uc *RecruitUsecase) GetOwned(
+	ctx context.Context,
+	channelID ChannelID,
+	messageID MessageID,
+	actorID UserID,
+) (*RecruitView, error) {
+	var view *RecruitView
+	err := uc.uow.Do(ctx, func(ctx context.Context) error {
+		state, err := uc.getOwnedRecruit(ctx, channelID, messageID, actorID)
+		if err != nil {
+			return err
 		}
 
-		err = uc.recruitRepos.Delete(ctx, state.ID)
+		view, err = uc.buildRecruitView(ctx, state)
+		return err
+	})
+	return view, err
+}
+
+// ListOpened はギルド内(channelID指定時はチャンネル内)の締め切られていない募集のViewを返す
+func (uc *RecruitUsecase) ListOpened(
+	ctx context.Context,
+	guildID GuildID,
+	channelID ChannelID,
+) ([]*RecruitView, error) {
+	var views []*RecruitView
+	err := uc.uow.Do(ctx, func(ctx context.Context) error {
+		states, err := uc.recruitRepos.ListOpened(ctx, guildID, channelID)
 		if err != nil {
 			return err
 		}
 
+		for _, state := range states {
+			view, err := uc.buildRecruitView(ctx, state)
+			if err != nil {
+				return err
+			}
+			views = append(views, view)
+		}
 		return nil
 	})
+	return views, err
+}
+
+// Remind は開始時刻のremindBefore前に到達した募集をリマインド済みにし、通知対象のViewを返す
+func (uc *RecruitUsecase) Remind(
+	ctx context.Context,
+	now time.Time,
+	remindBefore time.Duration,
+) ([]*RecruitView, error) {
+	candidates, err := uc.recruitRepos.ListScheduled(ctx, now.Add(remindBefore))
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.updateEach(ctx, candidates, func(state *RecruitState) bool {
+		if !state.ShouldRemind(now, remindBefore) {
+			return false
+		}
+		state.RemindedAt = &now
+		return true
+	})
+}
+
+// Start は開始時刻を過ぎた募集を開始済みにし、更新後のViewを返す
+func (uc *RecruitUsecase) Start(
+	ctx context.Context,
+	now time.Time,
+) ([]*RecruitView, error) {
+	candidates, err := uc.recruitRepos.ListScheduled(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.updateEach(ctx, candidates, func(state *RecruitState) bool {
+		if !state.ShouldStart(now) {
+			return false
+		}
+		state.Status = RecruitStatusStarted
+		return true
+	})
+}
+
+// Expire は有効期限ttlを過ぎた募集を締め切り、更新後のViewを返す
+// 期限は保存済みの日時から都度計算するため、BOTの再起動を挟んでも判定は変わらない
+func (uc *RecruitUsecase) Expire(
+	ctx context.Context,
+	now time.Time,
+	ttl time.Duration,
+) ([]*RecruitView, error) {
+	candidates, err := uc.recruitRepos.ListExpired(ctx, now.Add(-ttl))
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.updateEach(ctx, candidates, func(state *RecruitState) bool {
+		if !state.IsExpired(now, ttl) {
+			return false
+		}
+		state.Expire()
+		return true
+	})
+}
+
+// updateEach はcandidatesの募集ごとにロックを取得し、最新の状態にchangeを適用して保存する
+// changeがfalseを返した募集は更新しない。1件の失敗で他の募集の処理は止めず、エラーをまとめて返す
+func (uc *RecruitUsecase) updateEach(
+	ctx context.Context,
+	candidates []*RecruitState,
+	change func(state *RecruitState) bool,
+) ([]*RecruitView, error) {
+	var views []*RecruitView
+	var errs []error
+	for _, candidate := range candidates {
+		var view *RecruitView
+		err := uc.doLocked(ctx, candidate.ChannelID, candidate.MessageID, func(ctx context.Context) error {
+			// 一覧の取得後に参加や締め切りで変更されている場合があるため、ロックの中で取得し直して判定する
+			state, err := uc.recruitRepos.GetByMessage(ctx, candidate.ChannelID, candidate.MessageID)
+			if err != nil {
+				return err
+			}
+			if !change(state) {
+				return nil
+			}
+
+			if err := uc.recruitRepos.Update(ctx, state); err != nil {
+				return err
+			}
+
+			view, err = uc.buildRecruitView(ctx, state)
+			return err
+		})
+		// 一覧の取得後に削除された募集は対象外
+		if errors.Is(err, ErrRecruitNotFound) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to update recruit %d: %w", candidate.ID, err))
+			continue
+		}
+		if view != nil {
+			views = append(views, view)
+		}
+	}
+	return views, errors.Join(errs...)
 }
@@ -0,0 +1,327 @@
+package recruit_test
+
+import (
+	"at-bot/internal/db/inmemory"
+	"at-bot/internal/recruit"
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// インメモリのリポジトリを使用して、複数の操作を通した募集の状態を検証する
+
+func newInmemoryUsecase(participants func(recruit.ParticipantRepository) recruit.ParticipantRepository) *recruit.RecruitUsecase {
+	store := inmemory.NewStore()
+	participantRepo := inmemory.NewParticipantRepository(store)
+	if participants != nil {
+		participantRepo = participants(participantRepo)
+	}
+	return recruit.NewRecruitUsecase(
+		inmemory.NewRecruitRepository(store),
+		participantRepo,
+		inmemory.NewTxManager(store),
+	)
+}
+
+// failingParticipantRepository は指定したユーザーの登録を失敗させる
+type failingParticipantRepository struct {
+	recruit.ParticipantRepository
+	failUserID recruit.UserID
+}
+
+func (r *failingParticipantRepository) Upsert(
+	ctx context.Context,
+	recruitID recruit.RecruitID,
+	userID recruit.UserID,
+	status recruit.ParticipantStatus,
+) error {
+	if userID == r.failUserID {
+		return errors.New("db error")
+	}
+	return r.ParticipantRepository.Upsert(ctx, recruitID, userID, status)
+}
+
+func TestRecruitUsecase_Inmemory_WaitlistPromotion(t *testing.T) {
+	ctx := context.Background()
+	uc := newInmemoryUsecase(nil)
+
+	if _, err := uc.Open(ctx, "guild-1", "channel-1", "message-1", 1, "author-1"); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	joined, err := uc.Join(ctx, "channel-1", "message-1", "user-1", nil)
+	if err != nil {
+		t.Fatalf("Join(user-1) error = %v", err)
+	}
+	if joined.CurrentStatus != recruit.ParticipantStatusJoined {
+		t.Errorf("Join(user-1).CurrentStatus = %v, want joined", joined.CurrentStatus)
+	}
+
+	waitlisted, err := uc.Join(ctx, "channel-1", "message-1", "user-2", nil)
+	if err != nil {
+		t.Fatalf("Join(user-2) error = %v", err)
+	}
+	if waitlisted.CurrentStatus != recruit.ParticipantStatusWaitlisted {
+		t.Errorf("Join(user-2).CurrentStatus = %v, want waitlisted", waitlisted.CurrentStatus)
+	}
+
+	canceled, err := uc.Cancel(ctx, "channel-1", "message-1", "user-1")
+	if err != nil {
+		t.Fatalf("Cancel(user-1) error = %v", err)
+	}
+	if len(canceled.PromotedUsers) != 1 || canceled.PromotedUsers[0] != "user-2" {
+		t.Errorf("Cancel().PromotedUsers = %v, want [user-2]", canceled.PromotedUsers)
+	}
+
+	view, err := uc.Get(ctx, "channel-1", "message-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(view.JoinedUsers) != 2 || view.JoinedUsers[0] != "author-1" || view.JoinedUsers[1] != "user-2" {
+		t.Errorf("JoinedUsers = %v, want [author-1 user-2]", view.JoinedUsers)
+	}
+	if len(view.WaitlistedUsers) != 0 {
+		t.Errorf("WaitlistedUsers = %v, want empty", view.WaitlistedUsers)
+	}
+}
+
+func TestRecruitUsecase_Inmemory_RollbackOnError(t *testing.T) {
+	ctx := context.Background()
+	uc := newInmemoryUsecase(func(repo recruit.ParticipantRepository) recruit.ParticipantRepository {
+		return &failingParticipantRepository{ParticipantRepository: repo, failUserID: "author-1"}
+	})
+
+	// 作成者の参加登録に失敗した場合は募集の作成も取り消される
+	if _, err := uc.Open(ctx, "guild-1", "channel-1", "message-1", 3, "author-1"); err == nil {
+		t.Fatal("Open() error = nil, want error")
+	}
+	if _, err := uc.Get(ctx, "channel-1", "message-1"); err == nil {
+		t.Error("Get() after failed Open() error = nil, want error")
+	}
+
+	opened, err := uc.ListOpened(ctx, "guild-1", "")
+	if err != nil {
+		t.Fatalf("ListOpened() error = %v", err)
+	}
+	if len(opened) != 0 {
+		t.Errorf("ListOpened() = %v, want empty", opened)
+	}
+}
+
+func TestRecruitUsecase_Inmemory_CloseAndDelete(t *testing.T) {
+	ctx := context.Background()
+	uc := newInmemoryUsecase(nil)
+
+	if _, err := uc.Open(ctx, "guild-1", "channel-1", "message-1", 3, "author-1"); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if _, err := uc.Close(ctx, "channel-1", "message-1", "author-1"); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := uc.Join(ctx, "channel-1", "message-1", "user-1", nil); !errors.Is(err, recruit.ErrRecruitClosed) {
+		t.Errorf("Join() after Close() error = %v, want ErrRecruitClosed", err)
+	}
+
+	if _, err := uc.Reopen(ctx, "channel-1", "message-1", "author-1"); err != nil {
+		t.Fatalf("Reopen() error = %v", err)
+	}
+	if err := uc.Delete(ctx, "channel-1", "message-1", "author-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	// 削除した募集はアーカイブされ、再開も締め切りもできない
+	if _, err := uc.Reopen(ctx, "channel-1", "message-1", "author-1"); !errors.Is(err, recruit.ErrRecruitNotFound) {
+		t.Errorf("Reopen() after Delete() error = %v, want ErrRecruitNotFound", err)
+	}
+	if _, err := uc.Close(ctx, "channel-1", "message-1", "author-1"); !errors.Is(err, recruit.ErrRecruitNotFound) {
+		t.Errorf("Close() after Delete() error = %v, want ErrRecruitNotFound", err)
+	}
+}
+
+// passthroughUnitOfWork はトランザクションで処理を分離しない
+// データベースの分離レベルに頼らず、募集ごとのロックで定員を超えないことを確認するために使用する
+type passthroughUnitOfWork struct{}
+
+func (passthroughUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// yieldingParticipantRepository は参加状態の取得後に他のゴルーチンへ処理を譲り、競合を起きやすくする
+type yieldingParticipantRepository struct {
+	recruit.ParticipantRepository
+}
+
+func (r *yieldingParticipantRepository) List(ctx context.Context, recruitID recruit.RecruitID) ([]recruit.Participant, error) {
+	participants, err := r.ParticipantRepository.List(ctx, recruitID)
+	runtime.Gosched()
+	return participants, err
+}
+
+func TestRecruitUsecase_Inmemory_ConcurrentJoin(t *testing.T) {
+	const (
+		capacity = 10
+		users    = 50
+	)
+
+	ctx := context.Background()
+	store := inmemory.NewStore()
+	uc := recruit.NewRecruitUsecase(
+		inmemory.NewRecruitRepository(store),
+		&yieldingParticipantRepository{ParticipantRepository: inmemory.NewParticipantRepository(store)},
+		passthroughUnitOfWork{},
+	)
+
+	if _, err := uc.Open(ctx, "guild-1", "channel-1", "message-1", capacity, "author-1"); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, users)
+	for i := 0; i < users; i++ {
+		wg.Add(1)
+		go func(userID recruit.UserID) {
+			defer wg.Done()
+			if _, err := uc.Join(ctx, "channel-1", "message-1", userID, nil); err != nil {
+				errs <- fmt.Errorf("Join(%s) error = %w", userID, err)
+			}
+		}(recruit.UserID(fmt.Sprintf("user-%d", i)))
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	view, err := uc.Get(ctx, "channel-1", "message-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	// 作成者を含む
+	if len(view.JoinedUsers) != capacity+1 {
+		t.Errorf("len(JoinedUsers) = %d, want %d", len(view.JoinedUsers), capacity+1)
+	}
+	if len(view.WaitlistedUsers) != users-capacity {
+		t.Errorf("len(WaitlistedUsers) = %d, want %d", len(view.WaitlistedUsers), users-capacity)
+	}
+}
+
+// yieldingRecruitRepository は募集の取得後に他のゴルーチンへ処理を譲り、競合を起きやすくする
+type yieldingRecruitRepository struct {
+	recruit.RecruitRepository
+}
+
+func (r *yieldingRecruitRepository) GetByMessage(
+	ctx context.Context,
+	channelID recruit.ChannelID,
+	messageID recruit.MessageID,
+) (*recruit.RecruitState, error) {
+	state, err := r.RecruitRepository.GetByMessage(ctx, channelID, messageID)
+	runtime.Gosched()
+	return state, err
+}
+
+func (r *yieldingRecruitRepository) ListScheduled(ctx context.Context, until time.Time) ([]*recruit.RecruitState, error) {
+	states, err := r.RecruitRepository.ListScheduled(ctx, until)
+	runtime.Gosched()
+	return states, err
+}
+
+func (r *yieldingRecruitRepository) ListExpired(ctx context.Context, before time.Time) ([]*recruit.RecruitState, error) {
+	states, err := r.RecruitRepository.ListExpired(ctx, before)
+	runtime.Gosched()
+	return states, err
+}
+
+func TestRecruitUsecase_Inmemory_ConcurrentJoinAndSchedule(t *testing.T) {
+	const (
+		capacity = 10
+		users    = 50
+	)
+
+	ctx := context.Background()
+	store := inmemory.NewStore()
+	uc := recruit.NewRecruitUsecase(
+		&yieldingRecruitRepository{RecruitRepository: inmemory.NewRecruitRepository(store)},
+		&yieldingParticipantRepository{ParticipantRepository: inmemory.NewParticipantRepository(store)},
+		passthroughUnitOfWork{},
+	)
+
+	now := time.Now()
+	if _, err := uc.Open(ctx, "guild-1", "channel-1", "message-1", capacity, "author-1", recruit.WithStartAt(now.Add(-time.Minute))); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var joined atomic.Int32
+	errs := make(chan error, users+2)
+	var expired []*recruit.RecruitView
+	for i := 0; i < users; i++ {
+		// 参加の途中で開始と期限切れの処理を行う
+		if i == users/2 {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				if _, err := uc.Start(ctx, now); err != nil {
+					errs <- fmt.Errorf("Start() error = %w", err)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				views, err := uc.Expire(ctx, now.Add(48*time.Hour), time.Hour)
+				if err != nil {
+					errs <- fmt.Errorf("Expire() error = %w", err)
+				}
+				expired = views
+			}()
+		}
+
+		wg.Add(1)
+		go func(userID recruit.UserID) {
+			defer wg.Done()
+			_, err := uc.Join(ctx, "channel-1", "message-1", userID, nil)
+			switch {
+			case err == nil:
+				joined.Add(1)
+			case !errors.Is(err, recruit.ErrRecruitClosed):
+				errs <- fmt.Errorf("Join(%s) error = %w", userID, err)
+			}
+		}(recruit.UserID(fmt.Sprintf("user-%d", i)))
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	view, err := uc.Get(ctx, "channel-1", "message-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	// 開始の処理が期限切れによる締め切りを上書きしない
+	if view.Meta.Status != recruit.RecruitStatusClosed || view.Meta.CloseReason != recruit.CloseReasonExpired {
+		t.Errorf("status = %v (%v), want closed by expiration", view.Meta.Status, view.Meta.CloseReason)
+	}
+	// 締め切り後に参加が反映されない
+	if len(expired) != 1 {
+		t.Fatalf("Expire() returned %d views, want 1", len(expired))
+	}
+	if !slices.Equal(expired[0].JoinedUsers, view.JoinedUsers) || !slices.Equal(expired[0].WaitlistedUsers, view.WaitlistedUsers) {
+		t.Errorf("participants changed after expiration: expired %v/%v, final %v/%v",
+			expired[0].JoinedUsers, expired[0].WaitlistedUsers, view.JoinedUsers, view.WaitlistedUsers)
+	}
+	// 作成者を含む
+	if got := len(view.JoinedUsers) - 1 + len(view.WaitlistedUsers); got != int(joined.Load()) {
+		t.Errorf("participants = %d, want %d successful joins", got, joined.Load())
+	}
+	if len(view.JoinedUsers) > capacity+1 {
+		t.Errorf("len(JoinedUsers) = %d, want <= %d", len(view.JoinedUsers), capacity+1)
+	}
+}
@@ -9,9 +9,13 @@ import (
 
 // Mock repositories
 type mockRecruitRepository struct {
-	getByMessageFunc func(ctx context.Context, channelID ChannelID, messageID MessageID) (*RecruitState, error)
-	createFunc       func(ctx context.Context, state *RecruitState) (RecruitID, error)
-	deleteFunc       func(ctx context.Context, id RecruitID) error
+	getByMessageFunc  func(ctx context.Context, channelID ChannelID, messageID MessageID) (*RecruitState, error)
+	createFunc        func(ctx context.Context, state *RecruitState) (RecruitID, error)
+	deleteFunc        func(ctx context.Context, id RecruitID) error
+	updateFunc        func(ctx context.Context, state *RecruitState) error
+	listScheduledFunc func(ctx context.Context, until time.Time) ([]*RecruitState, error)
+	listExpiredFunc   func(ctx context.Context, before time.Time) ([]*RecruitState, error)
+	listOpenedFunc    func(ctx context.Context, guildID GuildID, channelID ChannelID) ([]*RecruitState, error)
 }
 
 func (m *mockRecruitRepository) Get(ctx context.Context, id RecruitID) (*RecruitState, error) {
@@ -33,6 +37,9 @@ func (m *mockRecruitRepository) Create(ctx context.Context, state *RecruitState)
 }
 
 func (m *mockRecruitRepository) Update(ctx context.Context, state *RecruitState) error {
+	if m.updateFunc != nil {
+		return m.updateFunc(ctx, state)
+	}
 	return nil
 }
 
@@ -43,10 +50,32 @@ func (m *mockRecruitRepository) Delete(ctx context.Context, id RecruitID) error
 	return nil
 }
 
+func (m *mockRecruitRepository) ListScheduled(ctx context.Context, until time.Time) ([]*RecruitState, error) {
+	if m.listScheduledFunc != nil {
+		return m.listScheduledFunc(ctx, until)
+	}
+	return nil, nil
+}
+
+func (m *mockRecruitRepository) ListExpired(ctx context.Context, before time.Time) ([]*RecruitState, error) {
+	if m.listExpiredFunc != nil {
+		return m.listExpiredFunc(ctx, before)
+	}
+	return nil, nil
+}
+
+func (m *mockRecruitRepository) ListOpened(ctx context.Context, guildID GuildID, channelID ChannelID) ([]*RecruitState, error) {
+	if m.listOpenedFunc != nil {
+		return m.listOpenedFunc(ctx, guildID, channelID)
+	}
+	return nil, nil
+}
+
 type mockParticipantRepository struct {
 	upsertFunc               func(ctx context.Context, recruitID RecruitID, userID UserID, status ParticipantStatus) error
 	findByRecruitAndUserFunc func(ctx context.Context, recruitID RecruitID, userID UserID) (*Participant, error)
 	listFunc                 func(ctx context.Context, recruitID RecruitID) ([]Participant, error)
+	deleteAllFunc            func(ctx context.Context, recruitID RecruitID) error
 }
 
 func (m *mockParticipantRepository) Upsert(ctx context.Context, recruitID RecruitID, userID UserID, status ParticipantStatus) error {
@@ -71,6 +100,9 @@ func (m *mockParticipantRepository) List(ctx context.Context, recruitID RecruitI
 }
 
 func (m *mockParticipantRepository) DeleteAll(ctx context.Context, recruitID RecruitID) error {
+	if m.deleteAllFunc != nil {
+		return m.deleteAllFunc(ctx, recruitID)
+	}
 	return nil
 }
 
@@ -153,6 +185,50 @@ func TestRecruitUsecase_Open(t *testing.T) {
 		}
 	})
 
+	t.Run("開始時刻を指定して募集を作成できる", func(t *testing.T) {
+		startAt := time.Date(2025, 1, 1, 21, 30, 0, 0, time.UTC)
+		recruitRepo := &mockRecruitRepository{
+			createFunc: func(ctx context.Context, state *RecruitState) (RecruitID, error) {
+				if state.StartAt == nil || !state.StartAt.Equal(startAt) {
+					t.Errorf("StartAt = %v, want %v", state.StartAt, startAt)
+				}
+				return 1, nil
+			},
+		}
+		uc := NewRecruitUsecase(recruitRepo, &mockParticipantRepository{}, &mockUnitOfWork{})
+
+		view, err := uc.Open(ctx, "guild-1", "channel-1", "message-1", 5, "author-1", WithStartAt(startAt))
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		if view.Meta.StartAt == nil {
+			t.Error("view.Meta.StartAt is nil")
+		}
+	})
+
+	t.Run("タイトルと内容を指定して募集を作成できる", func(t *testing.T) {
+		recruitRepo := &mockRecruitRepository{
+			createFunc: func(ctx context.Context, state *RecruitState) (RecruitID, error) {
+				if state.Title != "Apex ランク" {
+					t.Errorf("Title = %v, want Apex ランク", state.Title)
+				}
+				if state.Description != "ゴールド帯" {
+					t.Errorf("Description = %v, want ゴールド帯", state.Description)
+				}
+				return 1, nil
+			},
+		}
+		uc := NewRecruitUsecase(recruitRepo, &mockParticipantRepository{}, &mockUnitOfWork{})
+
+		_, err := uc.Open(
+			ctx, "guild-1", "channel-1", "message-1", 5, "author-1",
+			WithTitle("Apex ランク"), WithDescription("ゴールド帯"),
+		)
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+	})
+
 	t.Run("リポジトリエラーの場合はエラーを返す", func(t *testing.T) {
 		recruitRepo := &mockRecruitRepository{
 			createFunc: func(ctx context.Context, state *RecruitState) (RecruitID, error) {
@@ -214,7 +290,7 @@ func TestRecruitUsecase_Join(t *testing.T) {
 		uow := &mockUnitOfWork{}
 		uc := NewRecruitUsecase(recruitRepo, participantRepo, uow)
 
-		result, err := uc.Join(ctx, "channel-1", "message-1", "user-1")
+		result, err := uc.Join(ctx, "channel-1", "message-1", "user-1", nil)
 		if err != nil {
 			t.Fatalf("Join() error = %v", err)
 		}
@@ -250,7 +326,7 @@ func TestRecruitUsecase_Join(t *testing.T) {
 		uow := &mockUnitOfWork{}
 		uc := NewRecruitUsecase(recruitRepo, participantRepo, uow)
 
-		_, err := uc.Join(ctx, "channel-1", "message-1", "author-1")
+		_, err := uc.Join(ctx, "channel-1", "message-1", "author-1", nil)
 		if !errors.Is(err, ErrAuthorCannotJoin) {
 			t.Errorf("Join() error = %v, want ErrAuthorCannotJoin", err)
 		}
@@ -286,7 +362,7 @@ func TestRecruitUsecase_Join(t *testing.T) {
 		uow := &mockUnitOfWork{}
 		uc := NewRecruitUsecase(recruitRepo, participantRepo, uow)
 
-		_, err := uc.Join(ctx, "channel-1", "message-1", "user-1")
+		_, err := uc.Join(ctx, "channel-1", "message-1", "user-1", nil)
 		if !errors.Is(err, ErrAlreadyJoined) {
 			t.Errorf("Join() error = %v, want ErrAlreadyJoined", err)
 		}
@@ -438,7 +514,7 @@ func TestRecruitUsecase_Cancel(t *testing.T) {
 func TestRecruitUsecase_Close(t *testing.T) {
 	ctx := context.Background()
 
-	t.Run("作成者は募集を削除できる", func(t *testing.T) {
+	t.Run("作成者は募集を締め切れる", func(t *testing.T) {
 		recruitRepo := &mockRecruitRepository{
 			getByMessageFunc: func(ctx context.Context, channelID ChannelID, messageID MessageID) (*RecruitState, error) {
 				return &RecruitState{
@@ -452,25 +528,44 @@ func TestRecruitUsecase_Close(t *testing.T) {
 					CreatedAt:   time.Now(),
 				}, nil
 			},
-			deleteFunc: func(ctx context.Context, id RecruitID) error {
-				if id != 1 {
-					t.Errorf("RecruitID = %v, want 1", id)
+			updateFunc: func(ctx context.Context, state *RecruitState) error {
+				if state.Status != RecruitStatusClosed {
+					t.Errorf("Status = %v, want %v", state.Status, RecruitStatusClosed)
+				}
+				if state.CloseReason != CloseReasonManual {
+					t.Errorf("CloseReason = %v, want %v", state.CloseReason, CloseReasonManual)
 				}
 				return nil
 			},
+			deleteFunc: func(ctx context.Context, id RecruitID) error {
+				t.Error("Delete() should not be called")
+				return nil
+			},
 		}
 
-		participantRepo := &mockParticipantRepository{}
+		participantRepo := &mockParticipantRepository{
+			listFunc: func(ctx context.Context, recruitID RecruitID) ([]Participant, error) {
+				return []Participant{
+					{RecruitID: recruitID, UserID: "author-1", Status: ParticipantStatusJoined},
+					{RecruitID: recruitID, UserID: "user-1", Status: ParticipantStatusJoined},
+				}, nil
+			},
+		}
 		uow := &mockUnitOfWork{}
 		uc := NewRecruitUsecase(recruitRepo, participantRepo, uow)
 
-		err := uc.Close(ctx, "channel-1", "message-1", "author-1")
+		view, err := uc.Close(ctx, "channel-1", "message-1", "author-1")
 		if err != nil {
-			t.Errorf("Close() error = %v", err)
+			t.Fatalf("Close() error = %v", err)
+		}
+
+		// 参加者の履歴は保持される
+		if len(view.JoinedUsers) != 2 {
+			t.Errorf("JoinedUsers length = %v, want 2", len(view.JoinedUsers))
 		}
 	})
 
-	t.Run("作成者以外は削除できない", func(t *testing.T) {
+	t.Run("作成者以外は締め切れない", func(t *testing.T) {
 		recruitRepo := &mockRecruitRepository{
 			getByMessageFunc: func(ctx context.Context, channelID ChannelID, messageID MessageID) (*RecruitState, error) {
 				return &RecruitState{
@@ -490,9 +585,846 @@ func TestRecruitUsecase_Close(t *testing.T) {
 		uow := &mockUnitOfWork{}
 		uc := NewRecruitUsecase(recruitRepo, participantRepo, uow)
 
-		err := uc.Close(ctx, "channel-1", "message-1", "user-1")
-		if err == nil {
-			t.Error("Close() error = nil, want error")
+		_, err := uc.Close(ctx, "channel-1", "message-1", "user-1")
+		if !errors.Is(err, ErrNotAuthor) {
+			t.Errorf("Close() error = %v, want ErrNotAuthor", err)
+		}
+	})
+
+	t.Run("締め切り済みの募集は締め切れない", func(t *testing.T) {
+		recruitRepo := newClosedRecruitRepository()
+		uc := NewRecruitUsecase(recruitRepo, &mockParticipantRepository{}, &mockUnitOfWork{})
+
+		_, err := uc.Close(ctx, "channel-1", "message-1", "author-1")
+		if !errors.Is(err, ErrRecruitClosed) {
+			t.Errorf("Close() error = %v, want ErrRecruitClosed", err)
+		}
+	})
+}
+
+func newClosedRecruitRepository() *mockRecruitRepository {
+	return &mockRecruitRepository{
+		getByMessageFunc: func(ctx context.Context, channelID ChannelID, messageID MessageID) (*RecruitState, error) {
+			return &RecruitState{
+				ID:          1,
+				ChannelID:   channelID,
+				MessageID:   messageID,
+				AuthorID:    "author-1",
+				MaxCapacity: 5,
+				Status:      RecruitStatusClosed,
+				CloseReason: CloseReasonManual,
+				CreatedAt:   time.Now(),
+			}, nil
+		},
+	}
+}
+
+func TestRecruitUsecase_Reopen(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("作成者は締め切った募集を再開できる", func(t *testing.T) {
+		recruitRepo := newClosedRecruitRepository()
+		recruitRepo.updateFunc = func(ctx context.Context, state *RecruitState) error {
+			if state.Status != RecruitStatusOpened {
+				t.Errorf("Status = %v, want %v", state.Status, RecruitStatusOpened)
+			}
+			if state.CloseReason != CloseReasonNone {
+				t.Errorf("CloseReason = %v, want empty", state.CloseReason)
+			}
+			if state.ReopenedAt == nil {
+				t.Error("ReopenedAt is nil")
+			}
+			return nil
+		}
+		uc := NewRecruitUsecase(recruitRepo, &mockParticipantRepository{}, &mockUnitOfWork{})
+
+		view, err := uc.Reopen(ctx, "channel-1", "message-1", "author-1")
+		if err != nil {
+			t.Fatalf("Reopen() error = %v", err)
+		}
+		if view.Meta.Status != RecruitStatusOpened {
+			t.Errorf("Status = %v, want %v", view.Meta.Status, RecruitStatusOpened)
+		}
+	})
+
+	t.Run("作成者以外は再開できない", func(t *testing.T) {
+		uc := NewRecruitUsecase(newClosedRecruitRepository(), &mockParticipantRepository{}, &mockUnitOfWork{})
+
+		_, err := uc.Reopen(ctx, "channel-1", "message-1", "user-1")
+		if !errors.Is(err, ErrNotAuthor) {
+			t.Errorf("Reopen() error = %v, want ErrNotAuthor", err)
+		}
+	})
+
+	t.Run("募集中の募集は再開できない", func(t *testing.T) {
+		uc := NewRecruitUsecase(newWaitlistRecruitRepository(5), &mockParticipantRepository{}, &mockUnitOfWork{})
+
+		_, err := uc.Reopen(ctx, "channel-1", "message-1", "author-1")
+		if !errors.Is(err, ErrRecruitNotClosed) {
+			t.Errorf("Reopen() error = %v, want ErrRecruitNotClosed", err)
+		}
+	})
+}
+
+func TestRecruitUsecase_JoinClosedRecruit(t *testing.T) {
+	ctx := context.Background()
+	uc := NewRecruitUsecase(newClosedRecruitRepository(), &mockParticipantRepository{}, &mockUnitOfWork{})
+
+	if _, err := uc.Join(ctx, "channel-1", "message-1", "user-1", nil); !errors.Is(err, ErrRecruitClosed) {
+		t.Errorf("Join() error = %v, want ErrRecruitClosed", err)
+	}
+	if _, err := uc.Decline(ctx, "channel-1", "message-1", "user-1"); !errors.Is(err, ErrRecruitClosed) {
+		t.Errorf("Decline() error = %v, want ErrRecruitClosed", err)
+	}
+	// 作成者には締め切り後も操作パネルを表示する
+	if _, err := uc.Join(ctx, "channel-1", "message-1", "author-1", nil); !errors.Is(err, ErrAuthorCannotJoin) {
+		t.Errorf("Join() error = %v, want ErrAuthorCannotJoin", err)
+	}
+}
+
+func TestRecruitUsecase_Remind(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC)
+	remindBefore := 10 * time.Minute
+
+	soon := now.Add(5 * time.Minute)
+	later := now.Add(30 * time.Minute)
+	remindedAt := now.Add(-time.Minute)
+
+	recruitRepo := &mockRecruitRepository{
+		listScheduledFunc: func(ctx context.Context, until time.Time) ([]*RecruitState, error) {
+			if !until.Equal(now.Add(remindBefore)) {
+				t.Errorf("until = %v, want %v", until, now.Add(remindBefore))
+			}
+			return []*RecruitState{
+				{ID: 1, MessageID: "message-1", Status: RecruitStatusOpened, StartAt: &soon},
+				{ID: 2, MessageID: "message-2", Status: RecruitStatusOpened, StartAt: &soon, RemindedAt: &remindedAt},
+				{ID: 3, MessageID: "message-3", Status: RecruitStatusOpened, StartAt: &later},
+			}, nil
+		},
+		getByMessageFunc: getByMessageFrom(
+			&RecruitState{ID: 1, MessageID: "message-1", Status: RecruitStatusOpened, StartAt: &soon},
+			&RecruitState{ID: 2, MessageID: "message-2", Status: RecruitStatusOpened, StartAt: &soon, RemindedAt: &remindedAt},
+			&RecruitState{ID: 3, MessageID: "message-3", Status: RecruitStatusOpened, StartAt: &later},
+		),
+		updateFunc: func(ctx context.Context, state *RecruitState) error {
+			if state.ID != 1 {
+				t.Errorf("Update() RecruitID = %v, want 1", state.ID)
+			}
+			if state.RemindedAt == nil || !state.RemindedAt.Equal(now) {
+				t.Errorf("RemindedAt = %v, want %v", state.RemindedAt, now)
+			}
+			return nil
+		},
+	}
+	participantRepo := &mockParticipantRepository{
+		listFunc: func(ctx context.Context, recruitID RecruitID) ([]Participant, error) {
+			return []Participant{
+				{RecruitID: recruitID, UserID: "author-1", Status: ParticipantStatusJoined},
+				{RecruitID: recruitID, UserID: "user-1", Status: ParticipantStatusJoined},
+			}, nil
+		},
+	}
+
+	uc := NewRecruitUsecase(recruitRepo, participantRepo, &mockUnitOfWork{})
+
+	views, err := uc.Remind(ctx, now, remindBefore)
+	if err != nil {
+		t.Fatalf("Remind() error = %v", err)
+	}
+
+	if len(views) != 1 {
+		t.Fatalf("Remind() length = %v, want 1", len(views))
+	}
+	if views[0].Meta.ID != 1 {
+		t.Errorf("Remind() RecruitID = %v, want 1", views[0].Meta.ID)
+	}
+	if len(views[0].JoinedUsers) != 2 {
+		t.Errorf("JoinedUsers length = %v, want 2", len(views[0].JoinedUsers))
+	}
+}
+
+func TestRecruitUsecase_Start(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC)
+
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	var updated []RecruitID
+	recruitRepo := &mockRecruitRepository{
+		listScheduledFunc: func(ctx context.Context, until time.Time) ([]*RecruitState, error) {
+			return []*RecruitState{
+				{ID: 1, MessageID: "message-1", Status: RecruitStatusOpened, StartAt: &past},
+				{ID: 2, MessageID: "message-2", Status: RecruitStatusOpened, StartAt: &future},
+				{ID: 3, MessageID: "message-3", Status: RecruitStatusOpened, StartAt: &past},
+			}, nil
+		},
+		getByMessageFunc: getByMessageFrom(
+			&RecruitState{ID: 1, MessageID: "message-1", Status: RecruitStatusOpened, StartAt: &past},
+			&RecruitState{ID: 2, MessageID: "message-2", Status: RecruitStatusOpened, StartAt: &future},
+			// 一覧の取得後に締め切られた募集は開始しない
+			&RecruitState{ID: 3, MessageID: "message-3", Status: RecruitStatusClosed, StartAt: &past},
+		),
+		updateFunc: func(ctx context.Context, state *RecruitState) error {
+			if state.Status != RecruitStatusStarted {
+				t.Errorf("Status = %v, want %v", state.Status, RecruitStatusStarted)
+			}
+			updated = append(updated, state.ID)
+			return nil
+		},
+	}
+
+	uc := NewRecruitUsecase(recruitRepo, &mockParticipantRepository{}, &mockUnitOfWork{})
+
+	views, err := uc.Start(ctx, now)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	if len(views) != 1 || views[0].Meta.ID != 1 {
+		t.Errorf("Start() returned %v views, want recruit 1 only", len(views))
+	}
+	if len(updated) != 1 || updated[0] != 1 {
+		t.Errorf("updated = %v, want [1]", updated)
+	}
+}
+
+func TestRecruitUsecase_Expire(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 2, 20, 0, 0, 0, time.UTC)
+	ttl := 24 * time.Hour
+
+	recruitRepo := &mockRecruitRepository{
+		listExpiredFunc: func(ctx context.Context, before time.Time) ([]*RecruitState, error) {
+			if !before.Equal(now.Add(-ttl)) {
+				t.Errorf("before = %v, want %v", before, now.Add(-ttl))
+			}
+			return []*RecruitState{
+				{ID: 1, MessageID: "message-1", Status: RecruitStatusOpened, CreatedAt: now.Add(-25 * time.Hour)},
+				{ID: 2, MessageID: "message-2", Status: RecruitStatusOpened, CreatedAt: now.Add(-25 * time.Hour)},
+			}, nil
+		},
+		getByMessageFunc: getByMessageFrom(
+			&RecruitState{ID: 1, MessageID: "message-1", Status: RecruitStatusOpened, CreatedAt: now.Add(-25 * time.Hour)},
+			// 一覧の取得後に削除された募集は対象外
+		),
+		updateFunc: func(ctx context.Context, state *RecruitState) error {
+			if state.Status != RecruitStatusClosed {
+				t.Errorf("Status = %v, want %v", state.Status, RecruitStatusClosed)
+			}
+			if state.CloseReason != CloseReasonExpired {
+				t.Errorf("CloseReason = %v, want %v", state.CloseReason, CloseReasonExpired)
+			}
+			return nil
+		},
+	}
+
+	uc := NewRecruitUsecase(recruitRepo, &mockParticipantRepository{}, &mockUnitOfWork{})
+
+	views, err := uc.Expire(ctx, now, ttl)
+	if err != nil {
+		t.Fatalf("Expire() error = %v", err)
+	}
+
+	if len(views) != 1 {
+		t.Fatalf("Expire() length = %v, want 1", len(views))
+	}
+	if views[0].Meta.Status != RecruitStatusClosed {
+		t.Errorf("Status = %v, want %v", views[0].Meta.Status, RecruitStatusClosed)
+	}
+}
+
+// getByMessageFrom はstatesからメッセージIDが一致する募集を返すGetByMessageを作成する
+// 呼び出しごとに複製を返し、一覧で返した状態とは別のインスタンスとする
+func getByMessageFrom(states ...*RecruitState) func(ctx context.Context, channelID ChannelID, messageID MessageID) (*RecruitState, error) {
+	return func(ctx context.Context, channelID ChannelID, messageID MessageID) (*RecruitState, error) {
+		for _, state := range states {
+			if state.MessageID == messageID {
+				copied := *state
+				return &copied, nil
+			}
+		}
+		return nil, ErrRecruitNotFound
+	}
+}
+
+// participantStore はUpsertの結果をListに反映するテスト用の参加者ストア
+type participantStore struct {
+	participants []Participant
+	clock        time.Time
+}
+
+func (s *participantStore) repository() *mockParticipantRepository {
+	return &mockParticipantRepository{
+		upsertFunc: func(ctx context.Context, recruitID RecruitID, userID UserID, status ParticipantStatus) error {
+			s.clock = s.clock.Add(time.Second)
+			updatedAt := s.clock
+			for i := range s.participants {
+				if s.participants[i].UserID == userID {
+					s.participants[i].Status = status
+					s.participants[i].UpdatedAt = &updatedAt
+					return nil
+				}
+			}
+			s.participants = append(s.participants, Participant{
+				RecruitID: recruitID,
+				UserID:    userID,
+				Status:    status,
+				CreatedAt: updatedAt,
+				UpdatedAt: &updatedAt,
+			})
+			return nil
+		},
+		findByRecruitAndUserFunc: func(ctx context.Context, recruitID RecruitID, userID UserID) (*Participant, error) {
+			for _, p := range s.participants {
+				if p.UserID == userID {
+					return &p, nil
+				}
+			}
+			return nil, nil
+		},
+		listFunc: func(ctx context.Context, recruitID RecruitID) ([]Participant, error) {
+			return append([]Participant(nil), s.participants...), nil
+		},
+	}
+}
+
+func (s *participantStore) add(userID UserID, status ParticipantStatus) {
+	s.clock = s.clock.Add(time.Second)
+	s.participants = append(s.participants, Participant{
+		RecruitID: 1,
+		UserID:    userID,
+		Status:    status,
+		CreatedAt: s.clock,
+	})
+}
+
+func newWaitlistRecruitRepository(maxCapacity int) *mockRecruitRepository {
+	return &mockRecruitRepository{
+		getByMessageFunc: func(ctx context.Context, channelID ChannelID, messageID MessageID) (*RecruitState, error) {
+			return &RecruitState{
+				ID:          1,
+				ChannelID:   channelID,
+				MessageID:   messageID,
+				AuthorID:    "author-1",
+				MaxCapacity: maxCapacity,
+				Status:      RecruitStatusOpened,
+			}, nil
+		},
+	}
+}
+
+func TestRecruitUsecase_Waitlist(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("満員の場合はキャンセル待ちになる", func(t *testing.T) {
+		store := &participantStore{}
+		store.add("author-1", ParticipantStatusJoined)
+		store.add("user-1", ParticipantStatusJoined)
+
+		uc := NewRecruitUsecase(newWaitlistRecruitRepository(1), store.repository(), &mockUnitOfWork{})
+
+		result, err := uc.Join(ctx, "channel-1", "message-1", "user-2", nil)
+		if err != nil {
+			t.Fatalf("Join() error = %v", err)
+		}
+
+		if result.CurrentStatus != ParticipantStatusWaitlisted {
+			t.Errorf("CurrentStatus = %v, want %v", result.CurrentStatus, ParticipantStatusWaitlisted)
+		}
+		if len(result.CurrentView.JoinedUsers) != 2 {
+			t.Errorf("JoinedUsers length = %v, want 2", len(result.CurrentView.JoinedUsers))
+		}
+		if len(result.CurrentView.WaitlistedUsers) != 1 || result.CurrentView.WaitlistedUsers[0] != "user-2" {
+			t.Errorf("WaitlistedUsers = %v, want [user-2]", result.CurrentView.WaitlistedUsers)
+		}
+	})
+
+	t.Run("キャンセル待ち中の参加は参加済みエラー", func(t *testing.T) {
+		store := &participantStore{}
+		store.add("author-1", ParticipantStatusJoined)
+		store.add("user-1", ParticipantStatusJoined)
+		store.add("user-2", ParticipantStatusWaitlisted)
+
+		uc := NewRecruitUsecase(newWaitlistRecruitRepository(1), store.repository(), &mockUnitOfWork{})
+
+		_, err := uc.Join(ctx, "channel-1", "message-1", "user-2", nil)
+		if !errors.Is(err, ErrAlreadyJoined) {
+			t.Errorf("Join() error = %v, want ErrAlreadyJoined", err)
+		}
+	})
+
+	t.Run("参加者がキャンセルするとキャンセル待ちの先頭が繰り上がる", func(t *testing.T) {
+		store := &participantStore{}
+		store.add("author-1", ParticipantStatusJoined)
+		store.add("user-2", ParticipantStatusWaitlisted)
+		store.add("user-1", ParticipantStatusJoined)
+		store.add("user-3", ParticipantStatusWaitlisted)
+		// user-2は先に参加しているが、キャンセル待ちになったのはuser-3より後
+		later := store.clock.Add(time.Minute)
+		store.participants[1].UpdatedAt = &later
+
+		uc := NewRecruitUsecase(newWaitlistRecruitRepository(1), store.repository(), &mockUnitOfWork{})
+
+		result, err := uc.Cancel(ctx, "channel-1", "message-1", "user-1")
+		if err != nil {
+			t.Fatalf("Cancel() error = %v", err)
+		}
+
+		if len(result.PromotedUsers) != 1 || result.PromotedUsers[0] != "user-3" {
+			t.Errorf("PromotedUsers = %v, want [user-3]", result.PromotedUsers)
+		}
+		if len(result.CurrentView.WaitlistedUsers) != 1 || result.CurrentView.WaitlistedUsers[0] != "user-2" {
+			t.Errorf("WaitlistedUsers = %v, want [user-2]", result.CurrentView.WaitlistedUsers)
+		}
+		if len(result.CurrentView.JoinedUsers) != 2 {
+			t.Errorf("JoinedUsers length = %v, want 2", len(result.CurrentView.JoinedUsers))
+		}
+	})
+
+	t.Run("キャンセル待ちのユーザーが辞退しても繰り上げは発生しない", func(t *testing.T) {
+		store := &participantStore{}
+		store.add("author-1", ParticipantStatusJoined)
+		store.add("user-1", ParticipantStatusJoined)
+		store.add("user-2", ParticipantStatusWaitlisted)
+		store.add("user-3", ParticipantStatusWaitlisted)
+
+		uc := NewRecruitUsecase(newWaitlistRecruitRepository(1), store.repository(), &mockUnitOfWork{})
+
+		result, err := uc.Decline(ctx, "channel-1", "message-1", "user-2")
+		if err != nil {
+			t.Fatalf("Decline() error = %v", err)
+		}
+
+		if len(result.PromotedUsers) != 0 {
+			t.Errorf("PromotedUsers = %v, want empty", result.PromotedUsers)
+		}
+		if len(result.CurrentView.WaitlistedUsers) != 1 || result.CurrentView.WaitlistedUsers[0] != "user-3" {
+			t.Errorf("WaitlistedUsers = %v, want [user-3]", result.CurrentView.WaitlistedUsers)
+		}
+	})
+}
+
+func TestRecruitUsecase_Tentative(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("未定で回答しても枠を消費しない", func(t *testing.T) {
+		store := &participantStore{}
+		store.add("author-1", ParticipantStatusJoined)
+
+		uc := NewRecruitUsecase(newWaitlistRecruitRepository(1), store.repository(), &mockUnitOfWork{})
+
+		result, err := uc.Tentative(ctx, "channel-1", "message-1", "user-1")
+		if err != nil {
+			t.Fatalf("Tentative() error = %v", err)
+		}
+
+		if result.CurrentStatus != ParticipantStatusTentative {
+			t.Errorf("CurrentStatus = %v, want %v", result.CurrentStatus, ParticipantStatusTentative)
+		}
+		if len(result.CurrentView.TentativeUsers) != 1 {
+			t.Errorf("TentativeUsers length = %v, want 1", len(result.CurrentView.TentativeUsers))
+		}
+		if result.CurrentView.RemainingSlots() != 1 {
+			t.Errorf("RemainingSlots() = %v, want 1", result.CurrentView.RemainingSlots())
+		}
+	})
+
+	t.Run("既に未定の場合はエラー", func(t *testing.T) {
+		store := &participantStore{}
+		store.add("author-1", ParticipantStatusJoined)
+		store.add("user-1", ParticipantStatusTentative)
+
+		uc := NewRecruitUsecase(newWaitlistRecruitRepository(1), store.repository(), &mockUnitOfWork{})
+
+		_, err := uc.Tentative(ctx, "channel-1", "message-1", "user-1")
+		if !errors.Is(err, ErrAlreadyTentative) {
+			t.Errorf("Tentative() error = %v, want ErrAlreadyTentative", err)
+		}
+	})
+
+	t.Run("参加から未定に変更するとキャンセル待ちが繰り上がる", func(t *testing.T) {
+		store := &participantStore{}
+		store.add("author-1", ParticipantStatusJoined)
+		store.add("user-1", ParticipantStatusJoined)
+		store.add("user-2", ParticipantStatusWaitlisted)
+
+		uc := NewRecruitUsecase(newWaitlistRecruitRepository(1), store.repository(), &mockUnitOfWork{})
+
+		result, err := uc.Tentative(ctx, "channel-1", "message-1", "user-1")
+		if err != nil {
+			t.Fatalf("Tentative() error = %v", err)
+		}
+
+		if len(result.PromotedUsers) != 1 || result.PromotedUsers[0] != "user-2" {
+			t.Errorf("PromotedUsers = %v, want [user-2]", result.PromotedUsers)
+		}
+	})
+}
+
+func TestRecruitUsecase_ChangeCapacity(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("募集人数を増やすとキャンセル待ちが繰り上がる", func(t *testing.T) {
+		store := &participantStore{}
+		store.add("author-1", ParticipantStatusJoined)
+		store.add("user-1", ParticipantStatusJoined)
+		store.add("user-2", ParticipantStatusWaitlisted)
+		store.add("user-3", ParticipantStatusWaitlisted)
+
+		recruitRepo := newWaitlistRecruitRepository(1)
+		recruitRepo.updateFunc = func(ctx context.Context, state *RecruitState) error {
+			if state.MaxCapacity != 2 {
+				t.Errorf("MaxCapacity = %v, want 2", state.MaxCapacity)
+			}
+			return nil
+		}
+		uc := NewRecruitUsecase(recruitRepo, store.repository(), &mockUnitOfWork{})
+
+		result, err := uc.ChangeCapacity(ctx, "channel-1", "message-1", "author-1", 2)
+		if err != nil {
+			t.Fatalf("ChangeCapacity() error = %v", err)
+		}
+
+		if len(result.PromotedUsers) != 1 || result.PromotedUsers[0] != "user-2" {
+			t.Errorf("PromotedUsers = %v, want [user-2]", result.PromotedUsers)
+		}
+		if len(result.CurrentView.WaitlistedUsers) != 1 || result.CurrentView.WaitlistedUsers[0] != "user-3" {
+			t.Errorf("WaitlistedUsers = %v, want [user-3]", result.CurrentView.WaitlistedUsers)
+		}
+	})
+
+	t.Run("参加者数と同じ人数までは減らせる", func(t *testing.T) {
+		store := &participantStore{}
+		store.add("author-1", ParticipantStatusJoined)
+		store.add("user-1", ParticipantStatusJoined)
+
+		uc := NewRecruitUsecase(newWaitlistRecruitRepository(5), store.repository(), &mockUnitOfWork{})
+
+		result, err := uc.ChangeCapacity(ctx, "channel-1", "message-1", "author-1", 1)
+		if err != nil {
+			t.Fatalf("ChangeCapacity() error = %v", err)
+		}
+		if !result.CurrentView.IsFull() {
+			t.Error("IsFull() = false, want true")
+		}
+	})
+
+	tests := []struct {
+		name        string
+		repo        *mockRecruitRepository
+		actorID     UserID
+		maxCapacity int
+		wantErr     error
+	}{
+		{
+			name:        "参加者数より少ない人数には変更できない",
+			repo:        newWaitlistRecruitRepository(5),
+			actorID:     "author-1",
+			maxCapacity: 1,
+			wantErr:     ErrCapacityBelowJoined,
+		},
+		{
+			name:        "0人には変更できない",
+			repo:        newWaitlistRecruitRepository(5),
+			actorID:     "author-1",
+			maxCapacity: 0,
+			wantErr:     ErrInvalidCapacity,
+		},
+		{
+			name:        "作成者以外は変更できない",
+			repo:        newWaitlistRecruitRepository(5),
+			actorID:     "user-1",
+			maxCapacity: 3,
+			wantErr:     ErrNotAuthor,
+		},
+		{
+			name:        "締め切り済みの募集は変更できない",
+			repo:        newClosedRecruitRepository(),
+			actorID:     "author-1",
+			maxCapacity: 3,
+			wantErr:     ErrRecruitClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &participantStore{}
+			store.add("author-1", ParticipantStatusJoined)
+			store.add("user-1", ParticipantStatusJoined)
+			store.add("user-2", ParticipantStatusJoined)
+
+			uc := NewRecruitUsecase(tt.repo, store.repository(), &mockUnitOfWork{})
+
+			_, err := uc.ChangeCapacity(ctx, "channel-1", "message-1", tt.actorID, tt.maxCapacity)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChangeCapacity() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecruitUsecase_Kick(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("参加者を外すとキャンセル待ちが繰り上がる", func(t *testing.T) {
+		store := &participantStore{}
+		store.add("author-1", ParticipantStatusJoined)
+		store.add("user-1", ParticipantStatusJoined)
+		store.add("user-2", ParticipantStatusWaitlisted)
+
+		uc := NewRecruitUsecase(newWaitlistRecruitRepository(1), store.repository(), &mockUnitOfWork{})
+
+		result, err := uc.Kick(ctx, "channel-1", "message-1", "author-1", "user-1")
+		if err != nil {
+			t.Fatalf("Kick() error = %v", err)
+		}
+
+		if *result.PreviousStatus != ParticipantStatusJoined {
+			t.Errorf("PreviousStatus = %v, want %v", *result.PreviousStatus, ParticipantStatusJoined)
+		}
+		if result.CurrentStatus != ParticipantStatusKicked {
+			t.Errorf("CurrentStatus = %v, want %v", result.CurrentStatus, ParticipantStatusKicked)
+		}
+		if len(result.PromotedUsers) != 1 || result.PromotedUsers[0] != "user-2" {
+			t.Errorf("PromotedUsers = %v, want [user-2]", result.PromotedUsers)
 		}
 	})
+
+	tests := []struct {
+		name     string
+		repo     *mockRecruitRepository
+		actorID  UserID
+		targetID UserID
+		wantErr  error
+	}{
+		{
+			name:     "作成者は外せない",
+			repo:     newWaitlistRecruitRepository(5),
+			actorID:  "author-1",
+			targetID: "author-1",
+			wantErr:  ErrCannotKickAuthor,
+		},
+		{
+			name:     "参加していないユーザーは外せない",
+			repo:     newWaitlistRecruitRepository(5),
+			actorID:  "author-1",
+			targetID: "user-9",
+			wantErr:  ErrParticipantNotFound,
+		},
+		{
+			name:     "辞退済みのユーザーは外せない",
+			repo:     newWaitlistRecruitRepository(5),
+			actorID:  "author-1",
+			targetID: "user-2",
+			wantErr:  ErrParticipantNotFound,
+		},
+		{
+			name:     "外し済みのユーザーは外せない",
+			repo:     newWaitlistRecruitRepository(5),
+			actorID:  "author-1",
+			targetID: "user-3",
+			wantErr:  ErrParticipantNotFound,
+		},
+		{
+			name:     "作成者以外は外せない",
+			repo:     newWaitlistRecruitRepository(5),
+			actorID:  "user-2",
+			targetID: "user-1",
+			wantErr:  ErrNotAuthor,
+		},
+		{
+			name:     "締め切り済みの募集では外せない",
+			repo:     newClosedRecruitRepository(),
+			actorID:  "author-1",
+			targetID: "user-1",
+			wantErr:  ErrRecruitClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &participantStore{}
+			store.add("author-1", ParticipantStatusJoined)
+			store.add("user-1", ParticipantStatusJoined)
+			store.add("user-2", ParticipantStatusDeclined)
+			store.add("user-3", ParticipantStatusKicked)
+
+			uc := NewRecruitUsecase(tt.repo, store.repository(), &mockUnitOfWork{})
+
+			_, err := uc.Kick(ctx, "channel-1", "message-1", tt.actorID, tt.targetID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Kick() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecruitUsecase_Delete(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("作成者は募集を削除でき、記録はアーカイブとして残る", func(t *testing.T) {
+		var updated *RecruitState
+		recruitRepo := newWaitlistRecruitRepository(5)
+		recruitRepo.updateFunc = func(ctx context.Context, state *RecruitState) error {
+			updated = state
+			return nil
+		}
+		recruitRepo.deleteFunc = func(ctx context.Context, id RecruitID) error {
+			t.Error("Delete() should not be called")
+			return nil
+		}
+		participantRepo := &mockParticipantRepository{
+			deleteAllFunc: func(ctx context.Context, recruitID RecruitID) error {
+				t.Error("DeleteAll() should not be called")
+				return nil
+			},
+		}
+		uc := NewRecruitUsecase(recruitRepo, participantRepo, &mockUnitOfWork{})
+
+		if err := uc.Delete(ctx, "channel-1", "message-1", "author-1"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if updated == nil || updated.Status != RecruitStatusClosed || updated.CloseReason != CloseReasonDeleted {
+			t.Errorf("updated = %+v, want closed with reason %v", updated, CloseReasonDeleted)
+		}
+	})
+
+	t.Run("作成者以外は削除できない", func(t *testing.T) {
+		recruitRepo := newWaitlistRecruitRepository(5)
+		recruitRepo.updateFunc = func(ctx context.Context, state *RecruitState) error {
+			t.Error("Update() should not be called")
+			return nil
+		}
+		uc := NewRecruitUsecase(recruitRepo, &mockParticipantRepository{}, &mockUnitOfWork{})
+
+		if err := uc.Delete(ctx, "channel-1", "message-1", "user-1"); !errors.Is(err, ErrNotAuthor) {
+			t.Errorf("Delete() error = %v, want ErrNotAuthor", err)
+		}
+	})
+}
+
+func TestRecruitUsecase_GetOwned(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		actorID UserID
+		wantErr error
+	}{
+		{name: "作成者は取得できる", actorID: "author-1"},
+		{name: "作成者以外は取得できない", actorID: "user-1", wantErr: ErrNotAuthor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &participantStore{}
+			store.add("author-1", ParticipantStatusJoined)
+			store.add("user-1", ParticipantStatusJoined)
+			uc := NewRecruitUsecase(newWaitlistRecruitRepository(5), store.repository(), &mockUnitOfWork{})
+
+			view, err := uc.GetOwned(ctx, "channel-1", "message-1", tt.actorID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetOwned() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && len(view.JoinedUsers) != 2 {
+				t.Errorf("GetOwned().JoinedUsers = %v, want 2 users", view.JoinedUsers)
+			}
+		})
+	}
+}
+
+func TestRecruitUsecase_JoinRoleRestricted(t *testing.T) {
+	ctx := context.Background()
+
+	newRepo := func() *mockRecruitRepository {
+		recruitRepo := newWaitlistRecruitRepository(5)
+		getByMessage := recruitRepo.getByMessageFunc
+		recruitRepo.getByMessageFunc = func(ctx context.Context, channelID ChannelID, messageID MessageID) (*RecruitState, error) {
+			state, err := getByMessage(ctx, channelID, messageID)
+			state.RoleID = "role-1"
+			state.RoleRestricted = true
+			return state, err
+		}
+		return recruitRepo
+	}
+
+	t.Run("対象ロールを持つメンバーは参加できる", func(t *testing.T) {
+		store := &participantStore{}
+		store.add("author-1", ParticipantStatusJoined)
+		uc := NewRecruitUsecase(newRepo(), store.repository(), &mockUnitOfWork{})
+
+		result, err := uc.Join(ctx, "channel-1", "message-1", "user-1", []RoleID{"role-2", "role-1"})
+		if err != nil {
+			t.Fatalf("Join() error = %v", err)
+		}
+		if result.CurrentStatus != ParticipantStatusJoined {
+			t.Errorf("CurrentStatus = %v, want %v", result.CurrentStatus, ParticipantStatusJoined)
+		}
+	})
+
+	t.Run("対象ロールを持たないメンバーは参加できない", func(t *testing.T) {
+		store := &participantStore{}
+		store.add("author-1", ParticipantStatusJoined)
+		uc := NewRecruitUsecase(newRepo(), store.repository(), &mockUnitOfWork{})
+
+		_, err := uc.Join(ctx, "channel-1", "message-1", "user-1", []RoleID{"role-2"})
+		if !errors.Is(err, ErrRoleRequired) {
+			t.Errorf("Join() error = %v, want ErrRoleRequired", err)
+		}
+		if len(store.participants) != 1 {
+			t.Errorf("participants length = %v, want 1", len(store.participants))
+		}
+	})
+
+	t.Run("対象ロールを持たなくても辞退はできる", func(t *testing.T) {
+		store := &participantStore{}
+		store.add("author-1", ParticipantStatusJoined)
+		uc := NewRecruitUsecase(newRepo(), store.repository(), &mockUnitOfWork{})
+
+		if _, err := uc.Decline(ctx, "channel-1", "message-1", "user-1"); err != nil {
+			t.Errorf("Decline() error = %v", err)
+		}
+	})
+}
+
+func TestRecruitUsecase_ListOpened(t *testing.T) {
+	ctx := context.Background()
+
+	recruitRepo := &mockRecruitRepository{
+		listOpenedFunc: func(ctx context.Context, guildID GuildID, channelID ChannelID) ([]*RecruitState, error) {
+			if guildID != "guild-1" || channelID != "channel-1" {
+				t.Errorf("ListOpened() args = %v, %v, want guild-1, channel-1", guildID, channelID)
+			}
+			return []*RecruitState{
+				{ID: 1, MaxCapacity: 3, Status: RecruitStatusOpened},
+				{ID: 2, MaxCapacity: 1, Status: RecruitStatusStarted},
+			}, nil
+		},
+	}
+	participantRepo := &mockParticipantRepository{
+		listFunc: func(ctx context.Context, recruitID RecruitID) ([]Participant, error) {
+			return []Participant{
+				{RecruitID: recruitID, UserID: "author-1", Status: ParticipantStatusJoined},
+				{RecruitID: recruitID, UserID: "user-1", Status: ParticipantStatusJoined},
+			}, nil
+		},
+	}
+	uc := NewRecruitUsecase(recruitRepo, participantRepo, &mockUnitOfWork{})
+
+	views, err := uc.ListOpened(ctx, "guild-1", "channel-1")
+	if err != nil {
+		t.Fatalf("ListOpened() error = %v", err)
+	}
+
+	if len(views) != 2 {
+		t.Fatalf("ListOpened() length = %v, want 2", len(views))
+	}
+	if views[0].RemainingSlots() != 2 {
+		t.Errorf("views[0].RemainingSlots() = %v, want 2", views[0].RemainingSlots())
+	}
+	if !views[1].IsFull() {
+		t.Error("views[1].IsFull() = false, want true")
+	}
 }
@@ -0,0 +1,155 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+)
+
+type closer struct {
+	name  string
+	close func() error
+}
+
+// Manager は処理中のインタラクションやバックグラウンド処理を管理し、
+// 停止時に完了を待ってからリソースを閉じる
+type Manager struct {
+	mu       sync.Mutex
+	stopping bool
+	inflight int
+	wg       sync.WaitGroup
+	closers  []closer
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	// taskCtx は待機の期限を過ぎた場合にキャンセルされる
+	taskCtx context.Context
+	abort   context.CancelFunc
+}
+
+func NewManager() *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	taskCtx, abort := context.WithCancel(context.Background())
+	return &Manager{
+		ctx:     ctx,
+		cancel:  cancel,
+		taskCtx: taskCtx,
+		abort:   abort,
+	}
+}
+
+// Context は停止処理の開始時にキャンセルされるコンテキストを返す
+// バックグラウンド処理の終了の合図に使用する
+func (m *Manager) Context() context.Context {
+	return m.ctx
+}
+
+// TaskContext はインタラクションなど処理中の処理に渡すコンテキストを返す
+// 停止処理の開始ではキャンセルされず、完了を待つ期限を過ぎた場合にリソースを閉じる前にキャンセルされる
+func (m *Manager) TaskContext() context.Context {
+	return m.taskCtx
+}
+
+// Acquire は処理の開始を登録する。停止処理の開始後はokがfalseになり、処理を受け付けない
+// 処理が完了したら返されたreleaseを呼び出すこと
+func (m *Manager) Acquire() (release func(), ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stopping {
+		return nil, false
+	}
+	m.inflight++
+	m.wg.Add(1)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mu.Lock()
+			m.inflight--
+			m.mu.Unlock()
+			m.wg.Done()
+		})
+	}, true
+}
+
+// Go はfnをゴルーチンで実行し、停止時に完了を待つ
+// 停止処理の開始後は実行しない
+func (m *Manager) Go(fn func()) {
+	release, ok := m.Acquire()
+	if !ok {
+		return
+	}
+	go func() {
+		defer release()
+		fn()
+	}()
+}
+
+// OnClose は停止時に閉じるリソースを登録する
+// 後から登録したリソースが先に登録したリソースを使用している場合を考慮し、登録と逆の順に閉じる
+func (m *Manager) OnClose(name string, close func() error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closers = append(m.closers, closer{name: name, close: close})
+}
+
+// Shutdown は新しい処理の受け付けを停止し、処理中の処理の完了をctxの期限まで待ってからリソースを閉じる
+// 期限までに完了しなかった場合もリソースは閉じ、エラーを返す
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if m.stopping {
+		m.mu.Unlock()
+		return errors.New("shutdown already started")
+	}
+	m.stopping = true
+	closers := m.closers
+	m.mu.Unlock()
+
+	m.cancel()
+
+	var errs []error
+	if err := m.wait(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	m.abort()
+
+	for i := len(closers) - 1; i >= 0; i-- {
+		c := closers[i]
+		if err := c.close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close %s: %w", c.name, err))
+			continue
+		}
+		log.Printf("[SHUTDOWN] closed %s", c.name)
+	}
+	return errors.Join(errs...)
+}
+
+// wait は処理中の処理が全て完了するかctxの期限が来るまで待つ
+func (m *Manager) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	m.mu.Lock()
+	inflight := m.inflight
+	m.mu.Unlock()
+	if inflight > 0 {
+		log.Printf("[SHUTDOWN] waiting for %d in-flight tasks", inflight)
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		m.mu.Lock()
+		inflight := m.inflight
+		m.mu.Unlock()
+		return fmt.Errorf("gave up waiting for %d in-flight tasks: %w", inflight, ctx.Err())
+	}
+}
@@ -0,0 +1,203 @@
+package shutdown_test
+
+import (
+	"at-bot/internal/db/sqlite"
+	"at-bot/internal/discord"
+	"at-bot/internal/shutdown"
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// slowListener はトランザクションの途中で待機するインタラクション
+type slowListener struct {
+	handle func(ctx context.Context) error
+}
+
+func (l *slowListener) InteractionType() discordgo.InteractionType {
+	return discordgo.InteractionApplicationCommand
+}
+
+func (l *slowListener) InteractionID() string {
+	return ""
+}
+
+func (l *slowListener) MatchInteractionID(string) bool {
+	return true
+}
+
+func (l *slowListener) Handle(ctx context.Context, _ discord.Client, _ *discordgo.Interaction) error {
+	return l.handle(ctx)
+}
+
+func newInteraction(id string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:   id,
+			Type: discordgo.InteractionApplicationCommand,
+			Data: discordgo.ApplicationCommandInteractionData{Name: "slow"},
+		},
+	}
+}
+
+func TestManager_Shutdown_DrainsInFlightTransactions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.db")
+	db, err := sqlite.InitDB(sqlite.DefaultConfig(path))
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	if _, err := db.Exec("CREATE TABLE events (id INTEGER PRIMARY KEY, step TEXT NOT NULL)"); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	manager := shutdown.NewManager()
+	manager.OnClose("database", db.Close)
+
+	txManager := sqlite.NewTxManager(db)
+	started := make(chan struct{})
+	var calls int
+	var mu sync.Mutex
+	dispatcher := &discord.InteractionDispatcher{
+		Listeners: []discord.InteractionListener{
+			&slowListener{handle: func(ctx context.Context) error {
+				mu.Lock()
+				calls++
+				mu.Unlock()
+				return txManager.Do(ctx, func(ctx context.Context) error {
+					executor := sqlite.GetExecutor(ctx, db)
+					if _, err := executor.ExecContext(ctx, "INSERT INTO events (step) VALUES ('begin')"); err != nil {
+						return err
+					}
+					close(started)
+					// 停止処理の開始後もトランザクションが続く
+					time.Sleep(200 * time.Millisecond)
+					_, err := executor.ExecContext(ctx, "INSERT INTO events (step) VALUES ('end')")
+					return err
+				})
+			}},
+		},
+		Gate:        manager,
+		BaseContext: manager.TaskContext(),
+	}
+
+	handled := make(chan struct{})
+	go func() {
+		dispatcher.OnInteractionCreate(nil, newInteraction("interaction-1"))
+		close(handled)
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := manager.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	// Shutdownが返った時点で処理中のインタラクションは完了している
+	select {
+	case <-handled:
+	default:
+		t.Fatal("Shutdown() returned before in-flight interaction finished")
+	}
+
+	// 停止処理の開始後のインタラクションは受け付けない
+	dispatcher.OnInteractionCreate(nil, newInteraction("interaction-2"))
+	if calls != 1 {
+		t.Errorf("listener calls = %d, want 1", calls)
+	}
+
+	// データベースは閉じられている
+	if err := db.Ping(); err == nil {
+		t.Error("Ping() after Shutdown() error = nil, want error")
+	}
+
+	reopened, err := sqlite.InitDB(sqlite.DefaultConfig(path))
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	defer reopened.Close()
+
+	rows, err := reopened.Query("SELECT step FROM events ORDER BY id")
+	if err != nil {
+		t.Fatalf("failed to query events: %v", err)
+	}
+	defer rows.Close()
+	var steps []string
+	for rows.Next() {
+		var step string
+		if err := rows.Scan(&step); err != nil {
+			t.Fatalf("failed to scan event: %v", err)
+		}
+		steps = append(steps, step)
+	}
+	if want := []string{"begin", "end"}; !slices.Equal(steps, want) {
+		t.Errorf("committed steps = %v, want %v", steps, want)
+	}
+}
+
+func TestManager_Shutdown_Timeout(t *testing.T) {
+	manager := shutdown.NewManager()
+	var closed []string
+	for _, name := range []string{"database", "discord session"} {
+		manager.OnClose(name, func() error {
+			// 処理中の処理はリソースを閉じる前に中断される
+			if manager.TaskContext().Err() == nil {
+				t.Errorf("%s closed before TaskContext() is canceled", name)
+			}
+			closed = append(closed, name)
+			return nil
+		})
+	}
+
+	release, ok := manager.Acquire()
+	if !ok {
+		t.Fatal("Acquire() ok = false, want true")
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := manager.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() error = %v, want DeadlineExceeded", err)
+	}
+
+	// 期限を過ぎても登録と逆の順にリソースを閉じる
+	if want := []string{"discord session", "database"}; !slices.Equal(closed, want) {
+		t.Errorf("closed = %v, want %v", closed, want)
+	}
+}
+
+func TestManager_Go(t *testing.T) {
+	manager := shutdown.NewManager()
+
+	finished := make(chan struct{})
+	manager.Go(func() {
+		// 停止の合図を受けてから終了する
+		<-manager.Context().Done()
+		time.Sleep(50 * time.Millisecond)
+		close(finished)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := manager.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	select {
+	case <-finished:
+	default:
+		t.Error("Shutdown() returned before background task finished")
+	}
+
+	if _, ok := manager.Acquire(); ok {
+		t.Error("Acquire() after Shutdown() ok = true, want false")
+	}
+}
@@ -0,0 +1,70 @@
+package stats
+
+import "at-bot/internal/recruit"
+
+// UserStats はギルド内のユーザーの参加履歴の集計
+// 他のユーザーが作成した募集について、参加は現在参加している募集、
+// 辞退は一度でも辞退した募集、キャンセルは参加からキャンセルした回数を数える
+type UserStats struct {
+	GuildID  recruit.GuildID
+	UserID   recruit.UserID
+	Opened   int
+	Joined   int
+	Declined int
+	Canceled int
+	// CoParticipants は同じ募集に参加した回数が多い順に並ぶ
+	CoParticipants []CoParticipant
+}
+
+// CancelRate は参加表明のうちキャンセルした割合を返す
+// 参加表明がない場合はfalseを返す
+func (s *UserStats) CancelRate() (float64, bool) {
+	return cancelRate(s.Joined, s.Canceled)
+}
+
+// CoParticipant は同じ募集に参加したユーザーと回数
+type CoParticipant struct {
+	UserID recruit.UserID
+	Count  int
+}
+
+// GuildStats はギルド全体の参加履歴の集計
+type GuildStats struct {
+	GuildID  recruit.GuildID
+	Recruits int
+	Joined   int
+	Declined int
+	Canceled int
+	// TopMembers は参加回数が多い順に並ぶ
+	TopMembers []MemberStats
+	// TopPairs は同じ募集に参加した回数が多い順に並ぶ
+	TopPairs []CoParticipantPair
+}
+
+// MemberStats はギルド内のメンバーごとの参加とキャンセルの回数
+type MemberStats struct {
+	UserID   recruit.UserID
+	Joined   int
+	Canceled int
+}
+
+// CancelRate は参加表明のうちキャンセルした割合を返す
+func (s *MemberStats) CancelRate() (float64, bool) {
+	return cancelRate(s.Joined, s.Canceled)
+}
+
+// CoParticipantPair は同じ募集に参加したユーザーの組と回数
+type CoParticipantPair struct {
+	UserA recruit.UserID
+	UserB recruit.UserID
+	Count int
+}
+
+// cancelRate はキャンセルした参加が参加とキャンセルの合計に占める割合を返す
+func cancelRate(joined, canceled int) (float64, bool) {
+	total := joined + canceled
+	if total == 0 {
+		return 0, false
+	}
+	return float64(canceled) / float64(total), true
+}
@@ -0,0 +1,42 @@
+package stats
+
+import "testing"
+
+func TestUserStats_CancelRate(t *testing.T) {
+	tests := []struct {
+		name   string
+		stats  UserStats
+		want   float64
+		wantOK bool
+	}{
+		{
+			name:   "参加表明がない場合は算出しない",
+			stats:  UserStats{Declined: 2},
+			wantOK: false,
+		},
+		{
+			name:   "キャンセルがない場合は0",
+			stats:  UserStats{Joined: 3},
+			want:   0,
+			wantOK: true,
+		},
+		{
+			name:   "参加3回キャンセル1回の場合は25%",
+			stats:  UserStats{Joined: 3, Canceled: 1},
+			want:   0.25,
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.stats.CancelRate()
+			if ok != tt.wantOK {
+				t.Fatalf("CancelRate() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("CancelRate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
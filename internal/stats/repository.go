@@ -0,0 +1,15 @@
+package stats
+
+import (
+	"at-bot/internal/recruit"
+	"context"
+)
+
+// StatsRepository は募集と参加者の記録から統計を集計する
+// 作成者が削除しアーカイブした募集も集計対象に含める
+type StatsRepository interface {
+	// GetUserStats はユーザーの集計と、同じ募集に参加した回数が多いユーザーを最大limit件返す
+	GetUserStats(ctx context.Context, guildID recruit.GuildID, userID recruit.UserID, limit int) (*UserStats, error)
+	// GetGuildStats はギルドの集計と、参加回数の多いメンバーと組を最大limit件返す
+	GetGuildStats(ctx context.Context, guildID recruit.GuildID, limit int) (*GuildStats, error)
+}
@@ -0,0 +1,51 @@
+package stats
+
+import (
+	"at-bot/internal/recruit"
+	"at-bot/internal/uow"
+	"context"
+)
+
+// rankingLimit はランキング形式で表示する件数
+const rankingLimit = 5
+
+type StatsUsecase struct {
+	statsRepos StatsRepository
+	uow        uow.UnitOfWork
+}
+
+func NewStatsUsecase(statsRepos StatsRepository, uow uow.UnitOfWork) *StatsUsecase {
+	return &StatsUsecase{
+		statsRepos: statsRepos,
+		uow:        uow,
+	}
+}
+
+// UserStats はギルド内のユーザーの参加履歴の集計を返す
+func (uc *StatsUsecase) UserStats(
+	ctx context.Context,
+	guildID recruit.GuildID,
+	userID recruit.UserID,
+) (*UserStats, error) {
+	var stats *UserStats
+	err := uc.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		stats, err = uc.statsRepos.GetUserStats(ctx, guildID, userID, rankingLimit)
+		return err
+	})
+	return stats, err
+}
+
+// GuildStats はギルド全体の参加履歴の集計を返す
+func (uc *StatsUsecase) GuildStats(
+	ctx context.Context,
+	guildID recruit.GuildID,
+) (*GuildStats, error) {
+	var stats *GuildStats
+	err := uc.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		stats, err = uc.statsRepos.GetGuildStats(ctx, guildID, rankingLimit)
+		return err
+	})
+	return stats, err
+}